@@ -0,0 +1,212 @@
+package multi
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+// Level is a price level of a consolidated book.
+type Level struct {
+	Exchange string
+	// Price is the price of taking the level. It is RawPrice with the taker
+	// fee added for asks and subtracted for bids when the fee is known.
+	Price    float64
+	RawPrice float64
+	Amount   float64
+}
+
+// Book is the order book of a pair merged across exchanges. Asks are sorted
+// by ascending and bids by descending price.
+type Book struct {
+	Trading    string
+	Settlement string
+	Asks       []Level
+	Bids       []Level
+	// Errors are the exchanges left out of the book.
+	Errors Errors
+}
+
+// Consolidate merges the boards of a pair by exchange. The levels of an
+// exchange listed in fees are adjusted for its taker fee.
+func Consolidate(trading string, settlement string, boards map[string]*models.Board, fees map[string]private.TradeFee) *Book {
+	b := &Book{Trading: trading, Settlement: settlement, Errors: make(Errors)}
+	for name, board := range boards {
+		if board == nil {
+			continue
+		}
+		fee := fees[name].TakerFee
+		for _, v := range board.Asks {
+			b.Asks = append(b.Asks, Level{Exchange: name, Price: v.Price * (1 + fee), RawPrice: v.Price, Amount: v.Amount})
+		}
+		for _, v := range board.Bids {
+			b.Bids = append(b.Bids, Level{Exchange: name, Price: v.Price * (1 - fee), RawPrice: v.Price, Amount: v.Amount})
+		}
+	}
+	sort.SliceStable(b.Asks, func(i, j int) bool {
+		return b.Asks[i].Price < b.Asks[j].Price
+	})
+	sort.SliceStable(b.Bids, func(i, j int) bool {
+		return b.Bids[i].Price > b.Bids[j].Price
+	})
+	return b
+}
+
+// Venue is where an amount can be filled and at which average price.
+type Venue struct {
+	Exchange string
+	Price    float64
+}
+
+// BestVenueToBuy returns the exchange where buying amount on its own costs
+// the least on average.
+func (b *Book) BestVenueToBuy(amount float64) (Venue, error) {
+	return bestVenue(b.Asks, amount, func(p, best float64) bool { return p < best })
+}
+
+// BestVenueToSell returns the exchange where selling amount on its own
+// yields the most on average.
+func (b *Book) BestVenueToSell(amount float64) (Venue, error) {
+	return bestVenue(b.Bids, amount, func(p, best float64) bool { return p > best })
+}
+
+func bestVenue(levels []Level, amount float64, better func(p, best float64) bool) (Venue, error) {
+	if amount <= 0 {
+		return Venue{}, errors.Errorf("invalid amount %v", amount)
+	}
+	remaining := make(map[string]float64)
+	sums := make(map[string]float64)
+	var best Venue
+	for _, l := range levels {
+		r, ok := remaining[l.Exchange]
+		if !ok {
+			r = amount
+		}
+		if r <= 0 {
+			continue
+		}
+		filled := l.Amount
+		if filled > r {
+			filled = r
+		}
+		sums[l.Exchange] += filled * l.Price
+		remaining[l.Exchange] = r - filled
+		if r-filled > 0 {
+			continue
+		}
+		// the first exchange to fill the amount doesn't always have the
+		// best average, so keep comparing
+		p := sums[l.Exchange] / amount
+		if best.Exchange == "" || better(p, best.Price) {
+			best = Venue{Exchange: l.Exchange, Price: p}
+		}
+	}
+	if best.Exchange == "" {
+		return Venue{}, errors.Errorf("no exchange has %v on its book", amount)
+	}
+	return best, nil
+}
+
+// Mid returns the middle of the best bid and ask.
+func (b *Book) Mid() (float64, error) {
+	if len(b.Asks) == 0 || len(b.Bids) == 0 {
+		return 0, errors.New("the book has no asks or bids")
+	}
+	return (b.Asks[0].Price + b.Bids[0].Price) / 2, nil
+}
+
+// Depth is the amount on a book within some distance of its mid.
+type Depth struct {
+	Asks float64
+	Bids float64
+	// ByExchange are the amounts of asks and bids of each exchange.
+	ByExchange map[string]Depth
+}
+
+// Depth returns the amount of the asks and bids within bps basis points of
+// the mid.
+func (b *Book) Depth(bps float64) (Depth, error) {
+	mid, err := b.Mid()
+	if err != nil {
+		return Depth{}, err
+	}
+	d := Depth{ByExchange: make(map[string]Depth)}
+	for _, l := range b.Asks {
+		if l.Price > mid*(1+bps/10000) {
+			break
+		}
+		d.Asks += l.Amount
+		e := d.ByExchange[l.Exchange]
+		e.Asks += l.Amount
+		d.ByExchange[l.Exchange] = e
+	}
+	for _, l := range b.Bids {
+		if l.Price < mid*(1-bps/10000) {
+			break
+		}
+		d.Bids += l.Amount
+		e := d.ByExchange[l.Exchange]
+		e.Bids += l.Amount
+		d.ByExchange[l.Exchange] = e
+	}
+	return d, nil
+}
+
+type TradeFeeResults struct {
+	TradeFees map[string]private.TradeFee
+	Errors    Errors
+}
+
+// TradeFeeRate fetches the trade fees of the pair from every private client.
+func (m *MultiClient) TradeFeeRate(ctx context.Context, trading string, settlement string) *TradeFeeResults {
+	res := &TradeFeeResults{TradeFees: make(map[string]private.TradeFee)}
+	values, errs := m.each(ctx, privateNames(m.privates), func(name string) (interface{}, error) {
+		return m.privates[name].TradeFeeRate(trading, settlement)
+	})
+	for name, v := range values {
+		res.TradeFees[name] = v.(private.TradeFee)
+	}
+	res.Errors = errs
+	return res
+}
+
+// Book fetches the board of the pair from every public client and merges
+// them. With fees, the trade fees are fetched from the private clients at
+// the same time, and exchanges whose fee is unknown are left out rather than
+// compared at a price they don't offer.
+func (m *MultiClient) Book(ctx context.Context, trading string, settlement string, fees bool) *Book {
+	if !fees {
+		boards := m.Board(ctx, trading, settlement)
+		b := Consolidate(trading, settlement, boards.Boards, nil)
+		b.Errors = boards.Errors
+		return b
+	}
+	var boards *BoardResults
+	var rates *TradeFeeResults
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rates = m.TradeFeeRate(ctx, trading, settlement)
+	}()
+	boards = m.Board(ctx, trading, settlement)
+	<-done
+
+	errs := boards.Errors
+	for name := range boards.Boards {
+		if _, ok := rates.TradeFees[name]; ok {
+			continue
+		}
+		if err, ok := rates.Errors[name]; ok {
+			errs[name] = errors.Wrap(err, "failed to get trade fee")
+		} else {
+			errs[name] = errors.New("no private client to get the trade fee")
+		}
+		delete(boards.Boards, name)
+	}
+	b := Consolidate(trading, settlement, boards.Boards, rates.TradeFees)
+	b.Errors = errs
+	return b
+}
@@ -0,0 +1,227 @@
+// Package multi calls several exchanges at once.
+//
+//	m := multi.New(3 * time.Second)
+//	m.AddPublic("binance", binance)
+//	m.AddPublic("huobi", huobi)
+//	res := m.Rate(ctx, "ETH", "BTC")
+//	for name, err := range res.Errors { ... }
+//
+// Every exchange has its own deadline, so a slow or failing one never holds
+// back the others: its error is returned along with the results of the rest.
+// Client methods don't take a context, so a call which missed its deadline
+// keeps running in the background and its result is dropped.
+package multi
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+// MultiClient fans calls out to the clients of several exchanges. Clients
+// are added before the first call; calls are safe for concurrent use.
+type MultiClient struct {
+	// Timeout is the deadline of each exchange. With 0 only the deadline of
+	// the context applies.
+	Timeout time.Duration
+	// Timeouts overrides Timeout for some exchanges.
+	Timeouts map[string]time.Duration
+
+	publics  map[string]public.PublicClient
+	privates map[string]private.PrivateClient
+}
+
+func New(timeout time.Duration) *MultiClient {
+	return &MultiClient{
+		Timeout:  timeout,
+		Timeouts: make(map[string]time.Duration),
+		publics:  make(map[string]public.PublicClient),
+		privates: make(map[string]private.PrivateClient),
+	}
+}
+
+// AddPublic adds the public client of an exchange under name.
+func (m *MultiClient) AddPublic(name string, cli public.PublicClient) {
+	m.publics[name] = cli
+}
+
+// AddPrivate adds the private client of an exchange under name.
+func (m *MultiClient) AddPrivate(name string, cli private.PrivateClient) {
+	m.privates[name] = cli
+}
+
+// Errors are the errors of the exchanges which failed, by name.
+type Errors map[string]error
+
+func (e Errors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, name+": "+e[name].Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Err returns e, or nil if no exchange failed.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+type RateResults struct {
+	Rates  map[string]float64
+	Errors Errors
+}
+
+// Rate fetches the rate of the pair from every public client.
+func (m *MultiClient) Rate(ctx context.Context, trading string, settlement string) *RateResults {
+	res := &RateResults{Rates: make(map[string]float64)}
+	values, errs := m.each(ctx, publicNames(m.publics), func(name string) (interface{}, error) {
+		return m.publics[name].Rate(trading, settlement)
+	})
+	for name, v := range values {
+		res.Rates[name] = v.(float64)
+	}
+	res.Errors = errs
+	return res
+}
+
+type OrderBookTickResults struct {
+	OrderBookTicks map[string]map[string]map[string]models.OrderBookTick
+	Errors         Errors
+}
+
+// OrderBookTickMap fetches the best prices of every pair from every public
+// client.
+func (m *MultiClient) OrderBookTickMap(ctx context.Context) *OrderBookTickResults {
+	res := &OrderBookTickResults{OrderBookTicks: make(map[string]map[string]map[string]models.OrderBookTick)}
+	values, errs := m.each(ctx, publicNames(m.publics), func(name string) (interface{}, error) {
+		return m.publics[name].OrderBookTickMap()
+	})
+	for name, v := range values {
+		res.OrderBookTicks[name] = v.(map[string]map[string]models.OrderBookTick)
+	}
+	res.Errors = errs
+	return res
+}
+
+type BoardResults struct {
+	Boards map[string]*models.Board
+	Errors Errors
+}
+
+// Board fetches the board of the pair from every public client.
+func (m *MultiClient) Board(ctx context.Context, trading string, settlement string) *BoardResults {
+	res := &BoardResults{Boards: make(map[string]*models.Board)}
+	values, errs := m.each(ctx, publicNames(m.publics), func(name string) (interface{}, error) {
+		return m.publics[name].Board(trading, settlement)
+	})
+	for name, v := range values {
+		res.Boards[name] = v.(*models.Board)
+	}
+	res.Errors = errs
+	return res
+}
+
+type BalanceResults struct {
+	Balances map[string]map[string]float64
+	Errors   Errors
+}
+
+// Balances fetches the balances of every private client.
+func (m *MultiClient) Balances(ctx context.Context) *BalanceResults {
+	res := &BalanceResults{Balances: make(map[string]map[string]float64)}
+	values, errs := m.each(ctx, privateNames(m.privates), func(name string) (interface{}, error) {
+		return m.privates[name].Balances()
+	})
+	for name, v := range values {
+		res.Balances[name] = v.(map[string]float64)
+	}
+	res.Errors = errs
+	return res
+}
+
+func publicNames(clients map[string]public.PublicClient) []string {
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	return names
+}
+
+func privateNames(clients map[string]private.PrivateClient) []string {
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	return names
+}
+
+type result struct {
+	name  string
+	value interface{}
+	err   error
+}
+
+// each calls call for every exchange concurrently and returns once every
+// call returned or missed its deadline.
+func (m *MultiClient) each(ctx context.Context, names []string, call func(name string) (interface{}, error)) (map[string]interface{}, Errors) {
+	ch := make(chan result, len(names))
+	for _, name := range names {
+		go func(name string) {
+			v, err := m.call(ctx, name, call)
+			ch <- result{name: name, value: v, err: err}
+		}(name)
+	}
+	values := make(map[string]interface{}, len(names))
+	errs := make(Errors)
+	for range names {
+		r := <-ch
+		if r.err != nil {
+			errs[r.name] = r.err
+			continue
+		}
+		values[r.name] = r.value
+	}
+	return values, errs
+}
+
+func (m *MultiClient) call(ctx context.Context, name string, call func(name string) (interface{}, error)) (interface{}, error) {
+	timeout, ok := m.Timeouts[name]
+	if !ok {
+		timeout = m.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: errors.Errorf("panic: %v", r)}
+			}
+		}()
+		v, err := call(name)
+		done <- result{value: v, err: err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "%s did not answer in time", name)
+	}
+}
@@ -0,0 +1,176 @@
+package multi
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/api/public/mocks"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+func TestRate(t *testing.T) {
+	fast := new(mocks.PublicClient)
+	fast.On("Rate", "ETH", "BTC").Return(0.03, nil)
+	slow := new(mocks.PublicClient)
+	slow.On("Rate", "ETH", "BTC").Return(0.031, nil).After(time.Second)
+	broken := new(mocks.PublicClient)
+	broken.On("Rate", "ETH", "BTC").Return(0.0, errors.New("503 Service Unavailable"))
+	panicking := new(mocks.PublicClient)
+	panicking.On("Rate", "ETH", "BTC").Run(func(mock.Arguments) { panic("index out of range") })
+
+	m := New(time.Second)
+	m.Timeouts["slow"] = 50 * time.Millisecond
+	m.AddPublic("fast", fast)
+	m.AddPublic("slow", slow)
+	m.AddPublic("broken", broken)
+	m.AddPublic("panicking", panicking)
+
+	start := time.Now()
+	res := m.Rate(context.Background(), "ETH", "BTC")
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("took %s; want the slow exchange to be left behind", d)
+	}
+	if len(res.Rates) != 1 || res.Rates["fast"] != 0.03 {
+		t.Errorf("got %v; want the rate of fast", res.Rates)
+	}
+	if len(res.Errors) != 3 {
+		t.Fatalf("got %v; want errors of slow, broken and panicking", res.Errors)
+	}
+	if errors.Cause(res.Errors["slow"]) != context.DeadlineExceeded {
+		t.Errorf("got %v; want the deadline of slow", res.Errors["slow"])
+	}
+	if msg := res.Errors.Err().Error(); !strings.HasPrefix(msg, "broken: 503") {
+		t.Errorf("got %q; want the errors sorted by exchange", msg)
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	slow := new(mocks.PublicClient)
+	slow.On("Board", "ETH", "BTC").Return(&models.Board{}, nil).After(time.Second)
+	m := New(0)
+	m.AddPublic("slow", slow)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	res := m.Board(ctx, "ETH", "BTC")
+	if len(res.Boards) != 0 || res.Errors["slow"] == nil {
+		t.Errorf("got %v, %v; want the deadline of ctx", res.Boards, res.Errors)
+	}
+}
+
+func TestBalances(t *testing.T) {
+	ok := new(private.MockPrivateClient)
+	ok.On("Balances").Return(map[string]float64{"BTC": 1.5}, nil)
+	banned := new(private.MockPrivateClient)
+	banned.On("Balances").Return(nil, errors.New("ip banned"))
+
+	m := New(time.Second)
+	m.AddPrivate("bitflyer", ok)
+	m.AddPrivate("binance", banned)
+	res := m.Balances(context.Background())
+	if res.Balances["bitflyer"]["BTC"] != 1.5 {
+		t.Errorf("got %v; want the balances of bitflyer", res.Balances)
+	}
+	if _, ok := res.Balances["binance"]; ok || res.Errors["binance"] == nil {
+		t.Errorf("got %v; want the error of binance", res.Errors)
+	}
+	if (Errors{}).Err() != nil {
+		t.Error("want no error when every exchange answered")
+	}
+}
+
+func bars(typ models.OrderType, levels ...float64) []models.BoardBar {
+	var bs []models.BoardBar
+	for i := 0; i < len(levels); i += 2 {
+		bs = append(bs, models.BoardBar{Type: typ, Price: levels[i], Amount: levels[i+1]})
+	}
+	return bs
+}
+
+func TestConsolidate(t *testing.T) {
+	boards := map[string]*models.Board{
+		// cheapest first level, but thin
+		"binance": {Asks: bars(models.Ask, 100, 1, 110, 10), Bids: bars(models.Bid, 99, 1)},
+		"huobi":   {Asks: bars(models.Ask, 101, 10), Bids: bars(models.Bid, 98, 5)},
+		"okex":    {Asks: bars(models.Ask, 100.5, 2), Bids: bars(models.Bid, 99.5, 2)},
+	}
+	b := Consolidate("ETH", "BTC", boards, nil)
+	if len(b.Asks) != 4 || b.Asks[0].Exchange != "binance" || b.Asks[1].Exchange != "okex" {
+		t.Errorf("got %+v; want asks sorted by price and tagged", b.Asks)
+	}
+	if b.Bids[0].Exchange != "okex" || b.Bids[2].Price != 98 {
+		t.Errorf("got %+v; want bids sorted by descending price", b.Bids)
+	}
+
+	v, err := b.BestVenueToBuy(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Exchange != "huobi" || v.Price != 101 {
+		t.Errorf("got %+v; want huobi at 101", v)
+	}
+	if v, err := b.BestVenueToBuy(1); err != nil || v.Exchange != "binance" {
+		t.Errorf("got %+v, %v; want binance for a small amount", v, err)
+	}
+	if _, err := b.BestVenueToBuy(100); err == nil {
+		t.Error("want an error when no exchange has the amount")
+	}
+	if v, err := b.BestVenueToSell(1); err != nil || v.Exchange != "okex" {
+		t.Errorf("got %+v, %v; want okex", v, err)
+	}
+
+	// mid is (100 + 99.5) / 2 = 99.75, 100 bps is 98.7525 to 100.7475
+	d, err := b.Depth(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Asks != 3 || d.Bids != 3 {
+		t.Errorf("got %+v; want 3 asks and 3 bids", d)
+	}
+	if d.ByExchange["okex"].Asks != 2 || d.ByExchange["binance"].Bids != 1 {
+		t.Errorf("got %+v; want the depth of each exchange", d.ByExchange)
+	}
+
+	fees := map[string]private.TradeFee{"binance": {TakerFee: 0.02}}
+	b = Consolidate("ETH", "BTC", boards, fees)
+	if b.Asks[0].Exchange != "okex" || b.Asks[1].Price != 101 || b.Asks[1].RawPrice != 101 {
+		t.Errorf("got %+v; want binance behind okex and huobi after its fee", b.Asks)
+	}
+}
+
+func TestBookWithFees(t *testing.T) {
+	board := &models.Board{Asks: bars(models.Ask, 100, 1), Bids: bars(models.Bid, 99, 1)}
+	binance := new(mocks.PublicClient)
+	binance.On("Board", "ETH", "BTC").Return(board, nil)
+	huobi := new(mocks.PublicClient)
+	huobi.On("Board", "ETH", "BTC").Return(board.Copy(), nil)
+	okex := new(mocks.PublicClient)
+	okex.On("Board", "ETH", "BTC").Return(board.Copy(), nil)
+	binanceKeys := new(private.MockPrivateClient)
+	binanceKeys.On("TradeFeeRate", "ETH", "BTC").Return(private.TradeFee{TakerFee: 0.001}, nil)
+	huobiKeys := new(private.MockPrivateClient)
+	huobiKeys.On("TradeFeeRate", "ETH", "BTC").Return(private.TradeFee{}, errors.New("invalid signature"))
+
+	m := New(time.Second)
+	m.AddPublic("binance", binance)
+	m.AddPublic("huobi", huobi)
+	m.AddPublic("okex", okex)
+	m.AddPrivate("binance", binanceKeys)
+	m.AddPrivate("huobi", huobiKeys)
+
+	b := m.Book(context.Background(), "ETH", "BTC", true)
+	if len(b.Asks) != 1 || b.Asks[0].Exchange != "binance" || b.Asks[0].Price != 100.1 {
+		t.Errorf("got %+v; want only binance with its fee", b.Asks)
+	}
+	if len(b.Errors) != 2 || b.Errors["huobi"] == nil || b.Errors["okex"] == nil {
+		t.Errorf("got %v; want huobi and okex left out", b.Errors)
+	}
+	if b := m.Book(context.Background(), "ETH", "BTC", false); len(b.Asks) != 3 || len(b.Errors) != 0 {
+		t.Errorf("got %+v, %v; want every exchange without fees", b.Asks, b.Errors)
+	}
+}
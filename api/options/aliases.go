@@ -0,0 +1,24 @@
+package options
+
+import (
+	"github.com/xuyangcn/go-exchange-client/symbols"
+)
+
+// WithAliases adds asset aliases of the exchange on top of symbols.Defaults,
+// e.g. {"XBT": "BTC"}. Clients built by NewClient take and return canonical
+// names; mapping an asset to itself drops its default alias.
+func WithAliases(a symbols.Aliases) Option {
+	return func(o *Options) {
+		o.Aliases = o.Aliases.Merge(a)
+	}
+}
+
+// ResolveAliases returns the aliases of the exchange with opts applied,
+// without building the rest of the options.
+func ResolveAliases(exchange string, opts ...Option) symbols.Aliases {
+	o := Options{Exchange: exchange, Aliases: symbols.Defaults[exchange]}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.Aliases
+}
@@ -0,0 +1,58 @@
+package options
+
+import (
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/cache"
+)
+
+// WithCacheStore keeps the market data of the client in s, e.g. a
+// cache.RemoteStore shared by several processes.
+func WithCacheStore(s cache.Store) Option {
+	return func(o *Options) {
+		o.CacheStore = s
+	}
+}
+
+// WithCacheTTL sets how long the data of one endpoint of the cache is fresh,
+// overriding WithRateCacheDuration and WithBoardCacheDuration for it.
+// Endpoints are rate, order_book_tick, board and board_ticker.
+func WithCacheTTL(endpoint string, ttl time.Duration) Option {
+	return func(o *Options) {
+		ttls := make(map[string]time.Duration, len(o.CacheTTL)+1)
+		for k, v := range o.CacheTTL {
+			ttls[k] = v
+		}
+		ttls[endpoint] = ttl
+		o.CacheTTL = ttls
+	}
+}
+
+// WithStaleWhileRevalidate keeps serving expired market data for up to d
+// while it is refreshed in the background.
+func WithStaleWhileRevalidate(d time.Duration) Option {
+	return func(o *Options) {
+		o.StaleWhileRevalidate = d
+	}
+}
+
+// NewCache returns the market data cache of a public client. Rates, volumes
+// and order book ticks are fresh for RateCacheDuration and boards for
+// BoardCacheDuration unless CacheTTL says otherwise.
+func (o *Options) NewCache() *cache.Cache {
+	c := cache.New(o.CacheStore)
+	c.Namespace = o.Exchange + ":" + string(o.Environment)
+	c.TTL["rate"] = o.RateCacheDuration
+	c.TTL["order_book_tick"] = o.RateCacheDuration
+	c.TTL["board"] = o.BoardCacheDuration
+	c.TTL["board_ticker"] = o.BoardCacheDuration
+	for endpoint, ttl := range o.CacheTTL {
+		c.TTL[endpoint] = ttl
+	}
+	c.StaleWhileRevalidate = o.StaleWhileRevalidate
+	c.Observe = func(endpoint string, hit bool) {
+		o.CacheHit(endpoint, hit)
+	}
+	c.Logger = o.Log()
+	return c
+}
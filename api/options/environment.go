@@ -0,0 +1,91 @@
+package options
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Environment selects the endpoints a client talks to.
+type Environment string
+
+const (
+	Production Environment = "production"
+	Testnet    Environment = "testnet"
+	// Custom is set by WithEndpoints and WithBaseURL unless the URL is the one
+	// of another environment.
+	Custom Environment = "custom"
+)
+
+var ErrEnvironmentMismatch = errors.New("credentials do not belong to the environment")
+
+// Endpoints are the REST and WebSocket base URLs of one environment.
+type Endpoints struct {
+	REST      string
+	WebSocket string
+}
+
+// WithEnvironment switches the REST and WebSocket endpoints to env.
+// Constructors fail if the exchange has no such environment.
+func WithEnvironment(env Environment) Option {
+	return func(o *Options) {
+		o.Environment = env
+	}
+}
+
+// WithEndpoints points the client at a custom set of endpoints.
+func WithEndpoints(e Endpoints) Option {
+	return func(o *Options) {
+		o.Environment = Custom
+		o.BaseURL = e.REST
+		o.WebSocketURL = e.WebSocket
+		o.custom = true
+	}
+}
+
+// WithCredentialEnvironment declares the environment the API keys were issued for.
+// Constructors refuse keys of one environment with endpoints of another,
+// e.g. testnet keys against production.
+func WithCredentialEnvironment(env Environment) Option {
+	return func(o *Options) {
+		o.CredentialEnvironment = env
+	}
+}
+
+func (o *Options) resolveEnvironment() error {
+	if o.Environment == "" {
+		o.Environment = Production
+	}
+	if o.custom {
+		// the credentials are checked against where requests actually go
+		o.Environment = o.environmentOf(o.BaseURL)
+	} else if o.Environment != Custom {
+		if e, ok := o.Environments[o.Environment]; ok {
+			o.BaseURL = e.REST
+			o.WebSocketURL = e.WebSocket
+		} else if o.Environment != Production {
+			return errors.Errorf("%s environment is not supported", o.Environment)
+		}
+	}
+	if o.CredentialEnvironment != "" && o.Environment != Custom && o.CredentialEnvironment != o.Environment {
+		return errors.Wrapf(ErrEnvironmentMismatch, "%s credentials with %s endpoints", o.CredentialEnvironment, o.Environment)
+	}
+	return nil
+}
+
+// environmentOf returns the environment whose REST endpoint is baseURL, or
+// Custom for other hosts.
+func (o *Options) environmentOf(baseURL string) Environment {
+	same := func(a, b string) bool {
+		return a != "" && strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+	}
+	if same(o.production.REST, baseURL) {
+		return Production
+	}
+	for env, e := range o.Environments {
+		if same(e.REST, baseURL) {
+			return env
+		}
+	}
+	return Custom
+}
@@ -0,0 +1,219 @@
+package options
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Mirror tuning. Latency and error rate are moving averages over the last
+// requests, so a host which degrades loses its traffic within a few requests.
+const (
+	mirrorProbeInterval = 30 * time.Second
+	mirrorMaxFailures   = 3
+	mirrorSmoothing     = 0.2
+	// another host is only preferred when it scores this much better, so
+	// requests don't flap between hosts of similar latency
+	mirrorHysteresis = 0.8
+)
+
+// WithMirrors lets the client send its requests to any of the base URLs,
+// e.g. https://api1.binance.com. Requests to one of them go to the
+// healthiest, and the others are probed in the background.
+// Exchanges which publish mirrors have them by default in production;
+// WithMirrors() with no URLs pins the client to its base URL.
+// Private clients are never mirrored, so signed requests and orders are not
+// sent to a host they were not meant for or retried on another one.
+func WithMirrors(baseURLs ...string) Option {
+	return func(o *Options) {
+		o.Mirrors = baseURLs
+	}
+}
+
+type mirrors struct {
+	o     *Options
+	hosts []*mirrorHost
+
+	mu        sync.Mutex
+	current   *mirrorHost
+	lastProbe time.Time
+	probing   bool
+}
+
+type mirrorHost struct {
+	scheme, host string
+	measured     bool
+	latency      time.Duration
+	errorRate    float64
+	failures     int
+}
+
+// newMirrors returns nil unless the base URL of a public client is one of at
+// least two mirrors.
+func newMirrors(o *Options) (*mirrors, error) {
+	if o.Private || len(o.Mirrors) < 2 {
+		return nil, nil
+	}
+	base, err := url.Parse(o.BaseURL)
+	if err != nil {
+		return nil, nil
+	}
+	m := &mirrors{o: o}
+	for _, s := range o.Mirrors {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return nil, errors.Errorf("invalid mirror %s", s)
+		}
+		h := &mirrorHost{scheme: u.Scheme, host: u.Host}
+		if h.host == base.Host {
+			m.current = h
+		}
+		m.hosts = append(m.hosts, h)
+	}
+	if m.current == nil {
+		// custom endpoints or another environment
+		return nil, nil
+	}
+	m.current.measured = true
+	return m, nil
+}
+
+func (m *mirrors) lookup(host string) *mirrorHost {
+	for _, h := range m.hosts {
+		if h.host == host {
+			return h
+		}
+	}
+	return nil
+}
+
+func (m *mirrors) usable(h *mirrorHost) bool {
+	return h.measured && h.failures < mirrorMaxFailures
+}
+
+func (h *mirrorHost) score() float64 {
+	return float64(h.latency) * (1 + 10*h.errorRate)
+}
+
+// pick returns the host the next request goes to.
+func (m *mirrors) pick() *mirrorHost {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	best := m.current
+	for _, h := range m.hosts {
+		if !m.usable(h) {
+			continue
+		}
+		if !m.usable(best) || h.score() < best.score()*mirrorHysteresis {
+			best = h
+		}
+	}
+	if best != m.current {
+		m.o.Log().Infow("switching mirror", "from", m.current.host, "to", best.host)
+		m.current = best
+	}
+	return best
+}
+
+// record updates the averages of h; failed is set for network errors and 5xx.
+func (m *mirrors) record(h *mirrorHost, latency time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h.measured = true
+	failure := 0.0
+	if failed {
+		failure = 1
+		h.failures++
+	} else {
+		h.failures = 0
+		if h.latency == 0 {
+			h.latency = latency
+		} else {
+			h.latency += time.Duration(mirrorSmoothing * float64(latency-h.latency))
+		}
+	}
+	h.errorRate += mirrorSmoothing * (failure - h.errorRate)
+}
+
+// probe measures every host but the current one with a GET of its root,
+// at most once per mirrorProbeInterval.
+func (m *mirrors) probe(rt http.RoundTripper) {
+	m.mu.Lock()
+	if m.probing || time.Since(m.lastProbe) < mirrorProbeInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.probing = true
+	m.lastProbe = time.Now()
+	current := m.current
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			m.probing = false
+			m.mu.Unlock()
+		}()
+		for _, h := range m.hosts {
+			if h == current {
+				continue
+			}
+			start := time.Now()
+			failed := !m.ping(rt, h)
+			m.record(h, time.Since(start), failed)
+		}
+	}()
+}
+
+func (m *mirrors) ping(rt http.RoundTripper, h *mirrorHost) bool {
+	req, err := http.NewRequest(http.MethodGet, h.scheme+"://"+h.host+"/", nil)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	return res.StatusCode < http.StatusInternalServerError
+}
+
+// mirrorTransport sends requests for any mirror to the healthiest one.
+type mirrorTransport struct {
+	base  http.RoundTripper
+	probe http.RoundTripper
+	m     *mirrors
+}
+
+func (t *mirrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.m.lookup(req.URL.Host) == nil {
+		return t.base.RoundTrip(req)
+	}
+	t.m.probe(t.probe)
+	h := t.m.pick()
+	if req.URL.Host != h.host {
+		req = req.Clone(req.Context())
+		req.URL.Scheme, req.URL.Host, req.Host = h.scheme, h.host, ""
+	}
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	var p permanent
+	if err != nil && errors.As(err, &p) && p.Permanent() {
+		// refused before it was sent
+		return res, err
+	}
+	if err != nil && req.Context().Err() != nil {
+		return res, err
+	}
+	t.m.record(h, time.Since(start), err != nil || res.StatusCode >= http.StatusInternalServerError)
+	return res, err
+}
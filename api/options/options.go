@@ -0,0 +1,356 @@
+package options
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/logger"
+	"github.com/xuyangcn/go-exchange-client/symbols"
+	"go.uber.org/zap"
+)
+
+// RateLimiter blocks until a request may be sent.
+// *rate.Limiter of golang.org/x/time/rate satisfies it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// Options are the settings shared by every public and private client constructor.
+// Each constructor fills in its own defaults and applies the given Option on top.
+type Options struct {
+	// Exchange and Private identify the client, they are set by its constructor.
+	Exchange string
+	Private  bool
+
+	BaseURL            string
+	WebSocketURL       string
+	HttpClient         *http.Client
+	Timeout            time.Duration
+	RateCacheDuration  time.Duration
+	BoardCacheDuration time.Duration
+	Logger             *zap.SugaredLogger
+	RateLimiter        RateLimiter
+	Retry              Retry
+	Middlewares        []Middleware
+	Observers          []Observer
+
+	// CacheStore, CacheTTL and StaleWhileRevalidate configure the market data
+	// cache of public clients, see NewCache.
+	CacheStore           cache.Store
+	CacheTTL             map[string]time.Duration
+	StaleWhileRevalidate time.Duration
+
+	// Environments are the endpoints of the environments the exchange has
+	// besides the defaults above, which are the production endpoints.
+	Environments          map[Environment]Endpoints
+	Environment           Environment
+	CredentialEnvironment Environment
+
+	// Mirrors are alternate base URLs of the exchange, see WithMirrors.
+	Mirrors []string
+
+	// Aliases map the asset names of the exchange to canonical ones, see
+	// WithAliases.
+	Aliases symbols.Aliases
+
+	custom     bool
+	production Endpoints
+	mirrors    *mirrors
+}
+
+// Retry is the policy for resending GET requests which failed with a network
+// error or 5xx, waiting at least as long as Retry-After asks. 429 and 418 are
+// never resent. The zero value sends every request once.
+type Retry struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+type Option func(*Options)
+
+// Middleware wraps the transport of a client. o is the resolved options of the
+// client, so a middleware can label what it records with o.Exchange.
+type Middleware func(o *Options, next http.RoundTripper) http.RoundTripper
+
+// Observer is told about the cache lookups of a client.
+type Observer interface {
+	ObserveCache(o *Options, cache string, hit bool)
+}
+
+// WithBaseURL overrides the REST endpoint of the selected environment. The
+// environment becomes the one the URL belongs to, or Custom.
+func WithBaseURL(baseURL string) Option {
+	return func(o *Options) {
+		o.BaseURL = baseURL
+		o.custom = true
+	}
+}
+
+// WithHTTPClient makes the client send requests through cli.
+// The timeout of cli is kept unless WithTimeout is also given.
+func WithHTTPClient(cli *http.Client) Option {
+	return func(o *Options) {
+		o.HttpClient = cli
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithRateCacheDuration sets how long rates, volumes and order book ticks are cached.
+func WithRateCacheDuration(d time.Duration) Option {
+	return func(o *Options) {
+		o.RateCacheDuration = d
+	}
+}
+
+// WithBoardCacheDuration sets how long boards are cached.
+func WithBoardCacheDuration(d time.Duration) Option {
+	return func(o *Options) {
+		o.BoardCacheDuration = d
+	}
+}
+
+// WithLogger sets the logger of the client, the package default of logger is used otherwise.
+func WithLogger(l *zap.SugaredLogger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithSlogHandler sends the logs of the client to h.
+func WithSlogHandler(h slog.Handler) Option {
+	return WithLogger(logger.FromSlog(h))
+}
+
+// WithRateLimiter makes every request wait for l before it is sent.
+func WithRateLimiter(l RateLimiter) Option {
+	return func(o *Options) {
+		o.RateLimiter = l
+	}
+}
+
+// WithRetry resends failed GET requests up to maxAttempts times in total,
+// waiting backoff, 2*backoff, ... between attempts.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.Retry = Retry{MaxAttempts: maxAttempts, Backoff: backoff}
+	}
+}
+
+// WithMiddleware wraps every request of the client with m, inside the retries
+// so each attempt goes through m.
+func WithMiddleware(m ...Middleware) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, m...)
+	}
+}
+
+func WithObserver(obs Observer) Option {
+	return func(o *Options) {
+		o.Observers = append(o.Observers, obs)
+	}
+}
+
+// New applies opts on top of defaults and resolves the endpoints of the environment.
+func New(defaults Options, opts ...Option) (*Options, error) {
+	o := defaults
+	o.production = Endpoints{REST: o.BaseURL, WebSocket: o.WebSocketURL}
+	if o.Aliases == nil {
+		o.Aliases = symbols.Defaults[o.Exchange]
+	}
+	timeout := o.Timeout
+	o.Timeout = 0
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Timeout == 0 && o.HttpClient == nil {
+		o.Timeout = timeout
+	}
+	if err := o.resolveEnvironment(); err != nil {
+		return nil, err
+	}
+	m, err := newMirrors(&o)
+	if err != nil {
+		return nil, err
+	}
+	o.mirrors = m
+	return &o, nil
+}
+
+// NewHttpClient returns a copy of the configured http client, or a new one,
+// with the timeout and the rate limiter applied.
+func (o *Options) NewHttpClient() *http.Client {
+	cli := &http.Client{}
+	if o.HttpClient != nil {
+		*cli = *o.HttpClient
+	}
+	if o.Timeout != 0 {
+		cli.Timeout = o.Timeout
+	}
+	cli.Transport = o.WrapTransport(cli.Transport)
+	return cli
+}
+
+// Transport returns the round tripper of NewHttpClient, for clients which
+// build short-lived http clients.
+func (o *Options) Transport() http.RoundTripper {
+	return o.NewHttpClient().Transport
+}
+
+// WrapTransport applies the configured middlewares to rt.
+// It is used by SetTransport so replacing the transport keeps the rate limiter.
+func (o *Options) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	if o == nil {
+		return rt
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	base := rt
+	rt = &logTransport{base: rt, o: o}
+	for _, m := range o.Middlewares {
+		rt = m(o, rt)
+	}
+	if o.mirrors != nil {
+		rt = &mirrorTransport{base: rt, probe: base, m: o.mirrors}
+	}
+	if o.RateLimiter != nil {
+		rt = &limitedTransport{base: rt, limiter: o.RateLimiter}
+	}
+	if o.Retry.MaxAttempts > 1 {
+		rt = &retryTransport{base: rt, retry: o.Retry}
+	}
+	return rt
+}
+
+// Log returns the configured logger or the package default, with the
+// exchange field set.
+func (o *Options) Log() *zap.SugaredLogger {
+	l := logger.Get()
+	if o == nil {
+		return l
+	}
+	if o.Logger != nil {
+		l = o.Logger
+	}
+	if o.Exchange == "" {
+		return l
+	}
+	return l.With("exchange", o.Exchange)
+}
+
+// CacheHit reports a lookup of the named cache to the observers and returns hit.
+func (o *Options) CacheHit(cache string, hit bool) bool {
+	if o == nil {
+		return hit
+	}
+	for _, obs := range o.Observers {
+		obs.ObserveCache(o, cache, hit)
+	}
+	return hit
+}
+
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter RateLimiter
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// logTransport logs every attempt at debug level and failed ones at warn level.
+// Query strings are left out since they carry signatures.
+type logTransport struct {
+	base http.RoundTripper
+	o    *Options
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.o.Log().Warnw("request failed", "method", req.Method, "endpoint", req.URL.Path, "error", err)
+		return res, err
+	}
+	t.o.Log().Debugw("request", "method", req.Method, "endpoint", req.URL.Path, "status", res.StatusCode, "latency", time.Since(start))
+	return res, err
+}
+
+// permanent is implemented by errors which are not worth a retry, such as
+// the one of an open circuit breaker.
+type permanent interface {
+	Permanent() bool
+}
+
+type retryTransport struct {
+	base  http.RoundTripper
+	retry Retry
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+	backoff := t.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		res, err := t.base.RoundTrip(req)
+		if attempt >= t.retry.MaxAttempts || !retryable(res, err) {
+			return res, err
+		}
+		wait := backoff
+		if res != nil {
+			if d := retryAfter(res); d > wait {
+				wait = d
+			}
+			res.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// retryable leaves out 429 and 418: the exchange asked to slow down, and
+// retrying would turn a rate limit into a ban.
+func retryable(res *http.Response, err error) bool {
+	var p permanent
+	if errors.As(err, &p) && p.Permanent() {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return res.StatusCode >= http.StatusInternalServerError
+}
+
+// retryAfter returns how long the Retry-After header of res asks to wait,
+// given in seconds or as a date.
+func retryAfter(res *http.Response) time.Duration {
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
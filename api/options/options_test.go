@@ -0,0 +1,413 @@
+package options
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/cache"
+)
+
+type fakeRoundTripper struct {
+	calls    int
+	statuses []int
+	// retryAfter is the Retry-After header of the error responses
+	retryAfter string
+}
+
+func (rt *fakeRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	if rt.calls < len(rt.statuses) {
+		status = rt.statuses[rt.calls]
+	}
+	rt.calls++
+	header := make(http.Header)
+	if status != http.StatusOK && rt.retryAfter != "" {
+		header.Set("Retry-After", rt.retryAfter)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    r,
+		Header:     header,
+	}, nil
+}
+
+type countingLimiter struct {
+	waits int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	defaults := Options{BaseURL: "https://example.com", Timeout: 20 * time.Second, RateCacheDuration: 3 * time.Second}
+
+	o, err := New(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.BaseURL != "https://example.com" || o.RateCacheDuration != 3*time.Second {
+		t.Errorf("defaults are not applied: %+v", o)
+	}
+	if cli := o.NewHttpClient(); cli.Timeout != 20*time.Second {
+		t.Errorf("default timeout is not applied: %v", cli.Timeout)
+	}
+
+	o, err = New(defaults, WithBaseURL("http://localhost:4243"), WithRateCacheDuration(time.Minute), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.BaseURL != "http://localhost:4243" || o.RateCacheDuration != time.Minute {
+		t.Errorf("options are not applied: %+v", o)
+	}
+	if cli := o.NewHttpClient(); cli.Timeout != time.Second {
+		t.Errorf("timeout is not applied: %v", cli.Timeout)
+	}
+
+	given := &http.Client{Timeout: 5 * time.Second}
+	o, err = New(defaults, WithHTTPClient(given))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := o.NewHttpClient()
+	if cli.Timeout != 5*time.Second {
+		t.Errorf("timeout of the given client is overwritten: %v", cli.Timeout)
+	}
+	if cli == given {
+		t.Error("the given client is modified")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	rt := &fakeRoundTripper{}
+	limiter := &countingLimiter{}
+	o, err := New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := o.NewHttpClient()
+	for i := 0; i < 3; i++ {
+		res, err := cli.Get("http://localhost:4243")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	if limiter.waits != 3 || rt.calls != 3 {
+		t.Errorf("expected 3 waits and calls, got %d and %d", limiter.waits, rt.calls)
+	}
+
+	cli.Transport = o.WrapTransport(rt)
+	res, err := cli.Get("http://localhost:4243")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if limiter.waits != 4 {
+		t.Errorf("replaced transport is not rate limited")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	rt := &fakeRoundTripper{statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable}}
+	o, err := New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := o.NewHttpClient().Get("http://localhost:4243")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || rt.calls != 3 {
+		t.Errorf("expected 200 after 3 calls, got %d after %d", res.StatusCode, rt.calls)
+	}
+
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusTeapot} {
+		rt = &fakeRoundTripper{statuses: []int{status}}
+		o, err = New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRetry(3, time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err = o.NewHttpClient().Get("http://localhost:4243")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != status || rt.calls != 1 {
+			t.Errorf("%d must not be retried, got %d after %d calls", status, res.StatusCode, rt.calls)
+		}
+	}
+
+	rt = &fakeRoundTripper{statuses: []int{http.StatusServiceUnavailable}, retryAfter: "1"}
+	o, err = New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	res, err = o.NewHttpClient().Get("http://localhost:4243")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if d := time.Since(start); res.StatusCode != http.StatusOK || d < time.Second {
+		t.Errorf("expected 200 after Retry-After, got %d after %s", res.StatusCode, d)
+	}
+
+	rt = &fakeRoundTripper{statuses: []int{http.StatusBadGateway, http.StatusBadGateway}}
+	o, err = New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = o.NewHttpClient().Get("http://localhost:4243")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadGateway || rt.calls != 2 {
+		t.Errorf("expected the last response after 2 calls, got %d after %d", res.StatusCode, rt.calls)
+	}
+
+	rt = &fakeRoundTripper{statuses: []int{http.StatusBadGateway}}
+	o, err = New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = o.NewHttpClient().Post("http://localhost:4243", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if rt.calls != 1 {
+		t.Errorf("POST must not be retried, got %d calls", rt.calls)
+	}
+}
+
+type countingObserver map[string]int
+
+func (c countingObserver) ObserveCache(o *Options, cache string, hit bool) {
+	if hit {
+		c[cache]++
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	rt := &fakeRoundTripper{statuses: []int{http.StatusBadGateway}}
+	var seen []int
+	record := func(o *Options, next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			res, err := next.RoundTrip(r)
+			if err == nil {
+				seen = append(seen, res.StatusCode)
+			}
+			return res, err
+		})
+	}
+	obs := countingObserver{}
+	o, err := New(Options{}, WithHTTPClient(&http.Client{Transport: rt}), WithRetry(2, time.Millisecond), WithMiddleware(record), WithObserver(obs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := o.NewHttpClient().Get("http://localhost:4243")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if len(seen) != 2 || seen[0] != http.StatusBadGateway || seen[1] != http.StatusOK {
+		t.Errorf("middleware must see every attempt, got %v", seen)
+	}
+
+	if o.CacheHit("board", false) || !o.CacheHit("board", true) || obs["board"] != 1 {
+		t.Errorf("unexpected cache observations %v", obs)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestEnvironment(t *testing.T) {
+	defaults := Options{
+		BaseURL:      "https://api.example.com",
+		WebSocketURL: "wss://stream.example.com",
+		Environments: map[Environment]Endpoints{
+			Testnet: {REST: "https://testnet.example.com", WebSocket: "wss://testnet.example.com"},
+		},
+	}
+
+	o, err := New(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Environment != Production || o.BaseURL != "https://api.example.com" {
+		t.Errorf("expected production endpoints, got %+v", o)
+	}
+
+	o, err = New(defaults, WithEnvironment(Testnet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.BaseURL != "https://testnet.example.com" || o.WebSocketURL != "wss://testnet.example.com" {
+		t.Errorf("expected testnet endpoints, got %+v", o)
+	}
+
+	o, err = New(defaults, WithEndpoints(Endpoints{REST: "http://localhost:4243"}), WithCredentialEnvironment(Testnet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Environment != Custom || o.BaseURL != "http://localhost:4243" || o.WebSocketURL != "" {
+		t.Errorf("expected custom endpoints, got %+v", o)
+	}
+
+	if _, err := New(Options{BaseURL: "https://api.example.com"}, WithEnvironment(Testnet)); err == nil {
+		t.Error("expected an error for an exchange without testnet")
+	}
+	if _, err := New(defaults, WithCredentialEnvironment(Testnet)); errors.Cause(err) != ErrEnvironmentMismatch {
+		t.Errorf("expected %v, got %v", ErrEnvironmentMismatch, err)
+	}
+	if _, err := New(defaults, WithEnvironment(Testnet), WithCredentialEnvironment(Production)); errors.Cause(err) != ErrEnvironmentMismatch {
+		t.Errorf("expected %v, got %v", ErrEnvironmentMismatch, err)
+	}
+
+	// testnet keys must not reach production through a base URL
+	if _, err := New(defaults, WithEnvironment(Testnet), WithBaseURL("https://api.example.com/"), WithCredentialEnvironment(Testnet)); errors.Cause(err) != ErrEnvironmentMismatch {
+		t.Errorf("expected %v, got %v", ErrEnvironmentMismatch, err)
+	}
+	o, err = New(defaults, WithBaseURL("https://testnet.example.com"), WithCredentialEnvironment(Testnet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Environment != Testnet {
+		t.Errorf("expected the environment of the URL, got %s", o.Environment)
+	}
+}
+
+func TestLog(t *testing.T) {
+	var o *Options
+	if o.Log() == nil {
+		t.Error("nil options should fall back to the default logger")
+	}
+
+	var buf bytes.Buffer
+	rt := &fakeRoundTripper{statuses: []int{http.StatusTooManyRequests}}
+	o, err := New(Options{Exchange: "binance"}, WithHTTPClient(&http.Client{Transport: rt}),
+		WithSlogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := o.NewHttpClient().Get("http://localhost:4243/api/v1/depth?signature=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	line := buf.String()
+	for _, want := range []string{`"level":"DEBUG"`, `"exchange":"binance"`, `"endpoint":"/api/v1/depth"`, `"status":429`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %s in %s", want, line)
+		}
+	}
+	if strings.Contains(line, "secret") {
+		t.Errorf("query string is logged: %s", line)
+	}
+
+	buf.Reset()
+	o, err = New(Options{Exchange: "binance", RateCacheDuration: time.Minute}, WithCacheStore(failingStore{}),
+		WithSlogHandler(slog.NewJSONHandler(&buf, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.NewCache().Fetch("rate", "", func() (interface{}, error) { return 1, nil })
+	if line := buf.String(); !strings.Contains(line, "failed to read cache") || !strings.Contains(line, `"exchange":"binance"`) {
+		t.Errorf("expected cache failures in the client log, got %s", line)
+	}
+}
+
+// failingStore fails every read and write.
+type failingStore struct{}
+
+func (failingStore) Get(ctx context.Context, key string) (*cache.Entry, error) {
+	return nil, errors.New("store is down")
+}
+
+func (failingStore) Set(ctx context.Context, key string, e *cache.Entry, ttl time.Duration) error {
+	return errors.New("store is down")
+}
+
+func TestMirrors(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		calls[r.URL.Host]++
+		mu.Unlock()
+		status := http.StatusOK
+		if r.URL.Host == "api.example.com" {
+			status = http.StatusBadGateway
+		}
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader("{}")), Request: r, Header: make(http.Header)}, nil
+	})
+	defaults := Options{BaseURL: "https://api.example.com", Mirrors: []string{"https://api.example.com", "https://api1.example.com"}}
+	o, err := New(defaults, WithHTTPClient(&http.Client{Transport: rt}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := o.NewHttpClient()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		res, err := cli.Get("https://api.example.com/api/v1/depth?symbol=BTCUSDT")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode == http.StatusOK {
+			if res.Request.URL.RawQuery != "symbol=BTCUSDT" {
+				t.Errorf("the query is lost: %s", res.Request.URL)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the failing host to be left out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	failed := calls["api.example.com"]
+	mu.Unlock()
+	if failed < mirrorMaxFailures {
+		t.Errorf("expected a failover after %d failures, got %d", mirrorMaxFailures, failed)
+	}
+
+	// other hosts, environments, pinned and private clients are left alone
+	for _, o := range []*Options{
+		{BaseURL: "https://testnet.example.com", Mirrors: defaults.Mirrors},
+		{BaseURL: "https://api.example.com"},
+		{BaseURL: "https://api.example.com", Mirrors: defaults.Mirrors, Private: true},
+	} {
+		if m, err := newMirrors(o); m != nil || err != nil {
+			t.Errorf("expected no mirrors for %+v, got %v", o, err)
+		}
+	}
+	o, err = New(defaults, WithMirrors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.mirrors != nil {
+		t.Error("WithMirrors() must pin the client")
+	}
+	if _, err := New(defaults, WithMirrors("https://api.example.com", "::")); err == nil {
+		t.Error("expected an invalid mirror to fail")
+	}
+}
@@ -0,0 +1,40 @@
+package options
+
+import (
+	"net/http"
+	"sync"
+)
+
+// SwapTransport forwards to a transport SetTransport can replace while
+// requests are in flight.
+type SwapTransport struct {
+	mu sync.RWMutex
+	rt http.RoundTripper
+}
+
+func NewSwapTransport(rt http.RoundTripper) *SwapTransport {
+	return &SwapTransport{rt: rt}
+}
+
+func (t *SwapTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	rt := t.rt
+	t.mu.RUnlock()
+	return rt.RoundTrip(req)
+}
+
+func (t *SwapTransport) Set(rt http.RoundTripper) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rt = rt
+}
+
+// SetTransport replaces the transport of cli. Clients whose transport is a
+// SwapTransport swap it in place, so it is safe while requests are in flight.
+func SetTransport(cli *http.Client, rt http.RoundTripper) {
+	if s, ok := cli.Transport.(*SwapTransport); ok {
+		s.Set(rt)
+		return
+	}
+	cli.Transport = NewSwapTransport(rt)
+}
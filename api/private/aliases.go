@@ -0,0 +1,124 @@
+package private
+
+import (
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
+)
+
+// aliasClient translates the asset names of an exchange to canonical ones
+// in both directions, like the public one.
+type aliasClient struct {
+	PrivateClient
+	aliases symbols.Aliases
+}
+
+func withAliases(cli PrivateClient, a symbols.Aliases) PrivateClient {
+	return &aliasClient{PrivateClient: cli, aliases: a}
+}
+
+func (c *aliasClient) TransferFee() (map[string]float64, error) {
+	m, err := c.PrivateClient.TransferFee()
+	if err != nil {
+		return nil, err
+	}
+	return c.canonicalAmounts(m), nil
+}
+
+func (c *aliasClient) TradeFeeRates() (map[string]map[string]TradeFee, error) {
+	m, err := c.PrivateClient.TradeFeeRates()
+	if err != nil {
+		return nil, err
+	}
+	var pairs []models.CurrencyPair
+	for trading, n := range m {
+		for settlement := range n {
+			pairs = append(pairs, models.CurrencyPair{Trading: trading, Settlement: settlement})
+		}
+	}
+	r := make(map[string]map[string]TradeFee, len(m))
+	for cp, p := range c.aliases.CanonicalPairs(pairs) {
+		rn, ok := r[cp.Trading]
+		if !ok {
+			rn = make(map[string]TradeFee)
+			r[cp.Trading] = rn
+		}
+		rn[cp.Settlement] = m[p.Trading][p.Settlement]
+	}
+	return r, nil
+}
+
+func (c *aliasClient) TradeFeeRate(trading string, settlement string) (TradeFee, error) {
+	return c.PrivateClient.TradeFeeRate(c.aliases.Native(trading), c.aliases.Native(settlement))
+}
+
+func (c *aliasClient) Balances() (map[string]float64, error) {
+	m, err := c.PrivateClient.Balances()
+	if err != nil {
+		return nil, err
+	}
+	return c.canonicalAmounts(m), nil
+}
+
+func (c *aliasClient) CompleteBalances() (map[string]*models.Balance, error) {
+	m, err := c.PrivateClient.CompleteBalances()
+	if err != nil {
+		return nil, err
+	}
+	assets := make([]string, 0, len(m))
+	for k := range m {
+		assets = append(assets, k)
+	}
+	r := make(map[string]*models.Balance, len(m))
+	for canonical, native := range c.aliases.CanonicalAssets(assets) {
+		r[canonical] = m[native]
+	}
+	return r, nil
+}
+
+func (c *aliasClient) CompleteBalance(coin string) (*models.Balance, error) {
+	return c.PrivateClient.CompleteBalance(c.aliases.Native(coin))
+}
+
+func (c *aliasClient) ActiveOrders() ([]*models.Order, error) {
+	orders, err := c.PrivateClient.ActiveOrders()
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		o.Trading = c.aliases.Canonical(o.Trading)
+		o.Settlement = c.aliases.Canonical(o.Settlement)
+	}
+	return orders, nil
+}
+
+func (c *aliasClient) IsOrderFilled(trading string, settlement string, orderNumber string) (bool, error) {
+	return c.PrivateClient.IsOrderFilled(c.aliases.Native(trading), c.aliases.Native(settlement), orderNumber)
+}
+
+func (c *aliasClient) Order(trading string, settlement string, ordertype models.OrderType, price float64, amount float64) (string, error) {
+	return c.PrivateClient.Order(c.aliases.Native(trading), c.aliases.Native(settlement), ordertype, price, amount)
+}
+
+func (c *aliasClient) CancelOrder(trading string, settlement string, ordertype models.OrderType, orderNumber string) error {
+	return c.PrivateClient.CancelOrder(c.aliases.Native(trading), c.aliases.Native(settlement), ordertype, orderNumber)
+}
+
+func (c *aliasClient) Transfer(typ string, addr string, amount float64, additionalFee float64) error {
+	return c.PrivateClient.Transfer(c.aliases.Native(typ), addr, amount, additionalFee)
+}
+
+func (c *aliasClient) Address(coin string) (string, error) {
+	return c.PrivateClient.Address(c.aliases.Native(coin))
+}
+
+func (c *aliasClient) canonicalAmounts(m map[string]float64) map[string]float64 {
+	assets := make([]string, 0, len(m))
+	for k := range m {
+		assets = append(assets, k)
+	}
+	r := make(map[string]float64, len(m))
+	for canonical, native := range c.aliases.CanonicalAssets(assets) {
+		r[canonical] = m[native]
+	}
+	return r
+}
@@ -2,6 +2,7 @@ package private
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -14,55 +15,56 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
 	"github.com/xuyangcn/go-exchange-client/api/public"
 	"github.com/xuyangcn/go-exchange-client/helpers"
 	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/pkg/errors"
-	"github.com/tidwall/gjson"
 )
 
 const (
 	BINANCE_BASE_URL = "https://api.binance.com"
 )
 
-func NewBinanceApi(apikey func() (string, error), apisecret func() (string, error)) (*BinanceApi, error) {
-	hitbtcPublic, err := public.NewBinancePublicApi()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize public client")
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
+// binanceEnvironments are the endpoints besides production.
+var binanceEnvironments = map[options.Environment]options.Endpoints{
+	options.Testnet: {REST: "https://testnet.binance.vision", WebSocket: "wss://testnet.binance.vision/ws"},
+}
+
+func NewBinanceApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*BinanceApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "binance",
+		Private:           true,
+		BaseURL:           BINANCE_BASE_URL,
+		WebSocketURL:      "wss://stream.binance.com:9443/ws",
+		Environments:      binanceEnvironments,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pairs")
-	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
+		return nil, err
 	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	b := &BinanceApi{
-		BaseURL:           BINANCE_BASE_URL,
-		apiV1:             BINANCE_BASE_URL + "/api/v1/",
-		apiV3:             BINANCE_BASE_URL + "/api/v3/",
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		apiV1:             o.BaseURL + "/api/v1/",
+		apiV3:             o.BaseURL + "/api/v3/",
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		rt:                &http.Transport{},
+		rt:                cli.Transport,
+
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
 
 		m:         new(sync.Mutex),
 		currencyM: new(sync.Mutex),
 	}
-	b.setTimeOffset()
 	return b, nil
 }
 
@@ -72,22 +74,55 @@ type BinanceApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	rt                *http.Transport
+	rt                http.RoundTripper
 	settlements       []string
 	apiV1             string
 	apiV3             string
 	timeoffset        int64
+	timeM             sync.Mutex
+	timeSynced        bool
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	precisionMap    map[string]map[string]models.Precisions
+	precisionM      sync.Mutex
 	currencyPairs   []models.CurrencyPair
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m         *sync.Mutex
 	currencyM *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (h *BinanceApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
+	return nil
+}
+
+func (h *BinanceApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "binance",
+		Operations:  models.Except(models.PrivateOperations, models.OpActiveOrders),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup syncs the server time and loads the precisions which are otherwise fetched on first use.
+func (h *BinanceApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.syncTime, h.fetchPrecision)
+}
+
+func (h *BinanceApi) publicClient() (public.PublicClient, error) {
+	return public.NewBinancePublicApi(h.publicOpts...)
+}
+
 func (h *BinanceApi) privateApiUrl() string {
 	return h.BaseURL
 }
@@ -96,12 +131,14 @@ func (h *BinanceApi) publicApiUrl(command string) string {
 	return h.BaseURL + command
 }
 
+// fetchPrecision loads the precisions once. A failed fetch is retried on the next call.
 func (h *BinanceApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
 
-	h.precisionMap = make(map[string]map[string]models.Precisions)
 	url := h.publicApiUrl("/api/v1/exchangeInfo")
 	req, err := requestGetAsChrome(url)
 	if err != nil {
@@ -117,21 +154,25 @@ func (h *BinanceApi) fetchPrecision() error {
 		return errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	value := gjson.ParseBytes(byteArray)
+	if !value.Get("symbols").IsArray() {
+		return errors.Errorf("failed to fetch %s: %s", url, byteArray)
+	}
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("symbols").Array() {
 		trading := v.Get("baseAsset").Str
 		settlement := v.Get("quoteAsset").Str
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  int(v.Get("baseAssetPrecision").Int()),
 			AmountPrecision: int(v.Get("quotePrecision").Int()),
 		}
 	}
-
-	return errors.Wrapf(err, "failed to fetch %s", url)
+	h.precisionMap = precisionMap
+	return nil
 }
 
 func (h *BinanceApi) precise(trading string, settlement string) (*models.Precisions, error) {
@@ -139,7 +180,11 @@ func (h *BinanceApi) precise(trading string, settlement string) (*models.Precisi
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s missing trading", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -277,22 +322,19 @@ func (h *BinanceApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 }
 
 func (h *BinanceApi) TradeFeeRates() (map[string]map[string]TradeFee, error) {
-	url := public.BINANCE_BASE_URL + "/api/v3/account"
-	resp, err := h.HttpClient.Get(url)
+	pairs, err := h.CurrencyPairs()
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	byteArray, err := ioutil.ReadAll(resp.Body)
+	byteArray, err := h.privateApi("GET", "/api/v3/account", &url.Values{})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrap(err, "failed to fetch trade fee rates")
 	}
 	value := gjson.ParseBytes(byteArray)
 	makerFee := value.Get("makerCommission").Num / 10000
 	takerFee := value.Get("takerCommission").Num / 10000
 	traderFeeMap := make(map[string]map[string]TradeFee)
-	for _, pair := range h.currencyPairs {
+	for _, pair := range pairs {
 		m, ok := traderFeeMap[pair.Trading]
 		if !ok {
 			m = make(map[string]TradeFee)
@@ -309,7 +351,11 @@ func (b *BinanceApi) TradeFeeRate(trading string, settlement string) (TradeFee,
 	if err != nil {
 		return TradeFee{}, err
 	}
-	return feeMap[trading][settlement], nil
+	fee, ok := feeMap[trading][settlement]
+	if !ok {
+		return TradeFee{}, errors.Errorf("%s/%s missing trade fee rate", trading, settlement)
+	}
+	return fee, nil
 }
 
 type BinanceTransferFeeResponse struct {
@@ -345,7 +391,6 @@ func (h *BinanceApi) TransferFee() (map[string]float64, error) {
 	}
 	path := h.BaseURL + "/wapi/v3/assetDetail.html" + params.Encode()
 	respmap, err := helpers.HttpGet2(&h.HttpClient, path, map[string]string{"X-MBX-APIKEY": apiKey})
-	fmt.Println(respmap)
 
 	ad := respmap["assetDetail"]
 	if ad == nil {
@@ -576,16 +621,37 @@ func (bn *BinanceApi) setTimeOffset() error {
 	lt := time.Now()
 	offset := st.Sub(lt).Nanoseconds()
 	bn.timeoffset = int64(offset)
+	bn.timeSynced = true
 	return nil
 }
 
+// syncTime sets the server time offset on first use. A failure is retried by
+// the next request, which is signed with the local time meanwhile.
+func (bn *BinanceApi) syncTime() error {
+	bn.timeM.Lock()
+	defer bn.timeM.Unlock()
+	if bn.timeSynced {
+		return nil
+	}
+	return bn.setTimeOffset()
+}
+
+func (bn *BinanceApi) timeOffset() int64 {
+	bn.timeM.Lock()
+	defer bn.timeM.Unlock()
+	return bn.timeoffset
+}
+
 func (bn *BinanceApi) buildParamsSigned(postForm *url.Values) error {
 	secretKey, err := bn.SecretKeyFunc()
 	if err != nil {
 		return err
 	}
+	if err := bn.syncTime(); err != nil {
+		bn.opts.Log().Warnw("failed to sync server time", "error", err)
+	}
 	postForm.Set("recvWindow", "60000")
-	tonce := strconv.FormatInt(time.Now().UnixNano()+bn.timeoffset, 10)[0:13]
+	tonce := strconv.FormatInt(time.Now().UnixNano()+bn.timeOffset(), 10)[0:13]
 	postForm.Set("timestamp", tonce)
 	payload := postForm.Encode()
 	sign, _ := helpers.GetParamHmacSHA256Sign(secretKey, payload)
@@ -681,7 +747,10 @@ func (h *BinanceApi) ActiveOrders() ([]*models.Order, error) {
 func (h *BinanceApi) Order(trading string, settlement string, ordertype models.OrderType, price float64, amount float64) (string, error) {
 	params := &url.Values{}
 
-	symbol := strings.ToUpper(fmt.Sprintf("%s%s", trading, settlement))
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	params.Set("symbol", symbol)
 
 	if ordertype == models.Bid {
@@ -721,8 +790,12 @@ func (h *BinanceApi) Transfer(typ string, addr string, amount float64, additiona
 
 func (h *BinanceApi) CancelOrder(trading string, settlement string,
 	ordertype models.OrderType, orderNumber string) error {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return err
+	}
 	params := &url.Values{}
-	params.Set("symbol", trading+settlement)
+	params.Set("symbol", symbol)
 	params.Set("origClientOrderId", orderNumber)
 
 	bs, err := h.privateApi("DELETE", "/api/v3/order", params)
@@ -737,8 +810,12 @@ func (h *BinanceApi) CancelOrder(trading string, settlement string,
 }
 
 func (h *BinanceApi) IsOrderFilled(trading string, settlement string, orderNumber string) (bool, error) {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return false, err
+	}
 	params := &url.Values{}
-	params.Set("symbol", trading+settlement)
+	params.Set("symbol", symbol)
 	params.Set("origClientOrderId", orderNumber)
 	bs, err := h.privateApi("GET", "/api/v3/order", params)
 	if err != nil {
@@ -2,6 +2,7 @@ package private
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -15,8 +16,10 @@ import (
 
 	"github.com/Jeffail/gabs"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/models"
 )
 
 const (
@@ -38,24 +41,69 @@ type BitflyerApi struct {
 	rateMap         map[string]map[string]float64
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
-func NewBitflyerPrivateApi(apikey func() (string, error), apisecret func() (string, error)) (*BitflyerApi, error) {
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (b *BitflyerApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&b.HttpClient, b.opts.WrapTransport(transport))
+	return nil
+}
+
+func (b *BitflyerApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "bitflyer",
+		Operations:  models.Except(models.PrivateOperations, models.OpTransferFee, models.OpTransfer, models.OpAddress),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup does nothing as the client has no market metadata to load.
+func (b *BitflyerApi) Warmup(ctx context.Context) error {
+	return nil
+}
+
+func NewBitflyerPrivateApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*BitflyerApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "bitflyer",
+		Private:           true,
+		BaseURL:           BITFLYER_BASE_URL,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	api := &BitflyerApi{
 		ApikeyFunc:        apikey,
 		ApiSecretFunc:     apisecret,
-		BaseURL:           BITFLYER_BASE_URL,
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
+
 		m: new(sync.Mutex),
 	}
 	return api, nil
 }
 
+func (b *BitflyerApi) publicClient() (public.PublicClient, error) {
+	return public.NewBitflyerPublicApi(b.publicOpts...)
+}
+
 func (b *BitflyerApi) privateApiUrl() string {
 	return b.BaseURL
 }
@@ -388,8 +436,12 @@ func (b *BitflyerApi) Order(trading string, settlement string, ordertype models.
 	orderpath := "/v1/me/sendchildorder"
 	method := "POST"
 
+	productCode, err := b.symbols.symbol(b.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	param := make(map[string]string)
-	param["product_code"] = trading + "_" + settlement
+	param["product_code"] = productCode
 	param["child_order_type"] = "LIMIT"
 
 	var cmd string
@@ -423,11 +475,15 @@ func (b *BitflyerApi) Transfer(typ string, addr string,
 
 func (b *BitflyerApi) CancelOrder(trading string, settlement string,
 	ordertype models.OrderType, orderNumber string) error {
+	productCode, err := b.symbols.symbol(b.publicClient, trading, settlement)
+	if err != nil {
+		return err
+	}
 	args := make(map[string]string)
 	args["child_order_id"] = orderNumber
-	args["product_code"] = trading + settlement
+	args["product_code"] = productCode
 
-	_, err := b.privateApi("POST", "/v1/me/sendchildorder", args)
+	_, err = b.privateApi("POST", "/v1/me/sendchildorder", args)
 	if err != nil {
 		return errors.Wrapf(err, "failed to cancel order")
 	}
@@ -1,12 +1,44 @@
 package private
 
 import (
-	"github.com/xuyangcn/go-exchange-client/models"
+	"context"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
-	"strings"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"net/http"
 )
 
+func init() {
+	mustRegister("bitflyer", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewBitflyerPrivateApi(apikey, seckey, opts...)
+	})
+	mustRegister("poloniex", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewPoloniexApi(apikey, seckey, opts...)
+	})
+	mustRegister("hitbtc", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewHitbtcApi(apikey, seckey, opts...)
+	})
+	mustRegister("huobi", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewHuobiApi(apikey, seckey, opts...)
+	}, "huobipro")
+	mustRegister("okex", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewOkexApi(apikey, seckey, opts...)
+	}, "okx")
+	mustRegister("lbank", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewLbankApi(apikey, seckey, opts...)
+	})
+	mustRegister("kucoin", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewKucoinApi(apikey, seckey, opts...)
+	})
+	mustRegister("binance", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewBinanceApi(apikey, seckey, opts...)
+	})
+	mustRegister("p2pb2b", func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewP2pb2bApi(apikey, seckey, opts...)
+	})
+}
+
 type TradeFee struct {
 	MakerFee float64
 	TakerFee float64
@@ -30,9 +62,20 @@ type PrivateClient interface {
 	Transfer(typ string, addr string,
 		amount float64, additionalFee float64) error
 	Address(c string) (string, error)
+	Capabilities() models.Capabilities
+	// Warmup loads market metadata ahead of the first call.
+	// Constructors never do network I/O, so clients load it lazily otherwise.
+	Warmup(ctx context.Context) error
+
+	// SetTransport replaces the transport of the signed requests. It may be
+	// called while requests are in flight.
+	SetTransport(transport http.RoundTripper) error
 }
 
-func NewClient(mode ClientMode, exchangeName string, apikey func() (string, error), seckey func() (string, error)) (PrivateClient, error) {
+// NewClient builds a client of the exchange with opts applied on top of its defaults.
+// The options are also used for the public client it is built on, and the
+// client takes and returns canonical asset names, see options.WithAliases.
+func NewClient(mode ClientMode, exchangeName string, apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
 	if mode == TEST {
 		m := new(MockPrivateClient)
 		retCompleteBalance := make(map[string]*models.Balance)
@@ -48,23 +91,26 @@ func NewClient(mode ClientMode, exchangeName string, apikey func() (string, erro
 		m.On("TradeFeeRate", mock.Anything, mock.Anything).Return(retTradeFeeRate, nil)
 		m.On("Address", mock.Anything).Return("", nil)
 		m.On("Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		m.On("Capabilities").Return(models.Capabilities{
+			Exchange:    exchangeName,
+			Operations:  models.Except(models.PrivateOperations),
+			OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+			TimeInForce: []models.TimeInForce{models.GTC},
+		})
+		m.On("Warmup", mock.Anything).Return(nil)
+		m.On("SetTransport", mock.Anything).Return(nil)
 		return m, nil
 	}
-	switch strings.ToLower(exchangeName) {
-	case "bitflyer":
-		return NewBitflyerPrivateApi(apikey, seckey)
-	case "poloniex":
-		return NewPoloniexApi(apikey, seckey)
-	case "hitbtc":
-		return NewHitbtcApi(apikey, seckey)
-	case "huobi":
-		return NewHuobiApi(apikey, seckey)
-	case "lbank":
-		return NewLbankApi(apikey, seckey)
-	case "kucoin":
-		return NewKucoinApi(apikey, seckey)
-	case "binance":
-		return NewBinanceApi(apikey, seckey)
+	name, factory, err := lookup(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := factory(apikey, seckey, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to init %s api", name)
+	}
+	if a := options.ResolveAliases(name, opts...); len(a) > 0 {
+		cli = withAliases(cli, a)
 	}
-	return nil, errors.New("failed to init exchange api")
+	return cli, nil
 }
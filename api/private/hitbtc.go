@@ -2,58 +2,59 @@ package private
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sync"
 	"time"
 
-	"fmt"
 	"github.com/Jeffail/gabs"
 	"github.com/antonholmquist/jason"
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
 	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/helpers"
 	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/pkg/errors"
 	"strconv"
-	"strings"
 )
 
 const (
 	HITBTC_BASE_URL = "https://api.hitbtc.com"
 )
 
-func NewHitbtcApi(apikey func() (string, error), apisecret func() (string, error)) (*HitbtcApi, error) {
-	hitbtcPublic, err := public.NewHitbtcPublicApi()
-	if err != nil {
-		return nil, err
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
+// hitbtcEnvironments are the endpoints besides production.
+var hitbtcEnvironments = map[options.Environment]options.Endpoints{
+	options.Testnet: {REST: "https://api.demo.hitbtc.com", WebSocket: "wss://api.demo.hitbtc.com/api/2/ws"},
+}
+
+func NewHitbtcApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*HitbtcApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "hitbtc",
+		Private:           true,
+		BaseURL:           HITBTC_BASE_URL,
+		WebSocketURL:      "wss://api.hitbtc.com/api/2/ws",
+		Environments:      hitbtcEnvironments,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
-	}
-
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	return &HitbtcApi{
-		BaseURL:           HITBTC_BASE_URL,
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
+
 		m: new(sync.Mutex),
 	}, nil
 }
@@ -64,15 +65,47 @@ type HitbtcApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	settlements       []string
+	symbols           symbolIndex
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (h *HitbtcApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
+	return nil
+}
+
+func (h *HitbtcApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "hitbtc",
+		Operations:  models.Except(models.PrivateOperations),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup loads the symbols which are otherwise fetched on first use.
+func (h *HitbtcApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, func() error {
+		_, err := h.symbols.load(h.publicClient)
+		return err
+	})
+}
+
+func (h *HitbtcApi) publicClient() (public.PublicClient, error) {
+	return public.NewHitbtcPublicApi(h.publicOpts...)
+}
+
 func (h *HitbtcApi) publicApiUrl(command string) string {
 	return h.BaseURL + "/api/2/public/" + command
 }
@@ -128,7 +161,7 @@ func (h *HitbtcApi) TradeFeeRates() (map[string]map[string]TradeFee, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	symbolMap,err := json.Children()
+	symbolMap, err := json.Children()
 	traderFeeMap := make(map[string]map[string]TradeFee)
 	for _, v := range symbolMap {
 		takeLiquidityRateStr, ok := v.Path("takeLiquidityRate").Data().(string)
@@ -189,7 +222,7 @@ func (h *HitbtcApi) TransferFee() (map[string]float64, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json: %v", string(resBody))
 	}
-	currencyMap,err := json.Children()
+	currencyMap, err := json.Children()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json: %v", string(resBody))
 	}
@@ -221,7 +254,7 @@ func (h *HitbtcApi) Balances() (map[string]float64, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	rateMap,err := json.Children()
+	rateMap, err := json.Children()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
@@ -255,7 +288,7 @@ func (h *HitbtcApi) CompleteBalances() (map[string]*models.Balance, error) {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 
-	rateMap,err := json.Children()
+	rateMap, err := json.Children()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
@@ -298,7 +331,7 @@ func (h *HitbtcApi) CompleteBalance(coin string) (*models.Balance, error) {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 
-	rateMap,err := json.Children()
+	rateMap, err := json.Children()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
@@ -347,12 +380,16 @@ func (h *HitbtcApi) IsOrderFilled(trading string, settlement string, orderNumber
 }
 
 func (h *HitbtcApi) ActiveOrders() ([]*models.Order, error) {
+	ix, err := h.symbols.load(h.publicClient)
+	if err != nil {
+		return nil, err
+	}
 	bs, err := h.privateApi("GET", "/api/2/order", map[string]string{})
 	if err != nil {
 		return nil, err
 	}
 	json, err := gabs.ParseJSON(bs)
-	m,err := json.Children()
+	m, err := json.Children()
 	if err != nil {
 		return nil, err
 	}
@@ -390,23 +427,15 @@ func (h *HitbtcApi) ActiveOrders() ([]*models.Order, error) {
 		if side == "buy" {
 			orderType = models.Ask
 		}
-		var settlement string
-		var trading string
-		for _, s := range h.settlements {
-			index := strings.LastIndex(symbol, s)
-			if index != 0 && index == len(symbol)-len(s) {
-				settlement = s
-				trading = symbol[0:index]
-			}
-		}
-		if settlement == "" || trading == "" {
+		pair, ok := ix.Pair(symbol)
+		if !ok {
 			continue
 		}
 		c := &models.Order{
 			ExchangeOrderID: orderId,
 			Type:            orderType,
-			Trading:         trading,
-			Settlement:      settlement,
+			Trading:         pair.Trading,
+			Settlement:      pair.Settlement,
 			Price:           price,
 			Amount:          quantity,
 		}
@@ -424,7 +453,10 @@ func (h *HitbtcApi) Order(trading string, settlement string, ordertype models.Or
 	} else {
 		return "", errors.Errorf("unknown order type %d", ordertype)
 	}
-	pair := strings.ToUpper(fmt.Sprintf("%s%s", trading, settlement))
+	pair, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	args := make(map[string]string)
 	args["side"] = cmd
 	args["symbol"] = pair
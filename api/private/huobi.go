@@ -1,6 +1,7 @@
 package private
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,47 +11,41 @@ import (
 	"time"
 
 	"github.com/antonholmquist/jason"
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
 	"github.com/xuyangcn/go-exchange-client/api/public"
 	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/pkg/errors"
 )
 
 const (
 	HUOBI_BASE_URL = "https://api.huobi.pro"
 )
 
-func NewHuobiApi(apikey func() (string, error), apisecret func() (string, error)) (*HuobiApi, error) {
-	hitbtcPublic, err := public.NewHuobiPublicApi()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize public client")
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
+func NewHuobiApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*HuobiApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "huobi",
+		Private:           true,
+		BaseURL:           HUOBI_BASE_URL,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pairs")
-	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
+		return nil, err
 	}
-
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	return &HuobiApi{
-		BaseURL:           HUOBI_BASE_URL,
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		rt:                &http.Transport{},
+		rt:                cli.Transport,
+
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
 
 		m: new(sync.Mutex),
 	}, nil
@@ -62,16 +57,46 @@ type HuobiApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	rt                *http.Transport
+	rt                http.RoundTripper
 	settlements       []string
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (h *HuobiApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
+	return nil
+}
+
+func (h *HuobiApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "huobi",
+		Operations:  models.Except(models.PrivateOperations, models.OpActiveOrders),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup does nothing as the client has no market metadata to load.
+func (h *HuobiApi) Warmup(ctx context.Context) error {
+	return nil
+}
+
+func (h *HuobiApi) publicClient() (public.PublicClient, error) {
+	return public.NewHuobiPublicApi(h.publicOpts...)
+}
+
 func (h *HuobiApi) privateApiUrl() string {
 	return h.BaseURL
 }
@@ -95,12 +120,12 @@ func (h *HuobiApi) privateApi(method string, path string, params *url.Values) ([
 	sign, _ := GetParamHmacSHA256Base64Sign(secretKey, payload)
 	params.Set("Signature", sign)
 	urlStr := h.BaseURL + path + "?" + params.Encode()
-	resBody, err := NewHttpRequest(&http.Client{}, method, urlStr, "", nil)
+	resBody, err := NewHttpRequest(&h.HttpClient, method, urlStr, "", nil)
 	return resBody, err
 }
 
 func (h *HuobiApi) TradeFeeRates() (map[string]map[string]TradeFee, error) {
-	cli, err := public.NewClient("huobi")
+	cli, err := public.NewClient("huobi", h.publicOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -483,6 +508,10 @@ func (h *HuobiApi) Order(trading string, settlement string, ordertype models.Ord
 	if err != nil {
 		return "", err
 	}
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	params := &url.Values{}
 	if ordertype == models.Ask {
 		params.Set("type", "buy-limit")
@@ -491,7 +520,7 @@ func (h *HuobiApi) Order(trading string, settlement string, ordertype models.Ord
 	} else {
 		return "", errors.Errorf("unknown order type %d", ordertype)
 	}
-	params.Set("symbol", strings.ToLower(fmt.Sprintf("%s%s", trading, settlement)))
+	params.Set("symbol", symbol)
 	params.Set("account-id", accountId)
 	amountStr := strconv.FormatFloat(amount, 'f', 4, 64)
 	priceStr := strconv.FormatFloat(price, 'f', 4, 64)
@@ -501,7 +530,6 @@ func (h *HuobiApi) Order(trading string, settlement string, ordertype models.Ord
 	if err != nil {
 		return "", err
 	}
-	fmt.Println("===huobi:",string(byteArray))
 	json, err := jason.NewObjectFromBytes(byteArray)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to parse json")
@@ -574,7 +602,6 @@ func (h *HuobiApi) Address(c string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "failed to parse json")
 	}
-	fmt.Println(json)
 	address, err := json.GetString("data")
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to take address of %s", c)
@@ -1,6 +1,7 @@
 package private
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -16,48 +17,49 @@ import (
 	"strings"
 
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/public"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
 )
 
 const (
 	KUCOIN_BASE_URL = "https://api.kucoin.com"
 )
 
-func NewKucoinApi(apikey func() (string, error), apisecret func() (string, error)) (*KucoinApi, error) {
-	hitbtcPublic, err := public.NewKucoinPublicApi()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize public client")
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pairs")
-	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
-	}
+// kucoinEnvironments are the endpoints besides production.
+var kucoinEnvironments = map[options.Environment]options.Endpoints{
+	options.Testnet: {REST: "https://openapi-sandbox.kucoin.com", WebSocket: ""},
+}
 
-	return &KucoinApi{
+func NewKucoinApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*KucoinApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "kucoin",
+		Private:           true,
 		BaseURL:           KUCOIN_BASE_URL,
+		Environments:      kucoinEnvironments,
 		RateCacheDuration: 30 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
+	return &KucoinApi{
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		rt:                &http.Transport{},
+		rt:                cli.Transport,
+
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
 
 		m: new(sync.Mutex),
 	}, nil
@@ -69,17 +71,48 @@ type KucoinApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	rt                *http.Transport
+	rt                http.RoundTripper
 	settlements       []string
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	precisionMap    map[string]map[string]models.Precisions
+	precisionM      sync.Mutex
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (h *KucoinApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
+	return nil
+}
+
+func (h *KucoinApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "kucoin",
+		Operations:  models.Except(models.PrivateOperations, models.OpActiveOrders, models.OpAddress),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup loads the precisions which are otherwise fetched on first use.
+func (h *KucoinApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision)
+}
+
+func (h *KucoinApi) publicClient() (public.PublicClient, error) {
+	return public.NewKucoinPublicApi(h.publicOpts...)
+}
+
 func (h *KucoinApi) privateApiUrl() string {
 	return h.BaseURL
 }
@@ -98,6 +131,8 @@ func requestGetAsChrome(url string) (*http.Request, error) {
 }
 
 func (h *KucoinApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
@@ -121,8 +156,6 @@ func (h *KucoinApi) fetchPrecision() error {
 		coinPrecision[v.Get("currency").Str] = int(v.Get("precision").Int())
 	}
 
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url = h.publicApiUrl("/api/v1/market/allTickers")
 	req, err = requestGetAsChrome(url)
 	if err != nil {
@@ -138,6 +171,10 @@ func (h *KucoinApi) fetchPrecision() error {
 		return errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	value = gjson.ParseBytes(byteArray)
+	if !value.Get("data.ticker").IsArray() {
+		return errors.Errorf("failed to fetch %s: %s", url, byteArray)
+	}
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("data.ticker").Array() {
 		currencies := strings.Split(v.Get("symbol").Str, "-")
 		if len(currencies) < 2 {
@@ -146,17 +183,18 @@ func (h *KucoinApi) fetchPrecision() error {
 		trading := currencies[0]
 		settlement := currencies[1]
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  coinPrecision[settlement],
 			AmountPrecision: coinPrecision[trading],
 		}
 	}
-	return errors.Wrapf(err, "failed to fetch %s", url)
+	h.precisionMap = precisionMap
+	return nil
 }
 
 func (h *KucoinApi) precise(trading string, settlement string) (*models.Precisions, error) {
@@ -164,7 +202,11 @@ func (h *KucoinApi) precise(trading string, settlement string) (*models.Precisio
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s missing trading", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -470,7 +512,10 @@ func (h *KucoinApi) Order(trading string, settlement string, ordertype models.Or
 	params.Set("price", FloorFloat64ToStr(price, precise.PricePrecision))
 	params.Set("amount", FloorFloat64ToStr(amount, precise.AmountPrecision))
 
-	symbol := strings.ToUpper(fmt.Sprintf("%s-%s", trading, settlement))
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	params.Set("symbol", symbol)
 	byteArray, err := h.privateApi("POST", "/v1/order", params)
 	if err != nil {
@@ -504,8 +549,12 @@ func (h *KucoinApi) Transfer(typ string, addr string, amount float64, additional
 
 func (h *KucoinApi) CancelOrder(trading string, settlement string,
 	ordertype models.OrderType, orderNumber string) error {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return err
+	}
 	params := &url.Values{}
-	params.Set("symbol", trading+"-"+settlement)
+	params.Set("symbol", symbol)
 	params.Set("orderOid", orderNumber)
 	if ordertype == models.Ask {
 		params.Set("type", "BUY")
@@ -531,8 +580,12 @@ func (h *KucoinApi) CancelOrder(trading string, settlement string,
 }
 
 func (h *KucoinApi) IsOrderFilled(trading string, settlement string, orderNumber string) (bool, error) {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return false, err
+	}
 	params := &url.Values{}
-	params.Set("symbol", trading+"-"+settlement)
+	params.Set("symbol", symbol)
 	bs, err := h.privateApi("GET", "/v1/order/active", params)
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to cancel order")
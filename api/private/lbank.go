@@ -1,6 +1,7 @@
 package private
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -8,11 +9,11 @@ import (
 	"time"
 
 	"bytes"
-	"fmt"
 	"github.com/antonholmquist/jason"
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
 	"github.com/xuyangcn/go-exchange-client/api/public"
 	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/pkg/errors"
 	"strconv"
 	"strings"
 )
@@ -21,38 +22,31 @@ const (
 	LBANK_BASE_URL = "https://api.lbkex.com"
 )
 
-func NewLbankApi(apikey func() (string, error), apisecret func() (string, error)) (*LbankApi, error) {
-	hitbtcPublic, err := public.NewLbankPublicApi()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize public client")
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
+func NewLbankApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*LbankApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "lbank",
+		Private:           true,
+		BaseURL:           LBANK_BASE_URL,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pairs")
-	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
+		return nil, err
 	}
-
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	return &LbankApi{
-		BaseURL:           LBANK_BASE_URL,
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		rt:                &http.Transport{},
+		rt:                cli.Transport,
+
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
 
 		m: new(sync.Mutex),
 	}, nil
@@ -64,16 +58,46 @@ type LbankApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	rt                *http.Transport
+	rt                http.RoundTripper
 	settlements       []string
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (h *LbankApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
+	return nil
+}
+
+func (h *LbankApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "lbank",
+		Operations:  models.Except(models.PrivateOperations, models.OpActiveOrders, models.OpAddress),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup does nothing as the client has no market metadata to load.
+func (h *LbankApi) Warmup(ctx context.Context) error {
+	return nil
+}
+
+func (h *LbankApi) publicClient() (public.PublicClient, error) {
+	return public.NewLbankPublicApi(h.publicOpts...)
+}
+
 func (h *LbankApi) privateApiUrl() string {
 	return h.BaseURL
 }
@@ -121,7 +145,7 @@ func (h *LbankApi) privateApi(method string, path string, params *url.Values) ([
 }
 
 func (h *LbankApi) TradeFeeRates() (map[string]map[string]TradeFee, error) {
-	cli, err := public.NewClient("lbank")
+	cli, err := public.NewClient("lbank", h.publicOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +194,7 @@ func (sm *lbankTransferFeeSyncMap) GetAll() map[string]float64 {
 }
 
 func (h *LbankApi) TransferFee() (map[string]float64, error) {
-	url := LBANK_BASE_URL + "/v1/withdrawConfigs.do"
+	url := h.BaseURL + "/v1/withdrawConfigs.do"
 	resp, err := h.HttpClient.Get(url)
 	transferFeeMap := lbankTransferFeeSyncMap{make(lbankTransferFeeMap), new(sync.Mutex)}
 	if err != nil {
@@ -360,6 +384,10 @@ func (h *LbankApi) ActiveOrders() ([]*models.Order, error) {
 }
 
 func (h *LbankApi) Order(trading string, settlement string, ordertype models.OrderType, price float64, amount float64) (string, error) {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	params := &url.Values{}
 	if ordertype == models.Ask {
 		params.Set("type", "buy")
@@ -368,7 +396,7 @@ func (h *LbankApi) Order(trading string, settlement string, ordertype models.Ord
 	} else {
 		return "", errors.Errorf("unknown order type %d", ordertype)
 	}
-	params.Set("symbol", strings.ToLower(fmt.Sprintf("%s_%s", trading, settlement)))
+	params.Set("symbol", symbol)
 	amountStr := strconv.FormatFloat(amount, 'f', 4, 64)
 	priceStr := strconv.FormatFloat(price, 'f', 4, 64)
 	params.Set("amount", amountStr)
@@ -402,10 +430,14 @@ func (h *LbankApi) Transfer(typ string, addr string, amount float64, additionalF
 
 func (h *LbankApi) CancelOrder(trading string, settlement string,
 	ordertype models.OrderType, orderNumber string) error {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return err
+	}
 	params := &url.Values{}
 	params.Set("order_id", orderNumber)
-	params.Set("symbol", trading+settlement)
-	_, err := h.privateApi("POST", "/v1/cancel_order.do", params)
+	params.Set("symbol", symbol)
+	_, err = h.privateApi("POST", "/v1/cancel_order.do", params)
 	if err != nil {
 		return errors.Wrapf(err, "failed to cancel order")
 	}
@@ -413,9 +445,13 @@ func (h *LbankApi) CancelOrder(trading string, settlement string,
 }
 
 func (h *LbankApi) IsOrderFilled(trading string, settlement string, orderNumber string) (bool, error) {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return false, err
+	}
 	params := &url.Values{}
 	params.Set("order_id", orderNumber)
-	params.Set("symbol", trading+settlement)
+	params.Set("symbol", symbol)
 	bs, err := h.privateApi("POST", "/v1/orders_info.do", params)
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to cancel order")
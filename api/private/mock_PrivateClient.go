@@ -1,6 +1,8 @@
 // Code generated by mockery v1.0.0
 package private
 
+import context "context"
+import http "net/http"
 import mock "github.com/stretchr/testify/mock"
 import models "github.com/xuyangcn/go-exchange-client/models"
 
@@ -90,6 +92,20 @@ func (_m *MockPrivateClient) CancelOrder(trading string, settlement string, orde
 	return r0
 }
 
+// Capabilities provides a mock function with given fields:
+func (_m *MockPrivateClient) Capabilities() models.Capabilities {
+	ret := _m.Called()
+
+	var r0 models.Capabilities
+	if rf, ok := ret.Get(0).(func() models.Capabilities); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(models.Capabilities)
+	}
+
+	return r0
+}
+
 // CompleteBalance provides a mock function with given fields: coin
 func (_m *MockPrivateClient) CompleteBalance(coin string) (*models.Balance, error) {
 	ret := _m.Called(coin)
@@ -258,3 +274,31 @@ func (_m *MockPrivateClient) TransferFee() (map[string]float64, error) {
 
 	return r0, r1
 }
+
+// SetTransport provides a mock function with given fields: transport
+func (_m *MockPrivateClient) SetTransport(transport http.RoundTripper) error {
+	ret := _m.Called(transport)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(http.RoundTripper) error); ok {
+		r0 = rf(transport)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Warmup provides a mock function with given fields: ctx
+func (_m *MockPrivateClient) Warmup(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -1,6 +1,7 @@
 package private
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -9,9 +10,10 @@ import (
 
 	"fmt"
 	"github.com/antonholmquist/jason"
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
 	"github.com/xuyangcn/go-exchange-client/api/public"
 	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/pkg/errors"
 	"strconv"
 	"strings"
 )
@@ -20,38 +22,31 @@ const (
 	OKEX_BASE_URL = "https://www.okex.com"
 )
 
-func NewOkexApi(apikey func() (string, error), apisecret func() (string, error)) (*OkexApi, error) {
-	hitbtcPublic, err := public.NewOkexPublicApi()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize public client")
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
+func NewOkexApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*OkexApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "okex",
+		Private:           true,
+		BaseURL:           OKEX_BASE_URL,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pairs")
-	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
+		return nil, err
 	}
-
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	return &OkexApi{
-		BaseURL:           OKEX_BASE_URL,
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		rt:                &http.Transport{},
+		rt:                cli.Transport,
+
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
 
 		m: new(sync.Mutex),
 	}, nil
@@ -63,16 +58,46 @@ type OkexApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	rt                *http.Transport
+	rt                http.RoundTripper
 	settlements       []string
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (o *OkexApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&o.HttpClient, o.opts.WrapTransport(transport))
+	return nil
+}
+
+func (o *OkexApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "okex",
+		Operations:  models.Except(models.PrivateOperations, models.OpActiveOrders),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup does nothing as the client has no market metadata to load.
+func (o *OkexApi) Warmup(ctx context.Context) error {
+	return nil
+}
+
+func (o *OkexApi) publicClient() (public.PublicClient, error) {
+	return public.NewOkexPublicApi(o.publicOpts...)
+}
+
 func (o *OkexApi) privateApiUrl() string {
 	return o.BaseURL
 }
@@ -96,12 +121,12 @@ func (o *OkexApi) privateApi(method string, path string, params *url.Values) ([]
 	sign, _ := GetParamHmacSHA256Base64Sign(secretKey, payload)
 	params.Set("Signature", sign)
 	urlStr := o.BaseURL + path + "?" + params.Encode()
-	resBody, err := NewHttpRequest(&http.Client{}, method, urlStr, "", nil)
+	resBody, err := NewHttpRequest(&o.HttpClient, method, urlStr, "", nil)
 	return resBody, err
 }
 
 func (o *OkexApi) TradeFeeRates() (map[string]map[string]TradeFee, error) {
-	cli, err := public.NewClient("okex")
+	cli, err := public.NewClient("okex", o.publicOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +175,7 @@ func (sm *okexTransferFeeSyncMap) GetAll() map[string]float64 {
 }
 
 func (o *OkexApi) TransferFee() (map[string]float64, error) {
-	cli, err := public.NewClient("okex")
+	cli, err := public.NewClient("okex", o.publicOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -419,6 +444,10 @@ func (o *OkexApi) Order(trading string, settlement string, ordertype models.Orde
 	if err != nil {
 		return "", err
 	}
+	symbol, err := o.symbols.symbol(o.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	params := &url.Values{}
 	if ordertype == models.Ask {
 		params.Set("type", "buy-limit")
@@ -427,7 +456,7 @@ func (o *OkexApi) Order(trading string, settlement string, ordertype models.Orde
 	} else {
 		return "", errors.Errorf("unknown order type %d", ordertype)
 	}
-	params.Set("symbol", strings.ToLower(fmt.Sprintf("%s%s", trading, settlement)))
+	params.Set("symbol", symbol)
 	params.Set("account-id", accountId)
 	amountStr := strconv.FormatFloat(amount, 'f', 4, 64)
 	priceStr := strconv.FormatFloat(price, 'f', 4, 64)
@@ -509,7 +538,6 @@ func (o *OkexApi) Address(c string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "failed to parse json")
 	}
-	fmt.Println(json)
 	address, err := json.GetString("data")
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to take address of %s", c)
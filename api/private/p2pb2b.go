@@ -1,6 +1,7 @@
 package private
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -17,48 +18,43 @@ import (
 
 	"github.com/Jeffail/gabs"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/public"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
 )
 
 const (
 	P2PB2B_BASE_URL = "https://api.p2pb2b.io/api/v1"
 )
 
-func NewP2pb2bApi(apikey func() (string, error), apisecret func() (string, error)) (*P2pb2bApi, error) {
-	hitbtcPublic, err := public.NewP2pb2bPublicApi()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to initialize public client")
-	}
-	pairs, err := hitbtcPublic.CurrencyPairs()
+func NewP2pb2bApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*P2pb2bApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "p2pb2b",
+		Private:           true,
+		BaseURL:           P2PB2B_BASE_URL,
+		RateCacheDuration: 30 * time.Second,
+	}, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get pairs")
-	}
-	var settlements []string
-	for _, v := range pairs {
-		settlements = append(settlements, v.Settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
-		}
+		return nil, err
 	}
-
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	return &P2pb2bApi{
-		BaseURL:           P2PB2B_BASE_URL,
-		RateCacheDuration: 30 * time.Second,
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
-		settlements:       uniq,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		rt:                &http.Transport{},
+		rt:                cli.Transport,
+
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
 
 		m: new(sync.Mutex),
 	}, nil
@@ -70,17 +66,48 @@ type P2pb2bApi struct {
 	BaseURL           string
 	RateCacheDuration time.Duration
 	HttpClient        http.Client
-	rt                *http.Transport
+	rt                http.RoundTripper
 	settlements       []string
 
 	volumeMap       map[string]map[string]float64
 	rateMap         map[string]map[string]float64
 	precisionMap    map[string]map[string]models.Precisions
+	precisionM      sync.Mutex
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (h *P2pb2bApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
+	return nil
+}
+
+func (h *P2pb2bApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "p2pb2b",
+		Operations:  models.Except(models.PrivateOperations, models.OpActiveOrders, models.OpAddress),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup loads the precisions which are otherwise fetched on first use.
+func (h *P2pb2bApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision)
+}
+
+func (h *P2pb2bApi) publicClient() (public.PublicClient, error) {
+	return public.NewP2pb2bPublicApi(h.publicOpts...)
+}
+
 func (h *P2pb2bApi) privateApiUrl() string {
 	return h.BaseURL
 }
@@ -90,10 +117,11 @@ func (h *P2pb2bApi) publicApiUrl(command string) string {
 }
 
 func (h *P2pb2bApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
 
 	url := h.publicApiUrl("public/tickers")
 	resp, err := h.HttpClient.Get(url)
@@ -114,6 +142,7 @@ func (h *P2pb2bApi) fetchPrecision() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse json")
 	}
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for k, v := range rateMap {
 		coins := strings.Split(k, "_")
 		if len(coins) != 2 {
@@ -143,16 +172,17 @@ func (h *P2pb2bApi) fetchPrecision() error {
 			return err
 		}
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  public.Precision(last),
 			AmountPrecision: public.Precision(volume),
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
@@ -161,7 +191,11 @@ func (h *P2pb2bApi) precise(trading string, settlement string) (*models.Precisio
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s missing trading", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -727,7 +761,10 @@ func (h *P2pb2bApi) Order(trading string, settlement string, ordertype models.Or
 	params.Set("price", FloorFloat64ToStr(price, precise.PricePrecision))
 	params.Set("amount", FloorFloat64ToStr(amount, precise.AmountPrecision))
 
-	symbol := strings.ToUpper(fmt.Sprintf("%s-%s", trading, settlement))
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 	params.Set("symbol", symbol)
 	byteArray, err := h.privateApi("POST", "/v1/order", params)
 	if err != nil {
@@ -761,8 +798,12 @@ func (h *P2pb2bApi) Transfer(typ string, addr string, amount float64, additional
 
 func (h *P2pb2bApi) CancelOrder(trading string, settlement string,
 	ordertype models.OrderType, orderNumber string) error {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return err
+	}
 	params := &url.Values{}
-	params.Set("symbol", trading+"-"+settlement)
+	params.Set("symbol", symbol)
 	params.Set("orderOid", orderNumber)
 	if ordertype == models.Ask {
 		params.Set("type", "BUY")
@@ -788,8 +829,12 @@ func (h *P2pb2bApi) CancelOrder(trading string, settlement string,
 }
 
 func (h *P2pb2bApi) IsOrderFilled(trading string, settlement string, orderNumber string) (bool, error) {
+	symbol, err := h.symbols.symbol(h.publicClient, trading, settlement)
+	if err != nil {
+		return false, err
+	}
 	params := &url.Values{}
-	params.Set("symbol", trading+"-"+settlement)
+	params.Set("symbol", symbol)
 	bs, err := h.privateApi("GET", "/v1/order/active", params)
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to cancel order")
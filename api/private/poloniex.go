@@ -2,11 +2,11 @@ package private
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -15,9 +15,10 @@ import (
 	"time"
 
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/logger"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/models"
 	"strings"
 )
 
@@ -25,16 +26,31 @@ const (
 	POLONIEX_BASE_URL = "https://poloniex.com"
 )
 
-func NewPoloniexApi(apikey func() (string, error), apisecret func() (string, error)) (*PoloniexApi, error) {
-	return &PoloniexApi{
+func NewPoloniexApi(apikey func() (string, error), apisecret func() (string, error), opts ...options.Option) (*PoloniexApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "poloniex",
+		Private:           true,
 		BaseURL:           POLONIEX_BASE_URL,
 		RateCacheDuration: 7 * 24 * time.Hour,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
+	return &PoloniexApi{
+		BaseURL:           o.BaseURL,
+		RateCacheDuration: o.RateCacheDuration,
 		ApiKeyFunc:        apikey,
 		SecretKeyFunc:     apisecret,
 		rateMap:           nil,
 		volumeMap:         nil,
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 
+		HttpClient: *cli,
+		opts:       o,
+		publicOpts: opts,
+
 		m: new(sync.Mutex),
 	}, nil
 }
@@ -50,9 +66,35 @@ type PoloniexApi struct {
 	rateMap         map[string]map[string]float64
 	rateLastUpdated time.Time
 
+	opts       *options.Options
+	publicOpts []options.Option
+	symbols    symbolIndex
+
 	m *sync.Mutex
 }
 
+// SetTransport replaces the transport of the signed requests, e.g. to send them
+// from a dedicated IP. The rate limiter and middlewares are kept, and requests
+// in flight finish on the old transport.
+func (p *PoloniexApi) SetTransport(transport http.RoundTripper) error {
+	options.SetTransport(&p.HttpClient, p.opts.WrapTransport(transport))
+	return nil
+}
+
+func (p *PoloniexApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:    "poloniex",
+		Operations:  models.Except(models.PrivateOperations),
+		OrderTypes:  []models.OrderType{models.Ask, models.Bid},
+		TimeInForce: []models.TimeInForce{models.GTC},
+	}
+}
+
+// Warmup does nothing as the client has no market metadata to load.
+func (p *PoloniexApi) Warmup(ctx context.Context) error {
+	return nil
+}
+
 func parsePoloCurrencyPair(s string) (string, string, error) {
 	xs := strings.Split(s, "_")
 
@@ -67,6 +109,10 @@ func (p *PoloniexApi) baseUrl() string {
 	return p.BaseURL
 }
 
+func (p *PoloniexApi) publicClient() (public.PublicClient, error) {
+	return public.NewPoloniexPublicApi(p.publicOpts...)
+}
+
 func (p *PoloniexApi) privateApiUrl() string {
 	return p.BaseURL
 }
@@ -142,7 +188,7 @@ func (p *PoloniexApi) fetchRate() error {
 	for k, v := range rateMap {
 		settlement, trading, err := parsePoloCurrencyPair(k)
 		if err != nil {
-			logger.Get().Warn("couldn't parse currency pair", err)
+			p.opts.Log().Warnw("couldn't parse currency pair", "pair", k, "error", err)
 			continue
 		}
 
@@ -195,7 +241,7 @@ func (p *PoloniexApi) TradeFeeRates() (map[string]map[string]TradeFee, error) {
 	defer p.m.Unlock()
 
 	now := time.Now()
-	if now.Sub(p.rateLastUpdated) >= p.RateCacheDuration {
+	if !p.opts.CacheHit("rate", now.Sub(p.rateLastUpdated) < p.RateCacheDuration) {
 		err := p.fetchRate()
 		if err != nil {
 			return nil, errors.Wrap(err, "aa")
@@ -488,7 +534,10 @@ func (p *PoloniexApi) Order(trading string, settlement string, ordertype models.
 		return "", errors.Errorf("unknown order type %d", ordertype)
 	}
 
-	pair := fmt.Sprintf("%s_%s", settlement, trading)
+	pair, err := p.symbols.symbol(p.publicClient, trading, settlement)
+	if err != nil {
+		return "", err
+	}
 
 	args := make(map[string]string)
 	args["currencyPair"] = pair
@@ -1,11 +1,16 @@
 package private
 
 import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
 	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -35,6 +40,16 @@ func (rt *FakeRoundTripper) Reset() {
 	rt.requests = nil
 }
 
+// offlineRoundTripper fails every request.
+type offlineRoundTripper struct {
+	requests int32
+}
+
+func (rt *offlineRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&rt.requests, 1)
+	return nil, errors.New("offline")
+}
+
 func TestNewClient(t *testing.T) {
 	apiFunc := func() (string, error) { return "APIKEY", nil }
 	secFunc := func() (string, error) { return "SECKEY", nil }
@@ -52,6 +67,124 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestRegister(t *testing.T) {
+	apiFunc := func() (string, error) { return "APIKEY", nil }
+	secFunc := func() (string, error) { return "SECKEY", nil }
+	factory := func(apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (PrivateClient, error) {
+		return NewPoloniexApi(apikey, seckey)
+	}
+	if err := Register("FakeExchange", factory, "fakeex"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register("fakeexchange", factory); err == nil {
+		t.Errorf("Register: Expected error on duplicate name")
+	}
+	cli, err := NewClient(PROJECT, "FakeEx", apiFunc, secFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cli.(*PoloniexApi); !ok {
+		t.Errorf("Register: Expected *PoloniexApi. Got %T", cli)
+	}
+	for _, name := range []string{"okex", "p2pb2b"} {
+		if _, err := Resolve(name); err != nil {
+			t.Errorf("Register: %s is not registered", name)
+		}
+	}
+	if _, err := NewClient(PROJECT, "nosuchexchange", apiFunc, secFunc); errors.Cause(err) != ErrUnknownExchange {
+		t.Errorf("NewClient: Expected %v. Got %v", ErrUnknownExchange, err)
+	}
+}
+
+func TestNewClientOffline(t *testing.T) {
+	apiFunc := func() (string, error) { return "APIKEY", nil }
+	secFunc := func() (string, error) { return "SECKEY", nil }
+	rt := &FakeRoundTripper{status: http.StatusOK}
+	for _, name := range Exchanges() {
+		if _, err := NewClient(PROJECT, name, apiFunc, secFunc, options.WithHTTPClient(&http.Client{Transport: rt})); err != nil {
+			t.Fatalf("NewClient(%s): %v", name, err)
+		}
+	}
+	if len(rt.requests) != 0 {
+		t.Errorf("NewClient: Expected no requests. Got %d", len(rt.requests))
+	}
+}
+
+func TestSetTransport(t *testing.T) {
+	// kucoin keys carry the passphrase
+	apiFunc := func() (string, error) { return "PHRASE::APIKEY", nil }
+	secFunc := func() (string, error) { return "SECKEY", nil }
+	for _, name := range Exchanges() {
+		unused := &FakeRoundTripper{status: http.StatusOK, message: "{}"}
+		cli, err := NewClient(PROJECT, name, apiFunc, secFunc, options.WithHTTPClient(&http.Client{Transport: unused}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rt := &offlineRoundTripper{}
+		if err := cli.SetTransport(rt); err != nil {
+			t.Fatalf("SetTransport(%s): %v", name, err)
+		}
+		if _, err := cli.Balances(); err == nil {
+			t.Errorf("SetTransport(%s): Expected an error from the new transport", name)
+		}
+		if atomic.LoadInt32(&rt.requests) == 0 {
+			t.Errorf("SetTransport(%s): Expected signed requests through the new transport", name)
+		}
+	}
+}
+
+func TestSetTransportInFlight(t *testing.T) {
+	apiFunc := func() (string, error) { return "PHRASE::APIKEY", nil }
+	secFunc := func() (string, error) { return "SECKEY", nil }
+	for _, name := range Exchanges() {
+		old := &offlineRoundTripper{}
+		cli, err := NewClient(PROJECT, name, apiFunc, secFunc, options.WithHTTPClient(&http.Client{Transport: old}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rt := &offlineRoundTripper{}
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				cli.Balances()
+			}()
+			go func() {
+				defer wg.Done()
+				if err := cli.SetTransport(rt); err != nil {
+					t.Errorf("SetTransport(%s): %v", name, err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	client := newTestPrivateClient("bitflyer", &FakeRoundTripper{status: http.StatusOK})
+	capabilities := client.Capabilities()
+	if capabilities.Supports(models.OpTransfer) || capabilities.Supports(models.OpAddress) {
+		t.Errorf("BitflyerPrivateApi: Transfer and Address must not be supported")
+	}
+	if !capabilities.Supports(models.OpOrder) || !capabilities.SupportsOrderType(models.Bid) {
+		t.Errorf("BitflyerPrivateApi: Order must be supported")
+	}
+}
+
+// listSymbol preloads the symbol index of a client, which is otherwise
+// fetched from the public API on first use.
+func listSymbol(s *symbolIndex, symbol, trading, settlement string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ix == nil {
+		s.ix = symbols.New()
+	}
+	if err := s.ix.Add(symbol, models.CurrencyPair{Trading: trading, Settlement: settlement}); err != nil {
+		panic(err)
+	}
+}
+
 func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClient {
 	apiFunc := func() (string, error) { return "APIKEY", nil }
 	secFunc := func() (string, error) { return "SECKEY", nil }
@@ -62,7 +195,7 @@ func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClie
 		n["JPY"] = 10000
 		m := make(map[string]map[string]float64)
 		m["BTC"] = n
-		return &BitflyerApi{
+		api := &BitflyerApi{
 			ApikeyFunc:        apiFunc,
 			ApiSecretFunc:     secFunc,
 			BaseURL:           endpoint,
@@ -73,12 +206,14 @@ func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClie
 			rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 			m:                 new(sync.Mutex),
 		}
+		listSymbol(&api.symbols, "BTC_JPY", "BTC", "JPY")
+		return api
 	case "poloniex":
 		n := make(map[string]float64)
 		n["BTC"] = 0.1
 		m := make(map[string]map[string]float64)
 		m["ETH"] = n
-		return &PoloniexApi{
+		api := &PoloniexApi{
 			ApiKeyFunc:        apiFunc,
 			SecretKeyFunc:     secFunc,
 			BaseURL:           endpoint,
@@ -89,21 +224,24 @@ func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClie
 			rateLastUpdated:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
 			m:                 new(sync.Mutex),
 		}
+		listSymbol(&api.symbols, "BTC_ETH", "ETH", "BTC")
+		return api
 	case "hitbtc":
-		return &HitbtcApi{
+		api := &HitbtcApi{
 			ApiKeyFunc:        apiFunc,
 			SecretKeyFunc:     secFunc,
 			BaseURL:           endpoint,
 			RateCacheDuration: 30 * time.Second,
 			HttpClient:        http.Client{Transport: rt},
-			settlements:       []string{"BTC"},
 			rateMap:           nil,
 			volumeMap:         nil,
 			rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 			m:                 new(sync.Mutex),
 		}
+		listSymbol(&api.symbols, "ETHBTC", "ETH", "BTC")
+		return api
 	case "lbank":
-		return &LbankApi{
+		api := &LbankApi{
 			ApiKeyFunc:        apiFunc,
 			SecretKeyFunc:     secFunc,
 			BaseURL:           endpoint,
@@ -115,8 +253,10 @@ func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClie
 			rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 			m:                 new(sync.Mutex),
 		}
+		listSymbol(&api.symbols, "eth_btc", "ETH", "BTC")
+		return api
 	case "kucoin":
-		return &KucoinApi{
+		api := &KucoinApi{
 			ApiKeyFunc:        apiFunc,
 			SecretKeyFunc:     secFunc,
 			BaseURL:           endpoint,
@@ -128,8 +268,10 @@ func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClie
 			rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 			m:                 new(sync.Mutex),
 		}
+		listSymbol(&api.symbols, "ETH-BTC", "ETH", "BTC")
+		return api
 	case "binance":
-		return &BinanceApi{
+		api := &BinanceApi{
 			ApiKeyFunc:        apiFunc,
 			SecretKeyFunc:     secFunc,
 			BaseURL:           endpoint,
@@ -142,6 +284,8 @@ func newTestPrivateClient(exchangeName string, rt http.RoundTripper) PrivateClie
 			m:                 new(sync.Mutex),
 			currencyM:         new(sync.Mutex),
 		}
+		listSymbol(&api.symbols, "ETHBTC", "ETH", "BTC")
+		return api
 	}
 	return nil
 }
@@ -560,6 +704,7 @@ func TestKucoinOrder(t *testing.T) {
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 		m:                 new(sync.Mutex),
 	}
+	listSymbol(&client.symbols, "ETH-BTC", "ETH", "BTC")
 	rt.message = json
 	orderId, err := client.Order("ETH", "BTC", models.Bid, 1000000, 0.01)
 	if err != nil {
@@ -613,6 +758,7 @@ func TestBinanceOrder(t *testing.T) {
 		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 		m:                 new(sync.Mutex),
 	}
+	listSymbol(&client.symbols, "ETHBTC", "ETH", "BTC")
 	rt.message = json
 	orderId, err := client.Order("ETH", "BTC", models.Bid, 1000000, 0.01)
 	if err != nil {
@@ -627,3 +773,66 @@ func TestBinanceOrder(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestBinanceFee(t *testing.T) {
+	t.Parallel()
+	json := `{"makerCommission":10,"takerCommission":20,"buyerCommission":0,"sellerCommission":0,"canTrade":true,"balances":[]}`
+	rt := &FakeRoundTripper{message: json, status: http.StatusOK}
+	client := newTestPrivateClient("binance", rt).(*BinanceApi)
+	client.currencyPairs = []models.CurrencyPair{{Trading: "ETH", Settlement: "BTC"}}
+	fee, err := client.TradeFeeRate("ETH", "BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.MakerFee != 0.001 || fee.TakerFee != 0.002 {
+		t.Errorf("BinanceApi: Expected %v %v. Got %v %v", 0.001, 0.002, fee.MakerFee, fee.TakerFee)
+	}
+	req := rt.requests[len(rt.requests)-1]
+	if req.Header.Get("X-MBX-APIKEY") != "APIKEY" || req.URL.Query().Get("signature") == "" {
+		t.Errorf("BinanceApi: Expected a signed request. Got %s", req.URL)
+	}
+	if _, err := client.TradeFeeRate("XRP", "BTC"); err == nil {
+		t.Errorf("BinanceApi: Expected an error for an unknown pair")
+	}
+}
+
+func TestBinanceTimeSync(t *testing.T) {
+	t.Parallel()
+	rt := &FakeRoundTripper{message: "{}", status: http.StatusInternalServerError}
+	client := newTestPrivateClient("binance", rt).(*BinanceApi)
+	if err := client.syncTime(); err == nil {
+		t.Fatal("BinanceApi: Expected an error from the failed sync")
+	}
+	ahead := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	rt.status = http.StatusOK
+	rt.message = fmt.Sprintf(`{"serverTime":%d}`, ahead)
+	if err := client.syncTime(); err != nil {
+		t.Fatalf("BinanceApi: Expected the sync to be retried. Got %v", err)
+	}
+	if offset := time.Duration(client.timeOffset()); offset < 59*time.Minute {
+		t.Errorf("BinanceApi: Expected an offset of an hour. Got %v", offset)
+	}
+	rt.Reset()
+	if err := client.syncTime(); err != nil || len(rt.requests) != 0 {
+		t.Errorf("BinanceApi: Expected no request after a sync. Got %d, %v", len(rt.requests), err)
+	}
+}
+
+func TestBinancePrecisionRetry(t *testing.T) {
+	t.Parallel()
+	jsonPrecision := `{"symbols":[{"symbol":"ETHBTC","baseAsset":"ETH","baseAssetPrecision":8,"quoteAsset":"BTC","quotePrecision":6}]}`
+	rt := &FakeRoundTripper{message: "<html>unavailable</html>", status: http.StatusServiceUnavailable}
+	client := newTestPrivateClient("binance", rt).(*BinanceApi)
+	if _, err := client.precise("ETH", "BTC"); err == nil {
+		t.Fatal("BinanceApi: Expected an error from the failed fetch")
+	}
+	rt.status = http.StatusOK
+	rt.message = jsonPrecision
+	precisions, err := client.precise("ETH", "BTC")
+	if err != nil {
+		t.Fatalf("BinanceApi: Expected the fetch to be retried. Got %v", err)
+	}
+	if precisions.PricePrecision != 8 || precisions.AmountPrecision != 6 {
+		t.Errorf("BinanceApi: Expected %v %v. Got %v %v", 8, 6, precisions.PricePrecision, precisions.AmountPrecision)
+	}
+}
@@ -0,0 +1,43 @@
+package private
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/symbols"
+)
+
+// symbolIndex is the native symbol index of the public API of an exchange,
+// fetched on first use.
+type symbolIndex struct {
+	mu sync.Mutex
+	ix *symbols.Index
+}
+
+func (s *symbolIndex) load(open func() (public.PublicClient, error)) (*symbols.Index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ix != nil {
+		return s.ix, nil
+	}
+	cli, err := open()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize public client")
+	}
+	ix, err := cli.Symbols()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get symbols")
+	}
+	s.ix = ix
+	return ix, nil
+}
+
+// symbol returns the native symbol the exchange lists a pair by.
+func (s *symbolIndex) symbol(open func() (public.PublicClient, error), trading, settlement string) (string, error) {
+	ix, err := s.load(open)
+	if err != nil {
+		return "", err
+	}
+	return ix.Symbol(trading, settlement)
+}
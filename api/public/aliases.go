@@ -0,0 +1,149 @@
+package public
+
+import (
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
+)
+
+// aliasClient translates the asset names of an exchange to canonical ones,
+// so callers use the same names for every exchange.
+type aliasClient struct {
+	PublicClient
+	aliases symbols.Aliases
+	symbols symbolIndex
+}
+
+func withAliases(cli PublicClient, a symbols.Aliases) PublicClient {
+	return &aliasClient{PublicClient: cli, aliases: a}
+}
+
+func (c *aliasClient) CurrencyPairs() ([]models.CurrencyPair, error) {
+	pairs, err := c.PublicClient.CurrencyPairs()
+	if err != nil {
+		return nil, err
+	}
+	listed := c.aliases.CanonicalPairs(pairs)
+	r := make([]models.CurrencyPair, 0, len(listed))
+	for _, p := range pairs {
+		if cp := c.aliases.CanonicalPair(p); listed[cp] == p {
+			r = append(r, cp)
+		}
+	}
+	return r, nil
+}
+
+func (c *aliasClient) Rate(trading string, settlement string) (float64, error) {
+	trading, settlement = c.native(trading, settlement)
+	return c.PublicClient.Rate(trading, settlement)
+}
+
+func (c *aliasClient) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
+	m, err := c.PublicClient.OrderBookTickMap()
+	if err != nil {
+		return nil, err
+	}
+	return c.canonicalTicks(m), nil
+}
+
+func (c *aliasClient) Snapshot() (*models.MarketSnapshot, error) {
+	s, err := c.PublicClient.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	s.Rates = c.canonicalRates(s.Rates)
+	s.Volumes = c.canonicalRates(s.Volumes)
+	s.OrderBookTicks = c.canonicalTicks(s.OrderBookTicks)
+	return s, nil
+}
+
+func (c *aliasClient) Symbols() (*symbols.Index, error) {
+	return c.symbols.load(func() (*symbols.Index, error) {
+		native, err := c.PublicClient.Symbols()
+		if err != nil {
+			return nil, err
+		}
+		ix := symbols.New()
+		for cp, p := range c.aliases.CanonicalPairs(native.Pairs()) {
+			s, err := native.Symbol(p.Trading, p.Settlement)
+			if err != nil {
+				return nil, err
+			}
+			if err := ix.Add(s, cp); err != nil {
+				return nil, errors.Wrap(err, "failed to index canonical symbols")
+			}
+		}
+		return ix, nil
+	})
+}
+
+func (c *aliasClient) FrozenCurrency() ([]string, error) {
+	currencies, err := c.PublicClient.FrozenCurrency()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range currencies {
+		currencies[i] = c.aliases.Canonical(v)
+	}
+	return currencies, nil
+}
+
+func (c *aliasClient) Board(trading string, settlement string) (*models.Board, error) {
+	trading, settlement = c.native(trading, settlement)
+	return c.PublicClient.Board(trading, settlement)
+}
+
+func (c *aliasClient) Precise(trading string, settlement string) (*models.Precisions, error) {
+	trading, settlement = c.native(trading, settlement)
+	return c.PublicClient.Precise(trading, settlement)
+}
+
+// native returns the names of a canonical pair on the exchange. A pair the
+// exchange lists under the canonical names is used as is, so an alias never
+// shadows it.
+func (c *aliasClient) native(trading, settlement string) (string, string) {
+	if ix, err := c.PublicClient.Symbols(); err == nil {
+		if _, err := ix.Symbol(trading, settlement); err == nil {
+			return trading, settlement
+		}
+	}
+	return c.aliases.Native(trading), c.aliases.Native(settlement)
+}
+
+func (c *aliasClient) canonicalRates(m map[string]map[string]float64) map[string]map[string]float64 {
+	var pairs []models.CurrencyPair
+	for trading, n := range m {
+		for settlement := range n {
+			pairs = append(pairs, models.CurrencyPair{Trading: trading, Settlement: settlement})
+		}
+	}
+	r := make(map[string]map[string]float64, len(m))
+	for cp, p := range c.aliases.CanonicalPairs(pairs) {
+		rn, ok := r[cp.Trading]
+		if !ok {
+			rn = make(map[string]float64)
+			r[cp.Trading] = rn
+		}
+		rn[cp.Settlement] = m[p.Trading][p.Settlement]
+	}
+	return r
+}
+
+func (c *aliasClient) canonicalTicks(m map[string]map[string]models.OrderBookTick) map[string]map[string]models.OrderBookTick {
+	var pairs []models.CurrencyPair
+	for trading, n := range m {
+		for settlement := range n {
+			pairs = append(pairs, models.CurrencyPair{Trading: trading, Settlement: settlement})
+		}
+	}
+	r := make(map[string]map[string]models.OrderBookTick, len(m))
+	for cp, p := range c.aliases.CanonicalPairs(pairs) {
+		rn, ok := r[cp.Trading]
+		if !ok {
+			rn = make(map[string]models.OrderBookTick)
+			r[cp.Trading] = rn
+		}
+		rn[cp.Settlement] = m[p.Trading][p.Settlement]
+	}
+	return r
+}
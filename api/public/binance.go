@@ -1,77 +1,97 @@
 package public
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"sync"
 	"time"
 
-	"github.com/xuyangcn/go-exchange-client/api/unified"
-	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/unified"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 )
 
 const (
 	BINANCE_BASE_URL = "https://api.binance.com"
 )
 
-func NewBinancePublicApi() (*BinanceApi, error) {
-	cli := &http.Client{}
-	cli.Timeout = 20 * time.Second
+// binanceMirrors are the hosts serving the production API.
+var binanceMirrors = []string{BINANCE_BASE_URL, "https://api1.binance.com", "https://api2.binance.com", "https://api3.binance.com"}
+
+// binanceEnvironments are the endpoints besides production.
+var binanceEnvironments = map[options.Environment]options.Endpoints{
+	options.Testnet: {REST: "https://testnet.binance.vision", WebSocket: "wss://testnet.binance.vision/ws"},
+}
+
+func NewBinancePublicApi(opts ...options.Option) (*BinanceApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "binance",
+		BaseURL:            BINANCE_BASE_URL,
+		WebSocketURL:       "wss://stream.binance.com:9443/ws",
+		Mirrors:            binanceMirrors,
+		Environments:       binanceEnvironments,
+		Timeout:            20 * time.Second,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	shrimpyApi, err := unified.NewShrimpyApi()
 	if err != nil {
 		return nil, err
 	}
 	api := &BinanceApi{
-		BaseURL:           BINANCE_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		boardCache:        cache.New(3*time.Second, 1*time.Second),
-		boardTickerCache:  cache.New(3*time.Second, 1*time.Second),
-		HttpClient:        cli,
-		ShrimpyClient:     shrimpyApi,
-		m:                 new(sync.Mutex),
-		rateM:             new(sync.Mutex),
-		currencyM:         new(sync.Mutex),
-		boardTickerM:      new(sync.Mutex),
+		BaseURL:       o.BaseURL,
+		cache:         o.NewCache(),
+		HttpClient:    cli,
+		ShrimpyClient: shrimpyApi,
+		opts:          o,
 	}
 	api.fetchSettlements()
 	return api, nil
 }
 
 type BinanceApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	rateLastUpdated   time.Time
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	boardCache        *cache.Cache
-	boardTickerCache  *cache.Cache
-	currencyPairs     []models.CurrencyPair
+	BaseURL      string
+	precisionMap map[string]map[string]models.Precisions
+	precisionM   sync.Mutex
+	symbols      symbolIndex
+	cache        *cache.Cache
 
 	HttpClient    *http.Client
 	ShrimpyClient *unified.ShrimpyApiClient
 
-	settlements  []string
-	m            *sync.Mutex
-	rateM        *sync.Mutex
-	currencyM    *sync.Mutex
-	boardTickerM *sync.Mutex
+	settlements []string
+	opts        *options.Options
 }
 
 func (h *BinanceApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *BinanceApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "binance",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *BinanceApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *BinanceApi) renewHttpClient() error {
 	rt := h.HttpClient.Transport
 	h.HttpClient = &http.Client{Transport: rt}
@@ -116,11 +136,11 @@ type BinanceTickResponse struct {
 }
 
 func (h *BinanceApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-
-	h.precisionMap = make(map[string]map[string]models.Precisions)
 	url := h.publicApiUrl("/api/v1/exchangeInfo")
 
 	byteArray, err := h.getRequest(url)
@@ -132,137 +152,110 @@ func (h *BinanceApi) fetchPrecision() error {
 	if value.Get("code").String() == "-1003" {
 		return errors.Errorf("ip banned %s", url)
 	}
+	if !value.Get("symbols").IsArray() {
+		return errors.Errorf("failed to fetch %s: %s", url, byteArray)
+	}
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("symbols").Array() {
 		trading := v.Get("baseAsset").Str
 		settlement := v.Get("quoteAsset").Str
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  int(v.Get("baseAssetPrecision").Int()),
 			AmountPrecision: int(v.Get("quotePrecision").Int()),
 		}
 	}
-
-	return errors.Wrapf(err, "failed to fetch %s", url)
+	h.precisionMap = precisionMap
+	return nil
 }
 
-func (h *BinanceApi) fetchRate() error {
+func (h *BinanceApi) fetchRate() (*tickers, error) {
 	url := h.publicApiUrl("/api/v1/ticker/24hr")
 	byteArray, err := h.getRequest(url)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	value := gjson.Parse(byteArray)
 	if value.Get("code").String() == "-1003" {
-		return errors.Errorf("ip banned %s", url)
+		return nil, errors.Errorf("ip banned %s", url)
 	}
-	currencyPairs, err := h.CurrencyPairs()
+	ix, err := h.Symbols()
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 
-	rateMap := make(map[string]map[string]float64)
-	volumeMap := make(map[string]map[string]float64)
-	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
+	t := newTickers()
 	for _, v := range value.Array() {
-		var trading, settlement string
-		symbol := v.Get("symbol").Str
-		for _, c := range currencyPairs {
-			if symbol == c.Trading+c.Settlement {
-				trading = c.Trading
-				settlement = c.Settlement
-				break
-			}
+		pair, ok := ix.Pair(v.Get("symbol").Str)
+		if !ok {
+			continue
 		}
+		trading, settlement := pair.Trading, pair.Settlement
 
 		lastf := v.Get("lastPrice").Float()
 		volumef := v.Get("volume").Float()
 		bestbidPrice := v.Get("bidPrice").Float()
 		bestaskPrice := v.Get("askPrice").Float()
-		h.rateM.Lock()
-		n, ok := volumeMap[trading]
-		if !ok {
-			n = make(map[string]float64)
-			volumeMap[trading] = n
-		}
-		n[settlement] = volumef
-		m, ok := rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			rateMap[trading] = m
-		}
-		m[settlement] = lastf
-		l, ok := orderBookTickMap[trading]
-		if !ok {
-			l = make(map[string]models.OrderBookTick)
-			orderBookTickMap[trading] = l
-		}
-		l[settlement] = models.OrderBookTick{
+		t.setVolume(trading, settlement, volumef)
+		t.setRate(trading, settlement, lastf)
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestAskPrice: bestaskPrice,
 			BestBidPrice: bestbidPrice,
-		}
-		h.rateM.Unlock()
+		})
 	}
-	h.rateMap = rateMap
-	h.volumeMap = volumeMap
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return t, nil
 }
 
-func (h *BinanceApi) fetchOrderBookTick() error {
-	boardMap, err := h.ShrimpyClient.GetBoards("binance")
+func (h *BinanceApi) fetchOrderBookTick() (map[string]map[string]models.OrderBookTick, error) {
+	url := h.publicApiUrl("/api/v3/ticker/bookTicker")
+	byteArray, err := h.getRequest(url)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	value := gjson.Parse(byteArray)
+	if value.Get("code").String() == "-1003" {
+		return nil, errors.Errorf("ip banned %s", url)
+	}
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
+
 	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
-	for settlement, m := range boardMap {
-		for trading, value := range m {
-			l, ok := orderBookTickMap[trading]
-			if !ok {
-				l = make(map[string]models.OrderBookTick)
-				orderBookTickMap[trading] = l
-			}
-			l[settlement] = models.OrderBookTick{
-				BestAskPrice:  value.BestAskPrice(),
-				BestAskAmount: value.BestAskAmount(),
-				BestBidPrice:  value.BestBidPrice(),
-				BestBidAmount: value.BestBidAmount(),
-			}
+	for _, v := range value.Array() {
+		pair, ok := ix.Pair(v.Get("symbol").Str)
+		if !ok {
+			continue
+		}
+		l, ok := orderBookTickMap[pair.Trading]
+		if !ok {
+			l = make(map[string]models.OrderBookTick)
+			orderBookTickMap[pair.Trading] = l
+		}
+		l[pair.Settlement] = models.OrderBookTick{
+			BestAskPrice:  v.Get("askPrice").Float(),
+			BestAskAmount: v.Get("askQty").Float(),
+			BestBidPrice:  v.Get("bidPrice").Float(),
+			BestBidAmount: v.Get("bidQty").Float(),
 		}
 	}
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return orderBookTickMap, nil
 }
 
 func (h *BinanceApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchOrderBookTick()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
-	}
-	return h.orderBookTickMap, nil
+	return fetchOrderBookTicks(h.cache, h.fetchOrderBookTick)
 }
 
 func (h *BinanceApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *BinanceApi) Precise(trading string, settlement string) (*models.Precisions, error) {
@@ -270,7 +263,11 @@ func (h *BinanceApi) Precise(trading string, settlement string) (*models.Precisi
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -281,96 +278,72 @@ func (h *BinanceApi) Precise(trading string, settlement string) (*models.Precisi
 }
 
 func (h *BinanceApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (h *BinanceApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
 }
 
 func (h *BinanceApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.currencyM.Lock()
-	defer h.currencyM.Unlock()
-	if len(h.currencyPairs) != 0 {
-		return h.currencyPairs, nil
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
 	}
+	return ix.Pairs(), nil
+}
+
+// fetchSymbols indexes the symbols of exchangeInfo by their base and quote
+// assets.
+func (h *BinanceApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("/api/v1/exchangeInfo")
 	byteArray, err := h.getRequest(url)
 	if err != nil {
-		return h.currencyPairs, err
+		return nil, err
 	}
 	value := gjson.Parse(byteArray)
-	currencyPairs := make([]models.CurrencyPair, 0)
-
 	if value.Get("code").String() == "-1003" {
-		return h.currencyPairs, errors.Errorf("ip banned %s", url)
+		return nil, errors.Errorf("ip banned %s", url)
 	}
+	ix := symbols.New()
 	for _, v := range value.Get("symbols").Array() {
-		trading := v.Get("baseAsset").Str
-		settlement := v.Get("quoteAsset").Str
-		currencyPairs = append(currencyPairs, models.CurrencyPair{
-			Trading:    trading,
-			Settlement: settlement,
+		addSymbol(h.opts, ix, v.Get("symbol").Str, models.CurrencyPair{
+			Trading:    v.Get("baseAsset").Str,
+			Settlement: v.Get("quoteAsset").Str,
 		})
 	}
-	h.currencyPairs = currencyPairs
-	return currencyPairs, nil
+	return ix, nil
+}
+
+func (h *BinanceApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
 }
 
 func (h *BinanceApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *BinanceApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *BinanceApi) FrozenCurrency() ([]string, error) {
-	if len(h.currencyPairs) != 0 {
+	if h.symbols.loaded() {
 		return []string{}, nil
 	}
 	url := h.publicApiUrl("/api/v1/exchangeInfo")
@@ -405,31 +378,28 @@ func (h *BinanceApi) FrozenCurrency() ([]string, error) {
 	return uniq, nil
 }
 
-func (h *BinanceApi) fetchBoardTicker() error {
+func (h *BinanceApi) fetchBoardTicker() (map[string]*models.Board, error) {
 	url := h.publicApiUrl("/api/v3/ticker/bookTicker")
 	byteArray, err := h.getRequest(url)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	value := gjson.Parse(byteArray)
 	if value.Get("code").String() == "-1003" {
-		return errors.Errorf("ip banned %s", url)
+		return nil, errors.Errorf("ip banned %s", url)
 	}
-	currencyPairs, err := h.CurrencyPairs()
+	ix, err := h.Symbols()
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 
+	boards := make(map[string]*models.Board)
 	for _, v := range value.Array() {
-		var trading, settlement string
-		symbol := v.Get("symbol").Str
-		for _, c := range currencyPairs {
-			if symbol == c.Trading+c.Settlement {
-				trading = c.Trading
-				settlement = c.Settlement
-				break
-			}
+		pair, ok := ix.Pair(v.Get("symbol").Str)
+		if !ok {
+			continue
 		}
+		trading, settlement := pair.Trading, pair.Settlement
 		bids := make([]models.BoardBar, 0)
 		asks := make([]models.BoardBar, 0)
 
@@ -450,24 +420,29 @@ func (h *BinanceApi) fetchBoardTicker() error {
 			Amount: bestAskAmountf,
 			Type:   models.Ask,
 		})
-		board := &models.Board{
+		boards[trading+"_"+settlement] = &models.Board{
 			Bids: bids,
 			Asks: asks,
 		}
-		h.boardTickerCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
 	}
-	return nil
+	return boards, nil
 }
 
 func (h *BinanceApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	c, found := h.boardCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
-	}
 	if trading == settlement {
 		return nil, errors.Errorf("trading and settlment are same")
 	}
-	url := h.publicApiUrl("/api/v1/depth?limit=1000&symbol=" + trading + settlement)
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *BinanceApi) fetchBoard(trading string, settlement string) (*models.Board, error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
+	}
+	url := h.publicApiUrl("/api/v1/depth?limit=1000&symbol=" + symbol)
 	byteArray, err := h.getRequest(url)
 	if err != nil {
 		return nil, err
@@ -501,32 +476,26 @@ func (h *BinanceApi) Board(trading string, settlement string) (board *models.Boa
 		}
 		asks = append(asks, askBoardBar)
 	}
-	board = &models.Board{
+	return &models.Board{
 		Asks: asks,
 		Bids: bids,
-	}
-	h.boardCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
-	return board, nil
+	}, nil
 }
 
 /*duplicated*/
 func (h *BinanceApi) BoardTicker(trading string, settlement string) (board *models.Board, err error) {
-	h.boardTickerM.Lock()
-	defer h.boardTickerM.Unlock()
-	c, found := h.boardTickerCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
-	}
 	if trading == settlement {
 		return nil, errors.Errorf("trading and settlment are same")
 	}
-	err = h.fetchBoardTicker()
+	v, err := h.cache.Fetch("board_ticker", "", func() (interface{}, error) {
+		return h.fetchBoardTicker()
+	})
 	if err != nil {
 		return nil, err
 	}
-	c, found = h.boardTickerCache.Get(trading + "_" + settlement)
-	if !found {
-		return nil, errors.Errorf("cache err")
+	board, ok := v.(map[string]*models.Board)[trading+"_"+settlement]
+	if !ok {
+		return nil, errors.Errorf("%s/%s", trading, settlement)
 	}
-	return c.(*models.Board), nil
+	return board.Copy(), nil
 }
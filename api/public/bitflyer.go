@@ -1,61 +1,82 @@
 package public
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
-	"time"
-
 	"sync"
+	"time"
 
 	"strings"
 
 	"github.com/Jeffail/gabs"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 )
 
 const (
 	BITFLYER_BASE_URL = "https://api.bitflyer.jp/v1"
 )
 
-func NewBitflyerPublicApi() (*BitflyerApi, error) {
+func NewBitflyerPublicApi(opts ...options.Option) (*BitflyerApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "bitflyer",
+		BaseURL:            BITFLYER_BASE_URL,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	api := &BitflyerApi{
-		BaseURL:           BITFLYER_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		HttpClient:        http.Client{},
+		BaseURL:    o.BaseURL,
+		HttpClient: *cli,
 
-		m: new(sync.Mutex),
+		cache: o.NewCache(),
+		opts:  o,
 	}
 	api.fetchSettlements()
 	return api, nil
 }
 
 type BitflyerApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	HttpClient        http.Client
-
-	volumeMap        map[string]map[string]float64
-	rateMap          map[string]map[string]float64
-	orderBookTickMap map[string]map[string]models.OrderBookTick
-	precisionMap     map[string]map[string]models.Precisions
-	rateLastUpdated  time.Time
-	settlements      []string
-
-	m *sync.Mutex
+	BaseURL    string
+	HttpClient http.Client
+
+	precisionMap map[string]map[string]models.Precisions
+	precisionM   sync.Mutex
+	symbols      symbolIndex
+	settlements  []string
+
+	cache *cache.Cache
+	opts  *options.Options
 }
 
 func (h *BitflyerApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (b *BitflyerApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "bitflyer",
+		Operations: models.Except(models.PublicOperations, models.OpFrozenCurrency),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (b *BitflyerApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, b.fetchPrecision, loadCurrencyPairs(b))
+}
+
 func (b *BitflyerApi) publicApiUrl(command string) string {
 	return b.BaseURL + "/" + command
 }
@@ -67,25 +88,22 @@ func (b *BitflyerApi) fetchSettlements() error {
 	return nil
 }
 
-func (b *BitflyerApi) fetchRate() error {
-	b.rateMap = make(map[string]map[string]float64)
-	b.volumeMap = make(map[string]map[string]float64)
-	b.orderBookTickMap = make(map[string]map[string]models.OrderBookTick)
+func (b *BitflyerApi) fetchRate() (*tickers, error) {
 	url := b.publicApiUrl("ticker")
 	resp, err := b.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := gabs.ParseJSON(byteArray)
 
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	pair := json.Path("product_code").Data().(string)
 
@@ -99,60 +117,46 @@ func (b *BitflyerApi) fetchRate() error {
 		}
 	}
 	if settlement == "" || trading == "" {
-		return errors.New("pair is not parsed")
+		return nil, errors.New("pair is not parsed")
 	}
+	t := newTickers()
+	t.setSymbol(pair, trading, settlement)
 	// update rate
 	last, ok := json.Path("ltp").Data().(float64)
 	if !ok {
-		return errors.New("close price is not parsed")
+		return nil, errors.New("close price is not parsed")
 	}
-
-	m, ok := b.rateMap[trading]
-	if !ok {
-		m = make(map[string]float64)
-		b.rateMap[trading] = m
-	}
-	m[settlement] = last
+	t.setRate(trading, settlement, last)
 
 	// update volume
 	volume, ok := json.Path("volume").Data().(float64)
 	if !ok {
-		return errors.New("volume is not parsed")
-	}
-	m, ok = b.volumeMap[trading]
-	if !ok {
-		m = make(map[string]float64)
-		b.volumeMap[trading] = m
+		return nil, errors.New("volume is not parsed")
 	}
-	m[settlement] = volume
+	t.setVolume(trading, settlement, volume)
 
 	// update orderBooTick
 	ask, ok := json.Path("best_ask").Data().(float64)
 	if !ok {
-		return errors.New("volume is not parsed")
+		return nil, errors.New("volume is not parsed")
 	}
 	bid, ok := json.Path("best_bid").Data().(float64)
 	if !ok {
-		return errors.New("volume is not parsed")
+		return nil, errors.New("volume is not parsed")
 	}
-	n, ok := b.orderBookTickMap[trading]
-	if !ok {
-		n = make(map[string]models.OrderBookTick)
-		b.orderBookTickMap[trading] = n
-	}
-	n[settlement] = models.OrderBookTick{
+	t.setTick(trading, settlement, models.OrderBookTick{
 		BestAskPrice: ask,
 		BestBidPrice: bid,
-	}
-	return nil
+	})
+	return t, nil
 }
 
 func (b *BitflyerApi) fetchPrecision() error {
+	b.precisionM.Lock()
+	defer b.precisionM.Unlock()
 	if b.precisionMap != nil {
 		return nil
 	}
-	b.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url := b.publicApiUrl("ticker")
 	resp, err := b.HttpClient.Get(url)
 	if err != nil {
@@ -184,34 +188,28 @@ func (b *BitflyerApi) fetchPrecision() error {
 	last := value.Get("ltp").Raw
 	volume := value.Get("volume").Raw
 
-	m, ok := b.precisionMap[trading]
+	precisionMap := make(map[string]map[string]models.Precisions)
+	m, ok := precisionMap[trading]
 	if !ok {
 		m = make(map[string]models.Precisions)
-		b.precisionMap[trading] = m
+		precisionMap[trading] = m
 	}
 	m[settlement] = models.Precisions{
 		PricePrecision:  Precision(last),
 		AmountPrecision: Precision(volume),
 	}
-
+	b.precisionMap = precisionMap
 	return nil
 }
 
 func (b *BitflyerApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(b.rateLastUpdated) >= b.RateCacheDuration {
-		err := b.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		b.rateLastUpdated = now
+	t, err := fetchTickers(b.cache, b.fetchRate)
+	if err != nil {
+		return nil, err
 	}
 
 	var pairs []models.CurrencyPair
-	for trading, m := range b.rateMap {
+	for trading, m := range t.Rates {
 		for settlement := range m {
 			pair := models.CurrencyPair{
 				Trading:    trading,
@@ -224,93 +222,55 @@ func (b *BitflyerApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	return pairs, nil
 }
 
-func (b *BitflyerApi) Volume(trading string, settlement string) (float64, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(b.rateLastUpdated) >= b.RateCacheDuration {
-		err := b.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		b.rateLastUpdated = now
-	}
+func (b *BitflyerApi) Symbols() (*symbols.Index, error) {
+	return b.symbols.load(indexTickers(b.opts, b.cache, b.fetchRate))
+}
 
-	if m, ok := b.volumeMap[trading]; !ok {
-		return 0, errors.New("trading volume not found")
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.New("settlement volume not found")
-	} else {
-		return volume, nil
+func (b *BitflyerApi) Volume(trading string, settlement string) (float64, error) {
+	t, err := fetchTickers(b.cache, b.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (b *BitflyerApi) Rate(trading string, settlement string) (float64, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(b.rateLastUpdated) >= b.RateCacheDuration {
-		err := b.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		b.rateLastUpdated = now
-	}
-	if m, ok := b.rateMap[trading]; !ok {
-		return 0, errors.New("trading rate not found")
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.New("settlement rate not found")
-	} else {
-		return rate, nil
+	t, err := fetchTickers(b.cache, b.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (b *BitflyerApi) RateMap() (map[string]map[string]float64, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
-	now := time.Now()
-	if now.Sub(b.rateLastUpdated) >= b.RateCacheDuration {
-		err := b.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		b.rateLastUpdated = now
+	t, err := fetchTickers(b.cache, b.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return b.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (b *BitflyerApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
-	now := time.Now()
-	if now.Sub(b.rateLastUpdated) >= b.RateCacheDuration {
-		err := b.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		b.rateLastUpdated = now
+	t, err := fetchTickers(b.cache, b.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return b.orderBookTickMap, nil
+	return copyTicks(t.Ticks), nil
 }
 
 func (b *BitflyerApi) VolumeMap() (map[string]map[string]float64, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
-	now := time.Now()
-	if now.Sub(b.rateLastUpdated) >= b.RateCacheDuration {
-		err := b.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		b.rateLastUpdated = now
+	t, err := fetchTickers(b.cache, b.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return b.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (b *BitflyerApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(b.cache, b.fetchRate)
 }
 
 func (b *BitflyerApi) FrozenCurrency() ([]string, error) {
@@ -322,7 +282,11 @@ func (h *BitflyerApi) Precise(trading string, settlement string) (*models.Precis
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -333,7 +297,17 @@ func (h *BitflyerApi) Precise(trading string, settlement string) (*models.Precis
 }
 
 func (b *BitflyerApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	url := b.publicApiUrl("board") + "?product_code=" + strings.ToUpper(trading) + "_" + strings.ToLower(settlement)
+	return fetchBoard(b.cache, trading, settlement, func() (*models.Board, error) {
+		return b.fetchBoard(trading, settlement)
+	})
+}
+
+func (b *BitflyerApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(b.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
+	}
+	url := b.publicApiUrl("board") + "?product_code=" + symbol
 	resp, err := b.HttpClient.Get(url)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to fetch %s", url)
@@ -0,0 +1,169 @@
+package public
+
+import (
+	"encoding/gob"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+// tickers are the rates, volumes and best prices of every pair of an
+// exchange, which are fetched together and cached under the rate endpoint.
+// They are shared by every caller and never modified once fetched, so
+// accessors hand out copies.
+type tickers struct {
+	Rates   map[string]map[string]float64
+	Volumes map[string]map[string]float64
+	Ticks   map[string]map[string]models.OrderBookTick
+	// Symbols are the pairs of the native symbols the tickers are listed by.
+	Symbols map[string]models.CurrencyPair
+	Time    time.Time
+}
+
+func init() {
+	gob.Register(&tickers{})
+}
+
+func newTickers() *tickers {
+	return &tickers{
+		Rates:   make(map[string]map[string]float64),
+		Volumes: make(map[string]map[string]float64),
+		Ticks:   make(map[string]map[string]models.OrderBookTick),
+		Symbols: make(map[string]models.CurrencyPair),
+		Time:    time.Now(),
+	}
+}
+
+func (t *tickers) setRate(trading, settlement string, rate float64) {
+	m, ok := t.Rates[trading]
+	if !ok {
+		m = make(map[string]float64)
+		t.Rates[trading] = m
+	}
+	m[settlement] = rate
+}
+
+func (t *tickers) setVolume(trading, settlement string, volume float64) {
+	m, ok := t.Volumes[trading]
+	if !ok {
+		m = make(map[string]float64)
+		t.Volumes[trading] = m
+	}
+	m[settlement] = volume
+}
+
+func (t *tickers) setTick(trading, settlement string, tick models.OrderBookTick) {
+	m, ok := t.Ticks[trading]
+	if !ok {
+		m = make(map[string]models.OrderBookTick)
+		t.Ticks[trading] = m
+	}
+	m[settlement] = tick
+}
+
+func (t *tickers) setSymbol(symbol, trading, settlement string) {
+	t.Symbols[symbol] = models.CurrencyPair{Trading: trading, Settlement: settlement}
+}
+
+func (t *tickers) rate(trading, settlement string) (float64, error) {
+	if m, ok := t.Rates[trading]; !ok {
+		return 0, errors.Errorf("%s/%s", trading, settlement)
+	} else if rate, ok := m[settlement]; !ok {
+		return 0, errors.Errorf("%s/%s", trading, settlement)
+	} else {
+		return rate, nil
+	}
+}
+
+func (t *tickers) volume(trading, settlement string) (float64, error) {
+	if m, ok := t.Volumes[trading]; !ok {
+		return 0, errors.Errorf("%s/%s", trading, settlement)
+	} else if volume, ok := m[settlement]; !ok {
+		return 0, errors.Errorf("%s/%s", trading, settlement)
+	} else {
+		return volume, nil
+	}
+}
+
+func (t *tickers) snapshot() *models.MarketSnapshot {
+	return &models.MarketSnapshot{
+		Rates:          copyRates(t.Rates),
+		Volumes:        copyRates(t.Volumes),
+		OrderBookTicks: copyTicks(t.Ticks),
+		Time:           t.Time,
+	}
+}
+
+func copyRates(m map[string]map[string]float64) map[string]map[string]float64 {
+	c := make(map[string]map[string]float64, len(m))
+	for trading, n := range m {
+		cn := make(map[string]float64, len(n))
+		for settlement, v := range n {
+			cn[settlement] = v
+		}
+		c[trading] = cn
+	}
+	return c
+}
+
+func copyTicks(m map[string]map[string]models.OrderBookTick) map[string]map[string]models.OrderBookTick {
+	c := make(map[string]map[string]models.OrderBookTick, len(m))
+	for trading, n := range m {
+		cn := make(map[string]models.OrderBookTick, len(n))
+		for settlement, v := range n {
+			cn[settlement] = v
+		}
+		c[trading] = cn
+	}
+	return c
+}
+
+func copyPairs(pairs []models.CurrencyPair) []models.CurrencyPair {
+	return append([]models.CurrencyPair(nil), pairs...)
+}
+
+// fetchTickers returns the cached tickers, calling fetch when they expired.
+func fetchTickers(c *cache.Cache, fetch func() (*tickers, error)) (*tickers, error) {
+	v, err := c.Fetch("rate", "", func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*tickers), nil
+}
+
+// fetchSnapshot returns a copy of the cached tickers.
+func fetchSnapshot(c *cache.Cache, fetch func() (*tickers, error)) (*models.MarketSnapshot, error) {
+	t, err := fetchTickers(c, fetch)
+	if err != nil {
+		return nil, err
+	}
+	return t.snapshot(), nil
+}
+
+// fetchOrderBookTicks returns a copy of the cached best prices of every pair,
+// for exchanges which fetch them apart from the rates.
+func fetchOrderBookTicks(c *cache.Cache, fetch func() (map[string]map[string]models.OrderBookTick, error)) (map[string]map[string]models.OrderBookTick, error) {
+	v, err := c.Fetch("order_book_tick", "", func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyTicks(v.(map[string]map[string]models.OrderBookTick)), nil
+}
+
+// fetchBoard returns a copy of the cached board of the pair, calling fetch
+// when it expired.
+func fetchBoard(c *cache.Cache, trading, settlement string, fetch func() (*models.Board, error)) (*models.Board, error) {
+	v, err := c.Fetch("board", trading+"_"+settlement, func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*models.Board).Copy(), nil
+}
@@ -1,18 +1,34 @@
 package public
 
 import (
-	"errors"
+	"context"
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/unified"
 	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"net/http"
-	"strings"
 	"sync"
 )
 
 var (
-	clientMap map[string]PublicClient
+	clientMap = make(map[string]PublicClient)
 	mtx       sync.Mutex
 )
 
+func init() {
+	mustRegister("binance", func(opts ...options.Option) (PublicClient, error) { return NewBinancePublicApi(opts...) })
+	mustRegister("bitflyer", func(opts ...options.Option) (PublicClient, error) { return NewBitflyerPublicApi(opts...) })
+	mustRegister("poloniex", func(opts ...options.Option) (PublicClient, error) { return NewPoloniexPublicApi(opts...) })
+	mustRegister("hitbtc", func(opts ...options.Option) (PublicClient, error) { return NewHitbtcPublicApi(opts...) })
+	mustRegister("huobi", func(opts ...options.Option) (PublicClient, error) { return NewHuobiPublicApi(opts...) }, "huobipro")
+	mustRegister("okex", func(opts ...options.Option) (PublicClient, error) { return NewOkexPublicApi(opts...) }, "okx")
+	mustRegister("cobinhood", func(opts ...options.Option) (PublicClient, error) { return NewCobinhoodPublicApi(opts...) })
+	mustRegister("lbank", func(opts ...options.Option) (PublicClient, error) { return NewLbankPublicApi(opts...) })
+	mustRegister("kucoin", func(opts ...options.Option) (PublicClient, error) { return NewKucoinPublicApi(opts...) })
+	mustRegister("p2pb2b", func(opts ...options.Option) (PublicClient, error) { return NewP2pb2bPublicApi(opts...) })
+}
+
 //go:generate mockery -name=PublicClient
 type PublicClient interface {
 	// Volume(trading string, settlement string) (float64, error)
@@ -21,49 +37,73 @@ type PublicClient interface {
 	// RateMap() (map[string]map[string]float64, error)
 	// VolumeMap() (map[string]map[string]float64, error)
 	OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error)
+	// Snapshot returns the rates, volumes and order book ticks of a single
+	// fetch. Maps and boards returned by clients are copies owned by the caller.
+	Snapshot() (*models.MarketSnapshot, error)
+	// Symbols returns the index mapping the native symbols of the exchange
+	// to currency pairs and back.
+	Symbols() (*symbols.Index, error)
 	FrozenCurrency() ([]string, error)
 	Board(trading string, settlement string) (*models.Board, error)
 	Precise(trading string, settlement string) (*models.Precisions, error)
+	Capabilities() models.Capabilities
+	// Warmup loads market metadata ahead of the first call.
+	// Constructors never do network I/O, so clients load it lazily otherwise.
+	Warmup(ctx context.Context) error
 
+	// SetTransport replaces the transport of the client. It may be called
+	// while requests are in flight.
 	SetTransport(transport http.RoundTripper) error
 }
 
-func NewDefaultClient(exchangeName string) PublicClient {
+// NewDefaultClient returns a client shared by every caller asking for the same exchange.
+func NewDefaultClient(exchangeName string) (PublicClient, error) {
+	name, err := Resolve(exchangeName)
+	if err != nil {
+		return nil, err
+	}
 	mtx.Lock()
 	defer mtx.Unlock()
-	if clientMap[strings.ToLower(exchangeName)] == nil {
-		cli, err := NewClient(exchangeName)
-		if err != nil {
-			panic(err)
-		}
-		clientMap[strings.ToLower(exchangeName)] = cli
-		return cli
+	if cli, ok := clientMap[name]; ok {
+		return cli, nil
+	}
+	cli, err := NewClient(name)
+	if err != nil {
+		return nil, err
+	}
+	clientMap[name] = cli
+	return cli, nil
+}
+
+// NewClient builds a new client of the exchange with opts applied on top of its defaults.
+// The client takes and returns canonical asset names, see options.WithAliases.
+func NewClient(exchangeName string, opts ...options.Option) (PublicClient, error) {
+	name, factory, err := lookup(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := factory(opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to init %s api", name)
+	}
+	if a := options.ResolveAliases(name, opts...); len(a) > 0 {
+		cli = withAliases(cli, a)
+	}
+	return cli, nil
+}
+
+func loadCurrencyPairs(cli PublicClient) func() error {
+	return func() error {
+		_, err := cli.CurrencyPairs()
+		return err
 	}
-	return clientMap[strings.ToLower(exchangeName)]
 }
 
-func NewClient(exchangeName string) (PublicClient, error) {
-	switch strings.ToLower(exchangeName) {
-	case "binance":
-		return NewBinancePublicApi()
-	case "bitflyer":
-		return NewBitflyerPublicApi()
-	case "poloniex":
-		return NewPoloniexPublicApi()
-	case "hitbtc":
-		return NewHitbtcPublicApi()
-	case "huobi":
-		return NewHuobiPublicApi()
-	case "okex":
-		return NewOkexPublicApi()
-	case "cobinhood":
-		return NewCobinhoodPublicApi()
-	case "lbank":
-		return NewLbankPublicApi()
-	case "kucoin":
-		return NewKucoinPublicApi()
-		/*case "p2pb2b":
-		return NewP2pb2bPublicApi()*/
+// shrimpyBoards returns the boards of exchange from Shrimpy, which only knows
+// the production markets.
+func shrimpyBoards(cli *unified.ShrimpyApiClient, o *options.Options, exchange string) (map[string]map[string]models.Board, error) {
+	if o.Environment != options.Production {
+		return nil, errors.Errorf("order book ticks are only available in %s, not %s", options.Production, o.Environment)
 	}
-	return nil, errors.New("failed to init exchange api")
+	return cli.GetBoards(exchange)
 }
@@ -1,16 +1,20 @@
 package public
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
-	"fmt"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"io/ioutil"
 	"net/url"
 	"strings"
@@ -23,49 +27,63 @@ const (
 type CobinhoodApiConfig struct {
 }
 
-func NewCobinhoodPublicApi() (*CobinhoodApi, error) {
+func NewCobinhoodPublicApi(opts ...options.Option) (*CobinhoodApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "cobinhood",
+		BaseURL:           COBINHOOD_BASE_URL,
+		RateCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	api := &CobinhoodApi{
-		BaseURL:                    COBINHOOD_BASE_URL,
-		RateCacheDuration:          3 * time.Second,
-		rateMap:                    nil,
-		volumeMap:                  nil,
-		orderBookTickMap:           nil,
-		rateLastUpdated:            time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		BaseURL:                    o.BaseURL,
 		CurrencyPairsCacheDuration: 7 * 24 * time.Hour,
 		currencyPairsLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 
-		m:         new(sync.Mutex),
-		currencyM: new(sync.Mutex),
+		HttpClient: *cli,
+
+		cache: o.NewCache(),
+		opts:  o,
 	}
-	api.fetchSettlements()
 	return api, nil
 }
 
 type CobinhoodApi struct {
 	BaseURL                    string
-	RateCacheDuration          time.Duration
-	volumeMap                  map[string]map[string]float64
-	rateMap                    map[string]map[string]float64
-	orderBookTickMap           map[string]map[string]models.OrderBookTick
 	precisionMap               map[string]map[string]models.Precisions
-	rateLastUpdated            time.Time
-	currencyPairs              []models.CurrencyPair
+	precisionM                 sync.Mutex
+	symbols                    symbolIndex
 	CurrencyPairsCacheDuration time.Duration
 	currencyPairsLastUpdated   time.Time
 	HttpClient                 http.Client
 
 	settlements []string
 
-	m         *sync.Mutex
-	currencyM *sync.Mutex
-	c         *CobinhoodApiConfig
+	cache *cache.Cache
+	opts  *options.Options
+	c     *CobinhoodApiConfig
 }
 
 func (h *CobinhoodApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(&h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *CobinhoodApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "cobinhood",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *CobinhoodApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *CobinhoodApi) publicApiUrl(command string) string {
 	return h.BaseURL + command
 }
@@ -88,11 +106,11 @@ func (h *CobinhoodApi) fetchSettlements() error {
 }
 
 func (h *CobinhoodApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url := h.publicApiUrl("/v1/market/tickers")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -106,6 +124,7 @@ func (h *CobinhoodApi) fetchPrecision() error {
 	}
 	value := gjson.Parse(string(byteArray))
 
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("result.tickers").Array() {
 		last := v.Get("last_trade_price").Str
 		volume := v.Get("24h_volume").Str
@@ -117,85 +136,84 @@ func (h *CobinhoodApi) fetchPrecision() error {
 		trading := currencies[0]
 		settlement := currencies[1]
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  Precision(last),
 			AmountPrecision: Precision(volume),
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
-func (h *CobinhoodApi) fetchRate() error {
-	h.rateMap = make(map[string]map[string]float64)
-	h.volumeMap = make(map[string]map[string]float64)
-	h.orderBookTickMap = make(map[string]map[string]models.OrderBookTick)
+func (h *CobinhoodApi) fetchRate() (*tickers, error) {
 	url := h.publicApiUrl("/v1/market/tickers")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := jason.NewObjectFromBytes(byteArray)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	result, err := json.GetObject("result")
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	tickers, err := result.GetObjectArray("tickers")
+	tickerArray, err := result.GetObjectArray("tickers")
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	for _, v := range tickers {
+	t := newTickers()
+	for _, v := range tickerArray {
 		lastString, err := v.GetString("last_trade_price")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		lastf, err := strconv.ParseFloat(lastString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 
 		volumeString, err := v.GetString("24h_volume")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		volumef, err := strconv.ParseFloat(volumeString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 
 		askString, err := v.GetString("lowest_ask")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		askf, err := strconv.ParseFloat(askString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 
 		bidString, err := v.GetString("highest_bid")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		bidf, err := strconv.ParseFloat(bidString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		pairString, err := v.GetString("trading_pair_id")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		currencies := strings.Split(pairString, "-")
 		if len(currencies) != 2 {
@@ -203,79 +221,55 @@ func (h *CobinhoodApi) fetchRate() error {
 		}
 		trading := currencies[0]
 		settlement := currencies[1]
-		m, ok := h.rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.rateMap[trading] = m
-		}
-		m[settlement] = lastf
-		m, ok = h.volumeMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.volumeMap[trading] = m
-		}
-		m[settlement] = volumef
-		n, ok := h.orderBookTickMap[trading]
-		if !ok {
-			n = make(map[string]models.OrderBookTick)
-			h.orderBookTickMap[trading] = n
-		}
-		n[settlement] = models.OrderBookTick{
+		t.setRate(trading, settlement, lastf)
+		t.setVolume(trading, settlement, volumef)
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestAskPrice: askf,
 			BestBidPrice: bidf,
-		}
+		})
 	}
-	return nil
+	return t, nil
 }
 
 func (h *CobinhoodApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.orderBookTickMap, nil
+	return copyTicks(t.Ticks), nil
 }
 
 func (h *CobinhoodApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *CobinhoodApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (h *CobinhoodApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
 }
 
 func (h *CobinhoodApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.currencyM.Lock()
-	defer h.currencyM.Unlock()
-	if len(h.currencyPairs) != 0 {
-		return h.currencyPairs, nil
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
 	}
+	return ix.Pairs(), nil
+}
+
+// fetchSymbols indexes the ids of the trading pairs by their base and quote
+// currencies.
+func (h *CobinhoodApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("/v1/market/trading_pairs")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -295,74 +289,53 @@ func (h *CobinhoodApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	trading_pairs, err := result.GetObjectArray("trading_pairs")
+	tradingPairs, err := result.GetObjectArray("trading_pairs")
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	var pairs []models.CurrencyPair
-	for _, v := range trading_pairs {
+	ix := symbols.New()
+	for _, v := range tradingPairs {
+		id, err := v.GetString("id")
+		if err != nil {
+			continue
+		}
 		trading, err := v.GetString("base_currency_id")
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse quote")
+			continue
 		}
 		settlement, err := v.GetString("quote_currency_id")
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse quote")
+			continue
 		}
-		pair := models.CurrencyPair{
+		addSymbol(h.opts, ix, id, models.CurrencyPair{
 			Trading:    strings.ToUpper(trading),
 			Settlement: strings.ToUpper(settlement),
-		}
-		pairs = append(pairs, pair)
+		})
 	}
-	h.currencyPairs = pairs
-	return pairs, nil
+	return ix, nil
 }
 
-func (h *CobinhoodApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
+func (h *CobinhoodApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
+}
 
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+func (h *CobinhoodApi) Volume(trading string, settlement string) (float64, error) {
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *CobinhoodApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *CobinhoodApi) Precise(trading string, settlement string) (*models.Precisions, error) {
@@ -370,7 +343,11 @@ func (h *CobinhoodApi) Precise(trading string, settlement string) (*models.Preci
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -397,7 +374,6 @@ func (h *CobinhoodApi) FrozenCurrency() ([]string, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	fmt.Println(json.String())
 	result, err := json.GetObject("result")
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json result key ngo")
@@ -428,9 +404,19 @@ func (h *CobinhoodApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *CobinhoodApi) Board(trading string, settlement string) (board *models.Board, err error) {
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *CobinhoodApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
+	}
 	args := url.Values{}
 	args.Add("limit", "10000")
-	path := h.publicApiUrl("/v1/market/orderbooks/"+trading+"-"+settlement) + "?" + args.Encode()
+	path := h.publicApiUrl("/v1/market/orderbooks/"+symbol) + "?" + args.Encode()
 	resp, err := h.HttpClient.Get(path)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to fetch %s", path)
@@ -445,7 +431,6 @@ func (h *CobinhoodApi) Board(trading string, settlement string) (board *models.B
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	fmt.Println(json.String())
 	result, err := json.GetObject("result")
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json result")
@@ -471,7 +456,6 @@ func (h *CobinhoodApi) Board(trading string, settlement string) (board *models.B
 		}
 		priceStr, err := s[0].String()
 		if err != nil {
-			fmt.Println(s[0])
 			return nil, errors.Wrapf(err, "failed to parse price")
 		}
 		amountStr, err := s[2].String()
@@ -499,7 +483,6 @@ func (h *CobinhoodApi) Board(trading string, settlement string) (board *models.B
 		}
 		priceStr, err := s[0].String()
 		if err != nil {
-			fmt.Println(s[0])
 			return nil, errors.Wrapf(err, "failed to parse price")
 		}
 		amountStr, err := s[2].String()
@@ -1,6 +1,7 @@
 package public
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -10,110 +11,144 @@ import (
 
 	"github.com/Jeffail/gabs"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/unified"
-	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/unified"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 )
 
 const (
 	HITBTC_BASE_URL = "https://api.hitbtc.com/api/2"
 )
 
+// hitbtcEnvironments are the endpoints besides production.
+var hitbtcEnvironments = map[options.Environment]options.Endpoints{
+	options.Testnet: {REST: "https://api.demo.hitbtc.com/api/2", WebSocket: "wss://api.demo.hitbtc.com/api/2/ws"},
+}
+
 type HitbtcApiConfig struct {
 }
 
-func NewHitbtcPublicApi() (*HitbtcApi, error) {
+func NewHitbtcPublicApi(opts ...options.Option) (*HitbtcApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "hitbtc",
+		BaseURL:            HITBTC_BASE_URL,
+		WebSocketURL:       "wss://api.hitbtc.com/api/2/ws",
+		Environments:       hitbtcEnvironments,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	shrimpyApi, err := unified.NewShrimpyApi()
 	if err != nil {
 		return nil, err
 	}
 	api := &HitbtcApi{
-		BaseURL:           HITBTC_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		precisionMap:      nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		boardCache:        cache.New(3*time.Second, 1*time.Second),
-		HttpClient:        &http.Client{},
-		ShrimpyClient:     shrimpyApi,
-
-		m: new(sync.Mutex),
-	}
-	api.fetchSettlements()
+		BaseURL:       o.BaseURL,
+		precisionMap:  nil,
+		cache:         o.NewCache(),
+		HttpClient:    cli,
+		ShrimpyClient: shrimpyApi,
+
+		opts: o,
+	}
 	return api, nil
 }
 
 type HitbtcApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	rateLastUpdated   time.Time
-	boardCache        *cache.Cache
-	HttpClient        *http.Client
-	ShrimpyClient     *unified.ShrimpyApiClient
-
-	settlements []string
-
-	m *sync.Mutex
-	c *HitbtcApiConfig
+	BaseURL       string
+	precisionMap  map[string]map[string]models.Precisions
+	precisionM    sync.Mutex
+	cache         *cache.Cache
+	HttpClient    *http.Client
+	ShrimpyClient *unified.ShrimpyApiClient
+
+	symbols symbolIndex
+
+	opts *options.Options
+	c    *HitbtcApiConfig
 }
 
 func (h *HitbtcApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *HitbtcApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "hitbtc",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *HitbtcApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.loadSymbols, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *HitbtcApi) publicApiUrl(command string) string {
 	return h.BaseURL + "/public/" + command
 }
 
-func (h *HitbtcApi) fetchSettlements() error {
-	settlements := make([]string, 0)
+// fetchSymbols indexes the symbols of /symbol by their base and quote
+// currencies, since symbols like BCNBTC cannot be split reliably.
+func (h *HitbtcApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("symbol")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := gabs.ParseJSON(byteArray)
 
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 
-	pairMap,err:= json.Children()
+	pairMap, err := json.Children()
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
+	ix := symbols.New()
 	for _, v := range pairMap {
-		settlement, ok := v.Path("quoteCurrency").Data().(string)
+		id, ok := v.Path("id").Data().(string)
+		if !ok {
+			continue
+		}
+		trading, ok := v.Path("baseCurrency").Data().(string)
 		if !ok {
 			continue
 		}
-		settlements = append(settlements, settlement)
-	}
-	m := make(map[string]bool)
-	uniq := []string{}
-	for _, ele := range settlements {
-		if !m[ele] {
-			m[ele] = true
-			uniq = append(uniq, ele)
+		settlement, ok := v.Path("quoteCurrency").Data().(string)
+		if !ok {
+			continue
 		}
+		addSymbol(h.opts, ix, id, models.CurrencyPair{Trading: trading, Settlement: settlement})
 	}
-	h.settlements = uniq
-	return nil
+	return ix, nil
+}
+
+// Symbols returns the index of /symbol, fetched on first use.
+func (h *HitbtcApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
+}
+
+func (h *HitbtcApi) loadSymbols() error {
+	_, err := h.Symbols()
+	return err
 }
 
 func Precision(numStr string) int {
@@ -125,11 +160,15 @@ func Precision(numStr string) int {
 }
 
 func (h *HitbtcApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
+	ix, err := h.Symbols()
+	if err != nil {
+		return err
+	}
 	url := h.publicApiUrl("ticker")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -143,20 +182,13 @@ func (h *HitbtcApi) fetchPrecision() error {
 	}
 	value := gjson.Parse(string(byteArray))
 
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Array() {
-		pair := v.Get("symbol").Str
-		var settlement string
-		var trading string
-		for _, s := range h.settlements {
-			index := strings.LastIndex(pair, s)
-			if index > 0 && index == len(pair)-len(s) {
-				settlement = s
-				trading = pair[0:index]
-			}
-		}
-		if settlement == "" || trading == "" {
+		pair, ok := ix.Pair(v.Get("symbol").Str)
+		if !ok {
 			continue
 		}
+		trading, settlement := pair.Trading, pair.Settlement
 		last := v.Get("last").Str
 		_, err = strconv.ParseFloat(last, 64)
 		if err != nil {
@@ -167,61 +199,56 @@ func (h *HitbtcApi) fetchPrecision() error {
 		if err != nil {
 			continue
 		}
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  Precision(last),
 			AmountPrecision: Precision(volume),
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
-func (h *HitbtcApi) fetchRate() error {
-	h.rateMap = make(map[string]map[string]float64)
-	h.volumeMap = make(map[string]map[string]float64)
-	h.orderBookTickMap = make(map[string]map[string]models.OrderBookTick)
+func (h *HitbtcApi) fetchRate() (*tickers, error) {
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	t := newTickers()
 	url := h.publicApiUrl("ticker")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := gabs.ParseJSON(byteArray)
 
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	rateMap,err := json.Children()
+	rateMap, err := json.Children()
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	for _, v := range rateMap {
-		pair, ok := v.Path("symbol").Data().(string)
+		symbol, ok := v.Path("symbol").Data().(string)
 		if !ok {
 			continue
 		}
-
-		var settlement string
-		var trading string
-		for _, s := range h.settlements {
-			index := strings.LastIndex(pair, s)
-			if index != -1 && index == len(pair)-len(s) {
-				settlement = s
-				trading = pair[0:index]
-			}
-		}
-		if settlement == "" || trading == "" {
+		pair, ok := ix.Pair(symbol)
+		if !ok {
 			continue
 		}
+		trading, settlement := pair.Trading, pair.Settlement
 		// update rate
 		last, ok := v.Path("last").Data().(string)
 		if !ok {
@@ -230,15 +257,10 @@ func (h *HitbtcApi) fetchRate() error {
 
 		lastf, err := strconv.ParseFloat(last, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		m, ok := h.rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.rateMap[trading] = m
-		}
-		m[settlement] = lastf
+		t.setRate(trading, settlement, lastf)
 
 		// update volume
 		volume, ok := v.Path("volume").Data().(string)
@@ -247,15 +269,10 @@ func (h *HitbtcApi) fetchRate() error {
 		}
 		volumef, err := strconv.ParseFloat(volume, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		m, ok = h.volumeMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.volumeMap[trading] = m
-		}
-		m[settlement] = volumef
+		t.setVolume(trading, settlement, volumef)
 
 		// update orderBookTick
 		askPrice, ok := v.Path("ask").Data().(string)
@@ -264,7 +281,7 @@ func (h *HitbtcApi) fetchRate() error {
 		}
 		askPricef, err := strconv.ParseFloat(askPrice, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		bidPrice, ok := v.Path("bid").Data().(string)
 		if !ok {
@@ -272,27 +289,22 @@ func (h *HitbtcApi) fetchRate() error {
 		}
 		bidPricef, err := strconv.ParseFloat(bidPrice, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		n, ok := h.orderBookTickMap[trading]
-		if !ok {
-			n = make(map[string]models.OrderBookTick)
-			h.orderBookTickMap[trading] = n
-		}
-		n[settlement] = models.OrderBookTick{
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestAskPrice: askPricef,
 			BestBidPrice: bidPricef,
-		}
+		})
 	}
 
-	return nil
+	return t, nil
 }
 
-func (h *HitbtcApi) fetchOrderBookTick() error {
-	boardMap, err := h.ShrimpyClient.GetBoards("hitbtc")
+func (h *HitbtcApi) fetchOrderBookTick() (map[string]map[string]models.OrderBookTick, error) {
+	boardMap, err := shrimpyBoards(h.ShrimpyClient, h.opts, "hitbtc")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
 	for settlement, m := range boardMap {
@@ -310,67 +322,41 @@ func (h *HitbtcApi) fetchOrderBookTick() error {
 			}
 		}
 	}
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return orderBookTickMap, nil
 }
 
 func (h *HitbtcApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchOrderBookTick()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
-	}
-	return h.orderBookTickMap, nil
+	return fetchOrderBookTicks(h.cache, h.fetchOrderBookTick)
 }
 
 func (h *HitbtcApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *HitbtcApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
 }
 
-func (h *HitbtcApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
+func (h *HitbtcApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
+}
 
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+func (h *HitbtcApi) CurrencyPairs() ([]models.CurrencyPair, error) {
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
 
 	var pairs []models.CurrencyPair
-	for trading, m := range h.rateMap {
+	for trading, m := range t.Rates {
 		for settlement := range m {
 			pair := models.CurrencyPair{
 				Trading:    trading,
@@ -388,7 +374,11 @@ func (h *HitbtcApi) Precise(trading string, settlement string) (*models.Precisio
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -399,49 +389,22 @@ func (h *HitbtcApi) Precise(trading string, settlement string) (*models.Precisio
 }
 
 func (h *HitbtcApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *HitbtcApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *HitbtcApi) FrozenCurrency() ([]string, error) {
@@ -460,7 +423,7 @@ func (h *HitbtcApi) FrozenCurrency() ([]string, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	currencyMap,err := json.Children()
+	currencyMap, err := json.Children()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
@@ -482,11 +445,17 @@ func (h *HitbtcApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *HitbtcApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	c, found := h.boardCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *HitbtcApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
 	}
-	url := h.publicApiUrl("orderbook/" + trading + settlement)
+	url := h.publicApiUrl("orderbook/" + symbol)
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to fetch %s", url)
@@ -561,6 +530,5 @@ func (h *HitbtcApi) Board(trading string, settlement string) (board *models.Boar
 		Bids: bids,
 		Asks: asks,
 	}
-	h.boardCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
 	return board, nil
 }
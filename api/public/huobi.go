@@ -1,17 +1,19 @@
 package public
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
 
-	"fmt"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/unified"
-	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"io/ioutil"
 	url2 "net/url"
 	"strconv"
@@ -22,59 +24,65 @@ const (
 	HUOBI_BASE_URL = "https://api.huobi.pro"
 )
 
-func NewHuobiPublicApi() (*HuobiApi, error) {
-	shrimpyApi, err := unified.NewShrimpyApi()
+// huobiMirrors are the hosts serving the production API. Private requests
+// sign the host, so they are not spread over them.
+var huobiMirrors = []string{HUOBI_BASE_URL, "https://api-aws.huobi.pro"}
+
+func NewHuobiPublicApi(opts ...options.Option) (*HuobiApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "huobi",
+		BaseURL:            HUOBI_BASE_URL,
+		Mirrors:            huobiMirrors,
+		Timeout:            10 * time.Second,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	api := &HuobiApi{
-		BaseURL:           HUOBI_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		boardCache:        cache.New(3*time.Second, 1*time.Second),
-		HttpClient:        &http.Client{Timeout: time.Duration(10) * time.Second},
-		ShrimpyClient:     shrimpyApi,
-		rt:                &http.Transport{},
-
-		m:         new(sync.Mutex),
-		rateM:     new(sync.Mutex),
-		currencyM: new(sync.Mutex),
-	}
-	api.fetchSettlements()
+		BaseURL:    o.BaseURL,
+		cache:      o.NewCache(),
+		HttpClient: cli,
+
+		opts: o,
+	}
 	return api, nil
 }
 
 type HuobiApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	rateLastUpdated   time.Time
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	currencyPairs     []models.CurrencyPair
-	boardCache        *cache.Cache
+	BaseURL      string
+	precisionMap map[string]map[string]models.Precisions
+	precisionM   sync.Mutex
+	symbols      symbolIndex
+	cache        *cache.Cache
 
-	HttpClient    *http.Client
-	ShrimpyClient *unified.ShrimpyApiClient
-
-	rt http.RoundTripper
+	HttpClient *http.Client
 
 	settlements []string
 
-	m         *sync.Mutex
-	rateM     *sync.Mutex
-	currencyM *sync.Mutex
+	opts *options.Options
 }
 
 func (h *HuobiApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *HuobiApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "huobi",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *HuobiApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *HuobiApi) publicApiUrl(command string) string {
 	return h.BaseURL + command
 }
@@ -119,19 +127,12 @@ func (h *HuobiApi) fetchSettlements() error {
 	return nil
 }
 
-type HuobiTickResponse struct {
-	response   []byte
-	Trading    string
-	Settlement string
-	err        error
-}
-
 func (h *HuobiApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url := h.publicApiUrl("/v1/common/symbols")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -146,200 +147,115 @@ func (h *HuobiApi) fetchPrecision() error {
 
 	value := gjson.Parse(string(byteArray))
 
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("data").Array() {
 		pricePrecision, err := strconv.Atoi(v.Get("price-precision").Raw)
 		if err != nil {
-			fmt.Println(err)
+			h.opts.Log().Warnw("couldn't parse price precision", "pair", v.Get("symbol").Str, "error", err)
 			continue
 		}
 		amountPrecision, err := strconv.Atoi(v.Get("amount-precision").Raw)
 		if err != nil {
-			fmt.Println(err)
+			h.opts.Log().Warnw("couldn't parse amount precision", "pair", v.Get("symbol").Str, "error", err)
 			continue
 		}
 		trading := strings.ToUpper(v.Get("base-currency").Str)
 		settlement := strings.ToUpper(v.Get("quote-currency").Str)
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  pricePrecision,
 			AmountPrecision: amountPrecision,
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
-func (h *HuobiApi) fetchRate() error {
-	rateMap := make(map[string]map[string]float64)
-	volumeMap := make(map[string]map[string]float64)
-	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
+// fetchRate fetches the tickers of every pair with a single request.
+func (h *HuobiApi) fetchRate() (*tickers, error) {
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
+	}
 
-	currencyPairs, err := h.CurrencyPairs()
+	url := h.publicApiUrl("/market/tickers")
+	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return err
-	}
-	ch := make(chan *HuobiTickResponse, len(currencyPairs))
-	workers := make(chan int, 10)
-	wg := &sync.WaitGroup{}
-	for _, v := range currencyPairs {
-		wg.Add(1)
-		workers <- 1
-		go func(trading string, settlement string) {
-			defer wg.Done()
-			url := h.publicApiUrl("/market/detail/merged?symbol=" + strings.ToLower(trading) + strings.ToLower(settlement))
-			cli := &http.Client{Transport: h.rt}
-			resp, err := cli.Get(url)
-			if err != nil {
-				ch <- &HuobiTickResponse{nil, trading, settlement, err}
-				<-workers
-				return
-			}
-			defer resp.Body.Close()
-			byteArray, err := ioutil.ReadAll(resp.Body)
-			ch <- &HuobiTickResponse{byteArray, trading, settlement, err}
-			<-workers
-		}(v.Trading, v.Settlement)
-	}
-	go func() {
-		wg.Wait()
-		close(ch)
-	}()
-
-	for r := range ch {
-		if r.err != nil {
-			continue
-		}
-		data, err := jason.NewObjectFromBytes(r.response)
-		if err != nil {
-			continue
-		}
-		tick, err := data.GetObject("tick")
-		if err != nil {
-			continue
-		}
-		volume, err := tick.GetFloat64("vol")
-		if err != nil {
-			continue
-		}
-		h.rateM.Lock()
-		n, ok := volumeMap[r.Trading]
-		if !ok {
-			n = make(map[string]float64)
-			volumeMap[r.Trading] = n
-		}
-		n[r.Settlement] = volume
-		close, err := tick.GetFloat64("close")
-		if err != nil {
-			continue
-		}
-		m, ok := rateMap[r.Trading]
-		if !ok {
-			m = make(map[string]float64)
-			rateMap[r.Trading] = m
-		}
-		m[r.Settlement] = close
-		ask, err := tick.GetFloat64Array("ask")
-		if err != nil || len(ask) == 0 {
-			continue
-		}
-		bid, err := tick.GetFloat64Array("bid")
-		if err != nil || len(bid) == 0 {
-			continue
-		}
-		l, ok := orderBookTickMap[r.Trading]
-		if !ok {
-			l = make(map[string]models.OrderBookTick)
-			orderBookTickMap[r.Trading] = l
-		}
-		l[r.Settlement] = models.OrderBookTick{
-			BestAskPrice: ask[0],
-			BestBidPrice: bid[0],
-		}
-		h.rateM.Unlock()
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
-	h.rateMap = rateMap
-	h.volumeMap = volumeMap
-	h.orderBookTickMap = orderBookTickMap
-	return nil
-}
+	defer resp.Body.Close()
 
-func (h *HuobiApi) fetchOrderBookTick() error {
-	boardMap, err := h.ShrimpyClient.GetBoards("huobi")
+	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
-	}
-	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
-	for settlement, m := range boardMap {
-		for trading, value := range m {
-			l, ok := orderBookTickMap[trading]
-			if !ok {
-				l = make(map[string]models.OrderBookTick)
-				orderBookTickMap[trading] = l
-			}
-			l[settlement] = models.OrderBookTick{
-				BestAskPrice:  value.BestAskPrice(),
-				BestAskAmount: value.BestAskAmount(),
-				BestBidPrice:  value.BestBidPrice(),
-				BestBidAmount: value.BestBidAmount(),
-			}
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
+	}
+	value := gjson.ParseBytes(byteArray)
+	if status := value.Get("status").Str; status != "ok" {
+		return nil, errors.Errorf("failed to fetch %s: %s %s", url, status, value.Get("err-msg").Str)
+	}
+
+	t := newTickers()
+	for _, v := range value.Get("data").Array() {
+		pair, ok := ix.Pair(v.Get("symbol").Str)
+		if !ok {
+			continue
 		}
+		t.setRate(pair.Trading, pair.Settlement, v.Get("close").Float())
+		t.setVolume(pair.Trading, pair.Settlement, v.Get("vol").Float())
+		t.setTick(pair.Trading, pair.Settlement, models.OrderBookTick{
+			BestAskPrice:  v.Get("ask").Float(),
+			BestAskAmount: v.Get("askSize").Float(),
+			BestBidPrice:  v.Get("bid").Float(),
+			BestBidAmount: v.Get("bidSize").Float(),
+		})
 	}
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return t, nil
 }
 
 func (h *HuobiApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchOrderBookTick()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.orderBookTickMap, nil
+	return copyTicks(t.Ticks), nil
 }
 
 func (h *HuobiApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *HuobiApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (h *HuobiApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
 }
 
 func (h *HuobiApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.currencyM.Lock()
-	defer h.currencyM.Unlock()
-	if len(h.currencyPairs) != 0 {
-		return h.currencyPairs, nil
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
 	}
+	return ix.Pairs(), nil
+}
+
+// fetchSymbols indexes the symbols of /v1/common/symbols by their base and
+// quote currencies.
+func (h *HuobiApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("/v1/common/symbols")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -359,70 +275,49 @@ func (h *HuobiApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	var pairs []models.CurrencyPair
+	ix := symbols.New()
 	for _, v := range data {
-		settlement, err := v.GetString("quote-currency")
+		symbol, err := v.GetString("symbol")
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse quote")
+			continue
 		}
 		trading, err := v.GetString("base-currency")
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse base")
+			continue
+		}
+		settlement, err := v.GetString("quote-currency")
+		if err != nil {
+			continue
 		}
-		pair := models.CurrencyPair{
+		addSymbol(h.opts, ix, symbol, models.CurrencyPair{
 			Trading:    strings.ToUpper(trading),
 			Settlement: strings.ToUpper(settlement),
-		}
-		pairs = append(pairs, pair)
+		})
 	}
-	h.currencyPairs = pairs
-	return pairs, nil
+	return ix, nil
 }
 
-func (h *HuobiApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
+func (h *HuobiApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
+}
 
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+func (h *HuobiApi) Volume(trading string, settlement string) (float64, error) {
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *HuobiApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *HuobiApi) Precise(trading string, settlement string) (*models.Precisions, error) {
@@ -430,10 +325,11 @@ func (h *HuobiApi) Precise(trading string, settlement string) (*models.Precision
 		return &models.Precisions{}, nil
 	}
 
-	err := h.fetchPrecision()
-	if err != nil {
+	if err := h.fetchPrecision(); err != nil {
 		return &models.Precisions{}, err
 	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -488,12 +384,18 @@ func (h *HuobiApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *HuobiApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	c, found := h.boardCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *HuobiApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
 	}
 	args := url2.Values{}
-	args.Add("symbol", strings.ToLower(trading)+strings.ToLower(settlement))
+	args.Add("symbol", symbol)
 	args.Add("type", "step0")
 	url := h.publicApiUrl("/market/depth?") + args.Encode()
 	resp, err := h.HttpClient.Get(url)
@@ -566,6 +468,5 @@ func (h *HuobiApi) Board(trading string, settlement string) (board *models.Board
 		Bids: bids,
 		Asks: asks,
 	}
-	h.boardCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
 	return board, nil
 }
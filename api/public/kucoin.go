@@ -1,6 +1,7 @@
 package public
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
@@ -10,69 +11,89 @@ import (
 	"strings"
 
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/unified"
-	"github.com/xuyangcn/go-exchange-client/models"
-	cache "github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/unified"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 )
 
 const (
 	KUCOIN_BASE_URL = "https://api.kucoin.com"
 )
 
-func NewKucoinPublicApi() (*KucoinApi, error) {
+// kucoinEnvironments are the endpoints besides production.
+var kucoinEnvironments = map[options.Environment]options.Endpoints{
+	options.Testnet: {REST: "https://openapi-sandbox.kucoin.com", WebSocket: ""},
+}
+
+func NewKucoinPublicApi(opts ...options.Option) (*KucoinApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "kucoin",
+		BaseURL:            KUCOIN_BASE_URL,
+		Environments:       kucoinEnvironments,
+		Timeout:            10 * time.Second,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	shrimpyApi, err := unified.NewShrimpyApi()
 	if err != nil {
 		return nil, err
 	}
 	api := &KucoinApi{
-		BaseURL:           KUCOIN_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		boardCache:        cache.New(3*time.Second, 1*time.Second),
-		HttpClient:        &http.Client{Timeout: time.Duration(10) * time.Second},
-		ShrimpyClient:     shrimpyApi,
-		rt:                &http.Transport{},
-
-		m:         new(sync.Mutex),
-		rateM:     new(sync.Mutex),
-		currencyM: new(sync.Mutex),
+		BaseURL:       o.BaseURL,
+		cache:         o.NewCache(),
+		HttpClient:    cli,
+		ShrimpyClient: shrimpyApi,
+		rt:            cli.Transport,
+
+		opts: o,
 	}
 	api.fetchSettlements()
 	return api, nil
 }
 
 type KucoinApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	rateLastUpdated   time.Time
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	boardCache        *cache.Cache
-	currencyPairs     []models.CurrencyPair
-	ShrimpyClient     *unified.ShrimpyApiClient
+	BaseURL       string
+	precisionMap  map[string]map[string]models.Precisions
+	precisionM    sync.Mutex
+	symbols       symbolIndex
+	cache         *cache.Cache
+	ShrimpyClient *unified.ShrimpyApiClient
 
 	HttpClient *http.Client
 	rt         http.RoundTripper
 
 	settlements []string
 
-	m         *sync.Mutex
-	rateM     *sync.Mutex
-	currencyM *sync.Mutex
+	opts *options.Options
 }
 
 func (h *KucoinApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *KucoinApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "kucoin",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *KucoinApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *KucoinApi) publicApiUrl(command string) string {
 	return h.BaseURL + command
 }
@@ -82,10 +103,10 @@ func (h *KucoinApi) fetchSettlements() error {
 	return nil
 }
 
-func (h *KucoinApi) fetchOrderBookTick() error {
-	boardMap, err := h.ShrimpyClient.GetBoards("kucoin")
+func (h *KucoinApi) fetchOrderBookTick() (map[string]map[string]models.OrderBookTick, error) {
+	boardMap, err := shrimpyBoards(h.ShrimpyClient, h.opts, "kucoin")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
 	for settlement, m := range boardMap {
@@ -103,22 +124,11 @@ func (h *KucoinApi) fetchOrderBookTick() error {
 			}
 		}
 	}
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return orderBookTickMap, nil
 }
 
 func (h *KucoinApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchOrderBookTick()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
-	}
-	return h.orderBookTickMap, nil
+	return fetchOrderBookTicks(h.cache, h.fetchOrderBookTick)
 }
 
 type KucoinTickResponse struct {
@@ -138,6 +148,8 @@ func requestGetAsChrome(url string) (*http.Request, error) {
 }
 
 func (h *KucoinApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
@@ -161,8 +173,6 @@ func (h *KucoinApi) fetchPrecision() error {
 		coinPrecision[v.Get("currency").Str] = int(v.Get("precision").Int())
 	}
 
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url = h.publicApiUrl("/api/v1/market/allTickers")
 	req, err = requestGetAsChrome(url)
 	if err != nil {
@@ -178,6 +188,7 @@ func (h *KucoinApi) fetchPrecision() error {
 		return errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	value = gjson.ParseBytes(byteArray)
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("data.ticker").Array() {
 
 		currencies := strings.Split(v.Get("symbol").Str, "-")
@@ -199,38 +210,37 @@ func (h *KucoinApi) fetchPrecision() error {
 			}
 		}
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  maxPrecision,
 			AmountPrecision: volPrecision,
 		}
 	}
-	return errors.Wrapf(err, "failed to fetch %s", url)
+	h.precisionMap = precisionMap
+	return nil
 }
 
-func (h *KucoinApi) fetchRate() error {
+func (h *KucoinApi) fetchRate() (*tickers, error) {
 	url := h.publicApiUrl("/api/v1/market/allTickers")
 	req, err := requestGetAsChrome(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	resp, err := h.HttpClient.Do(req)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	value := gjson.ParseBytes(byteArray)
-	rateMap := make(map[string]map[string]float64)
-	volumeMap := make(map[string]map[string]float64)
-	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
+	t := newTickers()
 	for _, v := range value.Get("data.ticker").Array() {
 		currencies := strings.Split(v.Get("symbol").Str, "-")
 		if len(currencies) < 2 {
@@ -244,48 +254,22 @@ func (h *KucoinApi) fetchRate() error {
 		bestbidPrice := v.Get("buy").Float()
 		bestaskPrice := v.Get("sell").Float()
 
-		h.rateM.Lock()
-		n, ok := volumeMap[trading]
-		if !ok {
-			n = make(map[string]float64)
-			volumeMap[trading] = n
-		}
-		n[settlement] = volumef
-		m, ok := rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			rateMap[trading] = m
-		}
-		m[settlement] = lastf
-		l, ok := orderBookTickMap[trading]
-		if !ok {
-			l = make(map[string]models.OrderBookTick)
-			orderBookTickMap[trading] = l
-		}
-		l[settlement] = models.OrderBookTick{
+		t.setVolume(trading, settlement, volumef)
+		t.setRate(trading, settlement, lastf)
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestBidPrice: bestbidPrice,
 			BestAskPrice: bestaskPrice,
-		}
-		h.rateM.Unlock()
+		})
 	}
-	h.rateMap = rateMap
-	h.volumeMap = volumeMap
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return t, nil
 }
 
 func (h *KucoinApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *KucoinApi) Precise(trading string, settlement string) (*models.Precisions, error) {
@@ -293,7 +277,11 @@ func (h *KucoinApi) Precise(trading string, settlement string) (*models.Precisio
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -304,27 +292,28 @@ func (h *KucoinApi) Precise(trading string, settlement string) (*models.Precisio
 }
 
 func (h *KucoinApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (h *KucoinApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
 }
 
 func (h *KucoinApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.currencyM.Lock()
-	defer h.currencyM.Unlock()
-	if len(h.currencyPairs) != 0 {
-		return h.currencyPairs, nil
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
 	}
-	h.fetchSettlements()
-	currecyPairs := make([]models.CurrencyPair, 0)
+	return ix.Pairs(), nil
+}
+
+// fetchSymbols indexes the symbols of /api/v1/symbols by their base and quote
+// currencies.
+func (h *KucoinApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("/api/v1/symbols")
 	req, err := requestGetAsChrome(url)
 	if err != nil {
@@ -340,7 +329,6 @@ func (h *KucoinApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
-
 	json, err := jason.NewObjectFromBytes(byteArray)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
@@ -349,7 +337,12 @@ func (h *KucoinApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
+	ix := symbols.New()
 	for _, v := range data {
+		symbol, err := v.GetString("symbol")
+		if err != nil {
+			continue
+		}
 		trading, err := v.GetString("baseCurrency")
 		if err != nil {
 			continue
@@ -358,59 +351,32 @@ func (h *KucoinApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 		if err != nil {
 			continue
 		}
-		currecyPairs = append(currecyPairs, models.CurrencyPair{
-			Trading:    trading,
-			Settlement: settlement,
-		})
+		addSymbol(h.opts, ix, symbol, models.CurrencyPair{Trading: trading, Settlement: settlement})
 	}
-	h.currencyPairs = currecyPairs
-	return currecyPairs, nil
+	return ix, nil
 }
 
-func (h *KucoinApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
+func (h *KucoinApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
+}
 
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+func (h *KucoinApi) Volume(trading string, settlement string) (float64, error) {
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *KucoinApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *KucoinApi) FrozenCurrency() ([]string, error) {
@@ -459,12 +425,18 @@ func (h *KucoinApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *KucoinApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	c, found := h.boardCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *KucoinApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
 	}
 	args := url2.Values{}
-	args.Add("symbol", strings.ToUpper(trading)+"-"+strings.ToUpper(settlement))
+	args.Add("symbol", symbol)
 	url := h.publicApiUrl("/api/v2/market/orderbook/level2?") + args.Encode()
 	req, err := requestGetAsChrome(url)
 	if err != nil {
@@ -508,6 +480,5 @@ func (h *KucoinApi) Board(trading string, settlement string) (board *models.Boar
 		Bids: bids,
 		Asks: asks,
 	}
-	h.boardCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
 	return board, nil
 }
@@ -1,15 +1,19 @@
 package public
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"io/ioutil"
 	url2 "net/url"
 	"strings"
@@ -19,53 +23,62 @@ const (
 	LBANK_BASE_URL = "https://api.lbkex.com"
 )
 
-func NewLbankPublicApi() (*LbankApi, error) {
+func NewLbankPublicApi(opts ...options.Option) (*LbankApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "lbank",
+		BaseURL:            LBANK_BASE_URL,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	api := &LbankApi{
-		BaseURL:           LBANK_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		boardCache:        cache.New(3*time.Second, 1*time.Second),
-
-		HttpClient: &http.Client{},
-		rt:         &http.Transport{},
-
-		m:         new(sync.Mutex),
-		rateM:     new(sync.Mutex),
-		currencyM: new(sync.Mutex),
-	}
-	api.fetchSettlements()
+		BaseURL: o.BaseURL,
+		cache:   o.NewCache(),
+
+		HttpClient: cli,
+		rt:         cli.Transport,
+
+		opts: o,
+	}
 	return api, nil
 }
 
 type LbankApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	rateLastUpdated   time.Time
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	currencyPairs     []models.CurrencyPair
-	boardCache        *cache.Cache
+	BaseURL      string
+	precisionMap map[string]map[string]models.Precisions
+	precisionM   sync.Mutex
+	symbols      symbolIndex
+	cache        *cache.Cache
 
 	HttpClient *http.Client
 	rt         http.RoundTripper
 
 	settlements []string
 
-	m         *sync.Mutex
-	rateM     *sync.Mutex
-	currencyM *sync.Mutex
+	opts *options.Options
 }
 
 func (h *LbankApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *LbankApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "lbank",
+		Operations: models.Except(models.PublicOperations, models.OpOrderBookTickMap),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *LbankApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *LbankApi) publicApiUrl(command string) string {
 	return h.BaseURL + command
 }
@@ -95,11 +108,11 @@ type LbankTickResponse struct {
 }
 
 func (h *LbankApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url := h.publicApiUrl("/v1/ticker.do") + "?symbol=all"
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -113,6 +126,7 @@ func (h *LbankApi) fetchPrecision() error {
 	}
 	value := gjson.Parse(string(byteArray))
 
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Array() {
 		pairString := v.Get("symbol").Str
 		last := v.Get("ticker.latest").Raw
@@ -125,61 +139,61 @@ func (h *LbankApi) fetchPrecision() error {
 		trading := strings.ToUpper(currencies[0])
 		settlement := strings.ToUpper(currencies[1])
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  Precision(last),
 			AmountPrecision: Precision(volume),
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
-func (h *LbankApi) fetchRate() error {
-	h.rateMap = make(map[string]map[string]float64)
-	h.volumeMap = make(map[string]map[string]float64)
+func (h *LbankApi) fetchRate() (*tickers, error) {
+	t := newTickers()
 	url := h.publicApiUrl("/v1/ticker.do") + "?symbol=all"
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := jason.NewValueFromBytes(byteArray)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	data, err := json.Array()
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	for _, v := range data {
 		vo, err := v.Object()
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse object")
+			return nil, errors.Wrapf(err, "failed to parse object")
 		}
 		pairString, err := vo.GetString("symbol")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse symbol")
+			return nil, errors.Wrapf(err, "failed to parse symbol")
 		}
 		ticker, err := vo.GetObject("ticker")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse ticker")
+			return nil, errors.Wrapf(err, "failed to parse ticker")
 		}
 		lastf, err := ticker.GetFloat64("latest")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse latest")
+			return nil, errors.Wrapf(err, "failed to parse latest")
 		}
 		volumef, err := ticker.GetFloat64("vol")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse vol")
+			return nil, errors.Wrapf(err, "failed to parse vol")
 		}
 
 		currencies := strings.Split(pairString, "_")
@@ -188,20 +202,10 @@ func (h *LbankApi) fetchRate() error {
 		}
 		trading := strings.ToUpper(currencies[0])
 		settlement := strings.ToUpper(currencies[1])
-		m, ok := h.rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.rateMap[trading] = m
-		}
-		m[settlement] = lastf
-		m, ok = h.volumeMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.volumeMap[trading] = m
-		}
-		m[settlement] = volumef
+		t.setRate(trading, settlement, lastf)
+		t.setVolume(trading, settlement, volumef)
 	}
-	return nil
+	return t, nil
 }
 
 func (h *LbankApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
@@ -210,31 +214,23 @@ func (h *LbankApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTic
 }
 
 func (h *LbankApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *LbankApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (h *LbankApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
 }
 
 func (h *LbankApi) Precise(trading string, settlement string) (*models.Precisions, error) {
@@ -242,7 +238,11 @@ func (h *LbankApi) Precise(trading string, settlement string) (*models.Precision
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -253,11 +253,16 @@ func (h *LbankApi) Precise(trading string, settlement string) (*models.Precision
 }
 
 func (h *LbankApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.currencyM.Lock()
-	defer h.currencyM.Unlock()
-	if len(h.currencyPairs) != 0 {
-		return h.currencyPairs, nil
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
 	}
+	return ix.Pairs(), nil
+}
+
+// fetchSymbols indexes the symbols of /v1/currencyPairs.do, which are
+// TRADING_SETTLEMENT in lower case.
+func (h *LbankApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("/v1/currencyPairs.do")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -271,76 +276,51 @@ func (h *LbankApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	}
 	json, err := jason.NewValueFromBytes(byteArray)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse json 1")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	data, err := json.Array()
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse json 2")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	var pairs []models.CurrencyPair
+	ix := symbols.New()
 	for _, v := range data {
-		pairString, err := v.String()
+		symbol, err := v.String()
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse quote")
+			continue
 		}
-		currencies := strings.Split(pairString, "_")
+		currencies := strings.Split(symbol, "_")
 		if len(currencies) != 2 {
 			continue
 		}
-		pair := models.CurrencyPair{
+		addSymbol(h.opts, ix, symbol, models.CurrencyPair{
 			Trading:    strings.ToUpper(currencies[0]),
 			Settlement: strings.ToUpper(currencies[1]),
-		}
-		pairs = append(pairs, pair)
+		})
 	}
-	h.currencyPairs = pairs
-	return pairs, nil
+	return ix, nil
 }
 
-func (h *LbankApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
+func (h *LbankApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
+}
 
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+func (h *LbankApi) Volume(trading string, settlement string) (float64, error) {
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *LbankApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *LbankApi) FrozenCurrency() ([]string, error) {
@@ -385,12 +365,18 @@ func (h *LbankApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *LbankApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	c, found := h.boardCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *LbankApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
 	}
 	args := url2.Values{}
-	args.Add("symbol", strings.ToLower(trading)+"_"+strings.ToLower(settlement))
+	args.Add("symbol", symbol)
 	args.Add("size", "60")
 	method := "/v1/depth.do?" + args.Encode()
 	url := h.publicApiUrl(method)
@@ -460,7 +446,6 @@ func (h *LbankApi) Board(trading string, settlement string) (board *models.Board
 		Bids: bids,
 		Asks: asks,
 	}
-	h.boardCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
 
 	return board, nil
 }
@@ -1,8 +1,11 @@
 // Code generated by mockery v1.0.0
 package mocks
 
+import context "context"
+import http "net/http"
 import mock "github.com/stretchr/testify/mock"
 import models "github.com/xuyangcn/go-exchange-client/models"
+import symbols "github.com/xuyangcn/go-exchange-client/symbols"
 
 // PublicClient is an autogenerated mock type for the PublicClient type
 type PublicClient struct {
@@ -32,6 +35,20 @@ func (_m *PublicClient) Board(trading string, settlement string) (*models.Board,
 	return r0, r1
 }
 
+// Capabilities provides a mock function with given fields:
+func (_m *PublicClient) Capabilities() models.Capabilities {
+	ret := _m.Called()
+
+	var r0 models.Capabilities
+	if rf, ok := ret.Get(0).(func() models.Capabilities); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(models.Capabilities)
+	}
+
+	return r0
+}
+
 // CurrencyPairs provides a mock function with given fields:
 func (_m *PublicClient) CurrencyPairs() ([]models.CurrencyPair, error) {
 	ret := _m.Called()
@@ -78,6 +95,52 @@ func (_m *PublicClient) FrozenCurrency() ([]string, error) {
 	return r0, r1
 }
 
+// OrderBookTickMap provides a mock function with given fields:
+func (_m *PublicClient) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
+	ret := _m.Called()
+
+	var r0 map[string]map[string]models.OrderBookTick
+	if rf, ok := ret.Get(0).(func() map[string]map[string]models.OrderBookTick); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[string]models.OrderBookTick)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Precise provides a mock function with given fields: trading, settlement
+func (_m *PublicClient) Precise(trading string, settlement string) (*models.Precisions, error) {
+	ret := _m.Called(trading, settlement)
+
+	var r0 *models.Precisions
+	if rf, ok := ret.Get(0).(func(string, string) *models.Precisions); ok {
+		r0 = rf(trading, settlement)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Precisions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(trading, settlement)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Rate provides a mock function with given fields: trading, settlement
 func (_m *PublicClient) Rate(trading string, settlement string) (float64, error) {
 	ret := _m.Called(trading, settlement)
@@ -122,6 +185,66 @@ func (_m *PublicClient) RateMap() (map[string]map[string]float64, error) {
 	return r0, r1
 }
 
+// SetTransport provides a mock function with given fields: transport
+func (_m *PublicClient) SetTransport(transport http.RoundTripper) error {
+	ret := _m.Called(transport)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(http.RoundTripper) error); ok {
+		r0 = rf(transport)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Snapshot provides a mock function with given fields:
+func (_m *PublicClient) Snapshot() (*models.MarketSnapshot, error) {
+	ret := _m.Called()
+
+	var r0 *models.MarketSnapshot
+	if rf, ok := ret.Get(0).(func() *models.MarketSnapshot); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.MarketSnapshot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Symbols provides a mock function with given fields:
+func (_m *PublicClient) Symbols() (*symbols.Index, error) {
+	ret := _m.Called()
+
+	var r0 *symbols.Index
+	if rf, ok := ret.Get(0).(func() *symbols.Index); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*symbols.Index)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Volume provides a mock function with given fields: trading, settlement
 func (_m *PublicClient) Volume(trading string, settlement string) (float64, error) {
 	ret := _m.Called(trading, settlement)
@@ -165,3 +288,17 @@ func (_m *PublicClient) VolumeMap() (map[string]map[string]float64, error) {
 
 	return r0, r1
 }
+
+// Warmup provides a mock function with given fields: ctx
+func (_m *PublicClient) Warmup(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
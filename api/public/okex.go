@@ -1,15 +1,20 @@
 package public
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/unified"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/unified"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"io/ioutil"
 	url2 "net/url"
 	"strconv"
@@ -20,42 +25,42 @@ const (
 	OKEX_BASE_URL = "https://www.okex.com"
 )
 
-func NewOkexPublicApi() (*OkexApi, error) {
+func NewOkexPublicApi(opts ...options.Option) (*OkexApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "okex",
+		BaseURL:           OKEX_BASE_URL,
+		RateCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	shrimpyApi, err := unified.NewShrimpyApi()
 	if err != nil {
 		return nil, err
 	}
 	api := &OkexApi{
-		BaseURL:                    OKEX_BASE_URL,
-		RateCacheDuration:          3 * time.Second,
-		rateMap:                    nil,
-		volumeMap:                  nil,
-		orderBookTickMap:           nil,
-		rateLastUpdated:            time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		BaseURL:                    o.BaseURL,
+		cache:                      o.NewCache(),
 		CurrencyPairsCacheDuration: 7 * 24 * time.Hour,
 		currencyPairsLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
 
-		HttpClient:    &http.Client{},
+		HttpClient:    cli,
 		ShrimpyClient: shrimpyApi,
-		rt:            &http.Transport{},
+		rt:            cli.Transport,
 
-		m:         new(sync.Mutex),
-		rateM:     new(sync.Mutex),
-		currencyM: new(sync.Mutex),
+		opts: o,
 	}
-	api.fetchSettlements()
 	return api, nil
 }
 
 type OkexApi struct {
 	BaseURL                    string
-	RateCacheDuration          time.Duration
-	rateLastUpdated            time.Time
-	volumeMap                  map[string]map[string]float64
-	rateMap                    map[string]map[string]float64
-	orderBookTickMap           map[string]map[string]models.OrderBookTick
 	precisionMap               map[string]map[string]models.Precisions
-	currencyPairs              []models.CurrencyPair
+	precisionM                 sync.Mutex
+	symbols                    symbolIndex
+	cache                      *cache.Cache
 	CurrencyPairsCacheDuration time.Duration
 	currencyPairsLastUpdated   time.Time
 
@@ -66,15 +71,25 @@ type OkexApi struct {
 
 	settlements []string
 
-	m         *sync.Mutex
-	rateM     *sync.Mutex
-	currencyM *sync.Mutex
+	opts *options.Options
 }
 
 func (h *OkexApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
+
+func (h *OkexApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "okex",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *OkexApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
 func (h *OkexApi) publicApiUrl(command string) string {
 	return h.BaseURL + command
 }
@@ -97,11 +112,11 @@ func (h *OkexApi) fetchSettlements() error {
 }
 
 func (h *OkexApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url := h.publicApiUrl("/v2/spot/markets/tickers")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -115,6 +130,7 @@ func (h *OkexApi) fetchPrecision() error {
 	}
 	value := gjson.Parse(string(byteArray))
 
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for _, v := range value.Get("data").Array() {
 		last := v.Get("last").Str
 		volume := v.Get("volume").Str
@@ -126,16 +142,17 @@ func (h *OkexApi) fetchPrecision() error {
 		}
 		trading := currencies[0]
 		settlement := currencies[1]
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  Precision(last),
 			AmountPrecision: Precision(volume),
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
@@ -146,67 +163,65 @@ type OkexTickResponse struct {
 	err        error
 }
 
-func (h *OkexApi) fetchRate() error {
-	h.rateMap = make(map[string]map[string]float64)
-	h.volumeMap = make(map[string]map[string]float64)
-	h.orderBookTickMap = make(map[string]map[string]models.OrderBookTick)
+func (h *OkexApi) fetchRate() (*tickers, error) {
+	t := newTickers()
 	url := h.publicApiUrl("/v2/spot/markets/tickers")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := jason.NewObjectFromBytes(byteArray)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	data, err := json.GetObjectArray("data")
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 	for _, v := range data {
 		lastString, err := v.GetString("last")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		lastf, err := strconv.ParseFloat(lastString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 
 		volumeString, err := v.GetString("volume")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		volumef, err := strconv.ParseFloat(volumeString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		buyString, err := v.GetString("buy")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		buyf, err := strconv.ParseFloat(buyString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		sellString, err := v.GetString("sell")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		sellf, err := strconv.ParseFloat(sellString, 64)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 
 		pairString, err := v.GetString("symbol")
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse quote")
+			return nil, errors.Wrapf(err, "failed to parse quote")
 		}
 		currencies := strings.Split(pairString, "_")
 		if len(currencies) != 2 {
@@ -214,35 +229,20 @@ func (h *OkexApi) fetchRate() error {
 		}
 		trading := currencies[0]
 		settlement := currencies[1]
-		m, ok := h.rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.rateMap[trading] = m
-		}
-		m[settlement] = lastf
-		m, ok = h.volumeMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.volumeMap[trading] = m
-		}
-		m[settlement] = volumef
-		n, ok := h.orderBookTickMap[trading]
-		if !ok {
-			n = make(map[string]models.OrderBookTick)
-			h.orderBookTickMap[trading] = n
-		}
-		n[settlement] = models.OrderBookTick{
+		t.setRate(trading, settlement, lastf)
+		t.setVolume(trading, settlement, volumef)
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestAskPrice: sellf,
 			BestBidPrice: buyf,
-		}
+		})
 	}
-	return nil
+	return t, nil
 }
 
-func (h *OkexApi) fetchOrderBookTick() error {
-	boardMap, err := h.ShrimpyClient.GetBoards("okex")
+func (h *OkexApi) fetchOrderBookTick() (map[string]map[string]models.OrderBookTick, error) {
+	boardMap, err := shrimpyBoards(h.ShrimpyClient, h.opts, "okex")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
 	for settlement, m := range boardMap {
@@ -260,58 +260,44 @@ func (h *OkexApi) fetchOrderBookTick() error {
 			}
 		}
 	}
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return orderBookTickMap, nil
 }
 
 func (h *OkexApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchOrderBookTick()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
-	}
-	return h.orderBookTickMap, nil
+	return fetchOrderBookTicks(h.cache, h.fetchOrderBookTick)
 }
 
 func (h *OkexApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *OkexApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
+}
+
+func (h *OkexApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
 }
 
 func (h *OkexApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.currencyM.Lock()
-	defer h.currencyM.Unlock()
-	if len(h.currencyPairs) != 0 {
-		return h.currencyPairs, nil
+	ix, err := h.Symbols()
+	if err != nil {
+		return nil, err
 	}
+	return ix.Pairs(), nil
+}
+
+// fetchSymbols indexes the symbols of /v2/markets/products, which are
+// trading_settlement.
+func (h *OkexApi) fetchSymbols() (*symbols.Index, error) {
 	url := h.publicApiUrl("/v2/markets/products")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -331,24 +317,26 @@ func (h *OkexApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	var pairs []models.CurrencyPair
+	ix := symbols.New()
 	for _, v := range data {
-		pairString, err := v.GetString("symbol")
+		symbol, err := v.GetString("symbol")
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse quote")
+			continue
 		}
-		currencies := strings.Split(pairString, "_")
+		currencies := strings.Split(symbol, "_")
 		if len(currencies) != 2 {
 			continue
 		}
-		pair := models.CurrencyPair{
+		addSymbol(h.opts, ix, symbol, models.CurrencyPair{
 			Trading:    strings.ToUpper(currencies[0]),
 			Settlement: strings.ToUpper(currencies[1]),
-		}
-		pairs = append(pairs, pair)
+		})
 	}
-	h.currencyPairs = pairs
-	return pairs, nil
+	return ix, nil
+}
+
+func (h *OkexApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(h.fetchSymbols)
 }
 
 func (h *OkexApi) Precise(trading string, settlement string) (*models.Precisions, error) {
@@ -356,7 +344,11 @@ func (h *OkexApi) Precise(trading string, settlement string) (*models.Precisions
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -367,49 +359,22 @@ func (h *OkexApi) Precise(trading string, settlement string) (*models.Precisions
 }
 
 func (h *OkexApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *OkexApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *OkexApi) FrozenCurrency() ([]string, error) {
@@ -455,9 +420,19 @@ func (h *OkexApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *OkexApi) Board(trading string, settlement string) (board *models.Board, err error) {
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *OkexApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
+	}
 	args := url2.Values{}
 	args.Add("size", "200")
-	method := "/v2/markets/" + strings.ToLower(trading) + "_" + strings.ToLower(settlement) + "/depth?" + args.Encode()
+	method := "/v2/markets/" + symbol + "/depth?" + args.Encode()
 	url := h.publicApiUrl(method)
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
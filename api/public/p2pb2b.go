@@ -1,6 +1,7 @@
 package public
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -9,10 +10,13 @@ import (
 	"time"
 
 	"github.com/Jeffail/gabs"
-	"github.com/xuyangcn/go-exchange-client/models"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 )
 
 const (
@@ -22,46 +26,60 @@ const (
 type P2pb2bApiConfig struct {
 }
 
-func NewP2pb2bPublicApi() (*P2pb2bApi, error) {
+func NewP2pb2bPublicApi(opts ...options.Option) (*P2pb2bApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:           "p2pb2b",
+		BaseURL:            P2PB2B_BASE_URL,
+		RateCacheDuration:  3 * time.Second,
+		BoardCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	api := &P2pb2bApi{
-		BaseURL:           P2PB2B_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		precisionMap:      nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		boardCache:        cache.New(3*time.Second, 1*time.Second),
-		HttpClient:        &http.Client{},
-
-		m: new(sync.Mutex),
-	}
-	api.fetchSettlements()
+		BaseURL:      o.BaseURL,
+		precisionMap: nil,
+		cache:        o.NewCache(),
+		HttpClient:   cli,
+
+		opts: o,
+	}
 	return api, nil
 }
 
 type P2pb2bApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	rateLastUpdated   time.Time
-	boardCache        *cache.Cache
-	HttpClient        *http.Client
+	BaseURL      string
+	precisionMap map[string]map[string]models.Precisions
+	precisionM   sync.Mutex
+	symbols      symbolIndex
+	cache        *cache.Cache
+	HttpClient   *http.Client
 
 	settlements []string
 
-	m *sync.Mutex
-	c *P2pb2bApiConfig
+	opts *options.Options
+	c    *P2pb2bApiConfig
 }
 
 func (h *P2pb2bApi) SetTransport(transport http.RoundTripper) error {
-	h.HttpClient.Transport = transport
+	options.SetTransport(h.HttpClient, h.opts.WrapTransport(transport))
 	return nil
 }
 
+func (h *P2pb2bApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "p2pb2b",
+		Operations: models.Except(models.PublicOperations, models.OpFrozenCurrency),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (h *P2pb2bApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, h.fetchPrecision, loadCurrencyPairs(h))
+}
+
 func (h *P2pb2bApi) publicApiUrl(command string) string {
 	return h.BaseURL + "/" + command
 }
@@ -84,7 +102,7 @@ func (h *P2pb2bApi) fetchSettlements() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse json")
 	}
-	pairs,err := json.Path("result").Children()
+	pairs, err := json.Path("result").Children()
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse json")
 	}
@@ -105,11 +123,11 @@ func (h *P2pb2bApi) fetchSettlements() error {
 }
 
 func (h *P2pb2bApi) fetchPrecision() error {
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if h.precisionMap != nil {
 		return nil
 	}
-	h.precisionMap = make(map[string]map[string]models.Precisions)
-
 	url := h.publicApiUrl("public/tickers")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
@@ -125,10 +143,11 @@ func (h *P2pb2bApi) fetchPrecision() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse json")
 	}
-	rateMap,err := json.Path("result").ChildrenMap()
+	rateMap, err := json.Path("result").ChildrenMap()
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse json")
 	}
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for k, v := range rateMap {
 		coins := strings.Split(k, "_")
 		if len(coins) != 2 {
@@ -158,41 +177,40 @@ func (h *P2pb2bApi) fetchPrecision() error {
 			return err
 		}
 
-		m, ok := h.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			h.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  Precision(last),
 			AmountPrecision: Precision(volume),
 		}
 	}
+	h.precisionMap = precisionMap
 	return nil
 }
 
-func (h *P2pb2bApi) fetchRate() error {
-	h.rateMap = make(map[string]map[string]float64)
-	h.volumeMap = make(map[string]map[string]float64)
-	h.orderBookTickMap = make(map[string]map[string]models.OrderBookTick)
+func (h *P2pb2bApi) fetchRate() (*tickers, error) {
+	t := newTickers()
 	url := h.publicApiUrl("public/tickers")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 
 	byteArray, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	json, err := gabs.ParseJSON(byteArray)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
-	rateMap,err := json.Path("result").ChildrenMap()
+	rateMap, err := json.Path("result").ChildrenMap()
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json children map")
+		return nil, errors.Wrapf(err, "failed to parse json children map")
 	}
 	for k, v := range rateMap {
 		coins := strings.Split(k, "_")
@@ -203,6 +221,7 @@ func (h *P2pb2bApi) fetchRate() error {
 		if settlement == "" || trading == "" {
 			continue
 		}
+		t.setSymbol(k, trading, settlement)
 		last, ok := v.Path("ticker").Path("last").Data().(string)
 		if !ok {
 			continue
@@ -210,15 +229,10 @@ func (h *P2pb2bApi) fetchRate() error {
 		// update rate
 		lastf, err := strconv.ParseFloat(last, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		m, ok := h.rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.rateMap[trading] = m
-		}
-		m[settlement] = lastf
+		t.setRate(trading, settlement, lastf)
 
 		// update volume
 		volume, ok := v.Path("ticker").Path("vol").Data().(string)
@@ -227,15 +241,10 @@ func (h *P2pb2bApi) fetchRate() error {
 		}
 		volumef, err := strconv.ParseFloat(volume, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		m, ok = h.volumeMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			h.volumeMap[trading] = m
-		}
-		m[settlement] = volumef
+		t.setVolume(trading, settlement, volumef)
 
 		// update orderBookTick
 		askPrice, ok := v.Path("ticker").Path("ask").Data().(string)
@@ -244,7 +253,7 @@ func (h *P2pb2bApi) fetchRate() error {
 		}
 		askPricef, err := strconv.ParseFloat(askPrice, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		bidPrice, ok := v.Path("ticker").Path("bid").Data().(string)
 		if !ok {
@@ -252,80 +261,54 @@ func (h *P2pb2bApi) fetchRate() error {
 		}
 		bidPricef, err := strconv.ParseFloat(bidPrice, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		n, ok := h.orderBookTickMap[trading]
-		if !ok {
-			n = make(map[string]models.OrderBookTick)
-			h.orderBookTickMap[trading] = n
-		}
-		n[settlement] = models.OrderBookTick{
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestAskPrice: askPricef,
 			BestBidPrice: bidPricef,
-		}
+		})
 	}
 
-	return nil
+	return t, nil
 }
 
 func (h *P2pb2bApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.orderBookTickMap, nil
+	return copyTicks(t.Ticks), nil
 }
 
 func (h *P2pb2bApi) RateMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (h *P2pb2bApi) VolumeMap() (map[string]map[string]float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return h.volumeMap, nil
+	return copyRates(t.Volumes), nil
 }
 
-func (h *P2pb2bApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
+func (h *P2pb2bApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(h.cache, h.fetchRate)
+}
 
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
+func (h *P2pb2bApi) CurrencyPairs() ([]models.CurrencyPair, error) {
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return nil, err
 	}
 
 	var pairs []models.CurrencyPair
-	for trading, m := range h.rateMap {
+	for trading, m := range t.Rates {
 		for settlement := range m {
 			pair := models.CurrencyPair{
 				Trading:    trading,
@@ -338,12 +321,20 @@ func (h *P2pb2bApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	return pairs, nil
 }
 
+func (h *P2pb2bApi) Symbols() (*symbols.Index, error) {
+	return h.symbols.load(indexTickers(h.opts, h.cache, h.fetchRate))
+}
+
 func (h *P2pb2bApi) Precise(trading string, settlement string) (*models.Precisions, error) {
 	if trading == settlement {
 		return &models.Precisions{}, nil
 	}
 
-	h.fetchPrecision()
+	if err := h.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	h.precisionM.Lock()
+	defer h.precisionM.Unlock()
 	if m, ok := h.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -354,49 +345,22 @@ func (h *P2pb2bApi) Precise(trading string, settlement string) (*models.Precisio
 }
 
 func (h *P2pb2bApi) Volume(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.volumeMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return volume, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (h *P2pb2bApi) Rate(trading string, settlement string) (float64, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		h.rateLastUpdated = now
-	}
-	if m, ok := h.rateMap[trading]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.Errorf("%s/%s", trading, settlement)
-	} else {
-		return rate, nil
+	t, err := fetchTickers(h.cache, h.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (h *P2pb2bApi) FrozenCurrency() ([]string, error) {
@@ -405,11 +369,17 @@ func (h *P2pb2bApi) FrozenCurrency() ([]string, error) {
 }
 
 func (h *P2pb2bApi) Board(trading string, settlement string) (board *models.Board, err error) {
-	c, found := h.boardCache.Get(trading + "_" + settlement)
-	if found {
-		return c.(*models.Board), nil
+	return fetchBoard(h.cache, trading, settlement, func() (*models.Board, error) {
+		return h.fetchBoard(trading, settlement)
+	})
+}
+
+func (h *P2pb2bApi) fetchBoard(trading string, settlement string) (board *models.Board, err error) {
+	symbol, err := pairSymbol(h.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
 	}
-	url := h.publicApiUrl("public/depth/result?market=" + trading + "_" + settlement + "&limit=100")
+	url := h.publicApiUrl("public/depth/result?market=" + symbol + "&limit=100")
 	resp, err := h.HttpClient.Get(url)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to fetch %s", url)
@@ -453,6 +423,5 @@ func (h *P2pb2bApi) Board(trading string, settlement string) (board *models.Boar
 		Asks: asks,
 		Bids: bids,
 	}
-	h.boardCache.Set(trading+"_"+settlement, board, cache.DefaultExpiration)
 	return board, nil
 }
@@ -1,6 +1,7 @@
 package public
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,11 +10,14 @@ import (
 
 	"encoding/json"
 	"github.com/antonholmquist/jason"
-	"github.com/xuyangcn/go-exchange-client/api/unified"
-	"github.com/xuyangcn/go-exchange-client/logger"
-	"github.com/xuyangcn/go-exchange-client/models"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/unified"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/helpers"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
 	"io/ioutil"
 	url2 "net/url"
 )
@@ -22,22 +26,28 @@ const (
 	POLONIEX_BASE_URL = "https://poloniex.com"
 )
 
-func NewPoloniexPublicApi() (*PoloniexApi, error) {
+func NewPoloniexPublicApi(opts ...options.Option) (*PoloniexApi, error) {
+	o, err := options.New(options.Options{
+		Exchange:          "poloniex",
+		BaseURL:           POLONIEX_BASE_URL,
+		RateCacheDuration: 3 * time.Second,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := o.NewHttpClient()
+	cli.Transport = options.NewSwapTransport(cli.Transport)
 	shrimpyApi, err := unified.NewShrimpyApi()
 	if err != nil {
 		return nil, err
 	}
 	api := &PoloniexApi{
-		BaseURL:           POLONIEX_BASE_URL,
-		RateCacheDuration: 3 * time.Second,
-		rateMap:           nil,
-		volumeMap:         nil,
-		orderBookTickMap:  nil,
-		rateLastUpdated:   time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
-		HttpClient:        http.Client{},
-		ShrimpyClient:     shrimpyApi,
+		BaseURL:       o.BaseURL,
+		cache:         o.NewCache(),
+		HttpClient:    *cli,
+		ShrimpyClient: shrimpyApi,
 
-		m: new(sync.Mutex),
+		opts: o,
 	}
 	return api, nil
 }
@@ -53,32 +63,43 @@ func parsePoloCurrencyPair(s string) (string, string, error) {
 }
 
 type PoloniexApi struct {
-	BaseURL           string
-	RateCacheDuration time.Duration
-	volumeMap         map[string]map[string]float64
-	rateMap           map[string]map[string]float64
-	orderBookTickMap  map[string]map[string]models.OrderBookTick
-	precisionMap      map[string]map[string]models.Precisions
-	rateLastUpdated   time.Time
-	HttpClient        http.Client
-	ShrimpyClient     *unified.ShrimpyApiClient
-
-	m *sync.Mutex
+	BaseURL       string
+	precisionMap  map[string]map[string]models.Precisions
+	precisionM    sync.Mutex
+	symbols       symbolIndex
+	cache         *cache.Cache
+	HttpClient    http.Client
+	ShrimpyClient *unified.ShrimpyApiClient
+
+	opts *options.Options
 }
 
 func (p *PoloniexApi) SetTransport(transport http.RoundTripper) error {
-	p.HttpClient.Transport = transport
+	options.SetTransport(&p.HttpClient, p.opts.WrapTransport(transport))
 	return nil
 }
 
+func (p *PoloniexApi) Capabilities() models.Capabilities {
+	return models.Capabilities{
+		Exchange:   "poloniex",
+		Operations: models.Except(models.PublicOperations),
+	}
+}
+
+// Warmup loads the market metadata which is otherwise fetched on first use.
+func (p *PoloniexApi) Warmup(ctx context.Context) error {
+	return helpers.Warmup(ctx, p.fetchPrecision, loadCurrencyPairs(p))
+}
+
 func (p *PoloniexApi) publicApiUrl(command string) string {
 	return p.BaseURL + "/public?command=" + command
 }
 func (p *PoloniexApi) fetchPrecision() error {
+	p.precisionM.Lock()
+	defer p.precisionM.Unlock()
 	if p.precisionMap != nil {
 		return nil
 	}
-	p.precisionMap = make(map[string]map[string]models.Precisions)
 	url := p.publicApiUrl("returnTicker")
 
 	resp, err := p.HttpClient.Get(url)
@@ -92,137 +113,116 @@ func (p *PoloniexApi) fetchPrecision() error {
 		return errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	value := gjson.Parse(string(byteArray))
+	precisionMap := make(map[string]map[string]models.Precisions)
 	for k, v := range value.Map() {
 		settlement, trading, err := parsePoloCurrencyPair(k)
 		if err != nil {
-			logger.Get().Warn("couldn't parse currency pair", err)
+			p.opts.Log().Warnw("couldn't parse currency pair", "pair", k, "error", err)
 			continue
 		}
 		last := v.Get("last").Str
 		volume := v.Get("baseVolume").Str
-		m, ok := p.precisionMap[trading]
+		m, ok := precisionMap[trading]
 		if !ok {
 			m = make(map[string]models.Precisions)
-			p.precisionMap[trading] = m
+			precisionMap[trading] = m
 		}
 		m[settlement] = models.Precisions{
 			PricePrecision:  Precision(last),
 			AmountPrecision: Precision(volume),
 		}
 	}
+	p.precisionMap = precisionMap
 	return nil
 }
 
-func (p *PoloniexApi) fetchRate() error {
-	p.rateMap = make(map[string]map[string]float64)
-	p.volumeMap = make(map[string]map[string]float64)
-	p.orderBookTickMap = make(map[string]map[string]models.OrderBookTick)
+func (p *PoloniexApi) fetchRate() (*tickers, error) {
+	t := newTickers()
 	url := p.publicApiUrl("returnTicker")
 
 	resp, err := p.HttpClient.Get(url)
 	if err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", url)
+		return nil, errors.Wrapf(err, "failed to fetch %s", url)
 	}
 	defer resp.Body.Close()
 	json, err := jason.NewObjectFromReader(resp.Body)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse json")
+		return nil, errors.Wrapf(err, "failed to parse json")
 	}
 
 	rateMap := json.Map()
 	for k, v := range rateMap {
 		settlement, trading, err := parsePoloCurrencyPair(k)
 		if err != nil {
-			logger.Get().Warn("couldn't parse currency pair", err)
+			p.opts.Log().Warnw("couldn't parse currency pair", "pair", k, "error", err)
 			continue
 		}
+		t.setSymbol(k, trading, settlement)
 
 		obj, err := v.Object()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// update rate
 		last, err := obj.GetString("last")
 		if err != nil {
-			return err
+			return nil, err
 		}
 		lastf, err := strconv.ParseFloat(last, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		m, ok := p.rateMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			p.rateMap[trading] = m
-		}
-		m[settlement] = lastf
+		t.setRate(trading, settlement, lastf)
 
 		// update volume
 		volume, err := obj.GetString("baseVolume")
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		volumef, err := strconv.ParseFloat(volume, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		m, ok = p.volumeMap[trading]
-		if !ok {
-			m = make(map[string]float64)
-			p.volumeMap[trading] = m
-		}
-		m[settlement] = volumef
+		t.setVolume(trading, settlement, volumef)
 
 		// update orderBookTick
 		ask, err := obj.GetString("lowestAsk")
 		if err != nil {
-			return err
+			return nil, err
 		}
 		askf, err := strconv.ParseFloat(ask, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		bid, err := obj.GetString("highestBid")
 		if err != nil {
-			return err
+			return nil, err
 		}
 		bidf, err := strconv.ParseFloat(bid, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		n, ok := p.orderBookTickMap[trading]
-		if !ok {
-			n = make(map[string]models.OrderBookTick)
-			p.orderBookTickMap[trading] = n
-		}
-		n[settlement] = models.OrderBookTick{
+		t.setTick(trading, settlement, models.OrderBookTick{
 			BestAskPrice: askf,
 			BestBidPrice: bidf,
-		}
+		})
 	}
-	return nil
+	return t, nil
 }
 
 func (p *PoloniexApi) CurrencyPairs() ([]models.CurrencyPair, error) {
-	p.m.Lock()
-	defer p.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(p.rateLastUpdated) >= p.RateCacheDuration {
-		err := p.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		p.rateLastUpdated = now
+	t, err := fetchTickers(p.cache, p.fetchRate)
+	if err != nil {
+		return nil, err
 	}
 
 	var pairs []models.CurrencyPair
-	for trading, m := range p.rateMap {
+	for trading, m := range t.Rates {
 		for settlement := range m {
 			pair := models.CurrencyPair{
 				Trading:    trading,
@@ -235,33 +235,27 @@ func (p *PoloniexApi) CurrencyPairs() ([]models.CurrencyPair, error) {
 	return pairs, nil
 }
 
-func (p *PoloniexApi) Volume(trading string, settlement string) (float64, error) {
-	p.m.Lock()
-	defer p.m.Unlock()
-
-	now := time.Now()
-	if now.Sub(p.rateLastUpdated) >= p.RateCacheDuration {
-		err := p.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		p.rateLastUpdated = now
-	}
+func (p *PoloniexApi) Symbols() (*symbols.Index, error) {
+	return p.symbols.load(indexTickers(p.opts, p.cache, p.fetchRate))
+}
 
-	if m, ok := p.volumeMap[trading]; !ok {
-		return 0, errors.New("trading volume not found")
-	} else if volume, ok := m[settlement]; !ok {
-		return 0, errors.New("settlement volume not found")
-	} else {
-		return volume, nil
+func (p *PoloniexApi) Volume(trading string, settlement string) (float64, error) {
+	t, err := fetchTickers(p.cache, p.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.volume(trading, settlement)
 }
 
 func (p *PoloniexApi) Precise(trading string, settlement string) (*models.Precisions, error) {
 	if trading == settlement {
 		return &models.Precisions{}, nil
 	}
-	p.fetchPrecision()
+	if err := p.fetchPrecision(); err != nil {
+		return &models.Precisions{}, err
+	}
+	p.precisionM.Lock()
+	defer p.precisionM.Unlock()
 	if m, ok := p.precisionMap[trading]; !ok {
 		return &models.Precisions{}, errors.Errorf("%s/%s", trading, settlement)
 	} else if precisions, ok := m[settlement]; !ok {
@@ -271,10 +265,10 @@ func (p *PoloniexApi) Precise(trading string, settlement string) (*models.Precis
 	}
 }
 
-func (h *PoloniexApi) fetchOrderBookTick() error {
-	boardMap, err := h.ShrimpyClient.GetBoards("poloniex")
+func (h *PoloniexApi) fetchOrderBookTick() (map[string]map[string]models.OrderBookTick, error) {
+	boardMap, err := shrimpyBoards(h.ShrimpyClient, h.opts, "poloniex")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	orderBookTickMap := make(map[string]map[string]models.OrderBookTick)
 	for settlement, m := range boardMap {
@@ -292,75 +286,42 @@ func (h *PoloniexApi) fetchOrderBookTick() error {
 			}
 		}
 	}
-	h.orderBookTickMap = orderBookTickMap
-	return nil
+	return orderBookTickMap, nil
 }
 
 func (h *PoloniexApi) OrderBookTickMap() (map[string]map[string]models.OrderBookTick, error) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	now := time.Now()
-	if now.Sub(h.rateLastUpdated) >= h.RateCacheDuration {
-		err := h.fetchOrderBookTick()
-		if err != nil {
-			return nil, err
-		}
-		h.rateLastUpdated = now
-	}
-	return h.orderBookTickMap, nil
+	return fetchOrderBookTicks(h.cache, h.fetchOrderBookTick)
 }
 
 func (p *PoloniexApi) RateMap() (map[string]map[string]float64, error) {
-	p.m.Lock()
-	defer p.m.Unlock()
-	now := time.Now()
-	if now.Sub(p.rateLastUpdated) >= p.RateCacheDuration {
-		err := p.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		p.rateLastUpdated = now
+	t, err := fetchTickers(p.cache, p.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return p.rateMap, nil
+	return copyRates(t.Rates), nil
 }
 
 func (p *PoloniexApi) VolumeMap() (map[string]map[string]float64, error) {
-	p.m.Lock()
-	defer p.m.Unlock()
-	now := time.Now()
-	if now.Sub(p.rateLastUpdated) >= p.RateCacheDuration {
-		err := p.fetchRate()
-		if err != nil {
-			return nil, err
-		}
-		p.rateLastUpdated = now
+	t, err := fetchTickers(p.cache, p.fetchRate)
+	if err != nil {
+		return nil, err
 	}
-	return p.volumeMap, nil
+	return copyRates(t.Volumes), nil
 }
 
-func (p *PoloniexApi) Rate(trading string, settlement string) (float64, error) {
-	p.m.Lock()
-	defer p.m.Unlock()
+func (p *PoloniexApi) Snapshot() (*models.MarketSnapshot, error) {
+	return fetchSnapshot(p.cache, p.fetchRate)
+}
 
+func (p *PoloniexApi) Rate(trading string, settlement string) (float64, error) {
 	if trading == settlement {
 		return 1, nil
 	}
-
-	now := time.Now()
-	if now.Sub(p.rateLastUpdated) >= p.RateCacheDuration {
-		err := p.fetchRate()
-		if err != nil {
-			return 0, err
-		}
-		p.rateLastUpdated = now
-	}
-	if m, ok := p.rateMap[trading]; !ok {
-		return 0, errors.New("trading rate not found")
-	} else if rate, ok := m[settlement]; !ok {
-		return 0, errors.New("settlement rate not found")
-	} else {
-		return rate, nil
+	t, err := fetchTickers(p.cache, p.fetchRate)
+	if err != nil {
+		return 0, err
 	}
+	return t.rate(trading, settlement)
 }
 
 func (p *PoloniexApi) FrozenCurrency() ([]string, error) {
@@ -396,8 +357,18 @@ type Currency struct {
 }
 
 func (p *PoloniexApi) Board(trading string, settlement string) (*models.Board, error) {
+	return fetchBoard(p.cache, trading, settlement, func() (*models.Board, error) {
+		return p.fetchBoard(trading, settlement)
+	})
+}
+
+func (p *PoloniexApi) fetchBoard(trading string, settlement string) (*models.Board, error) {
 	args := url2.Values{}
-	args.Add("currencyPair", settlement+"_"+trading)
+	symbol, err := pairSymbol(p.Symbols, trading, settlement)
+	if err != nil {
+		return nil, err
+	}
+	args.Add("currencyPair", symbol)
 	url := p.publicApiUrl("returnOrderBook") + "&" + args.Encode()
 	resp, err := p.HttpClient.Get(url)
 	if err != nil {
@@ -2,19 +2,22 @@ package public
 
 import (
 	"github.com/xuyangcn/go-exchange-client/logger"
+	"github.com/xuyangcn/go-exchange-client/proxy"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sync"
 	"time"
 )
 
-func RandomProxyUrl(proxyUrlListGroup ProxyUrlList) func(*http.Request) (*url.URL, error) {
-	proxyUrlList, _ := proxyUrlListGroup.GetList()
-	rand.Seed(time.Now().UnixNano())
-
+// RandomProxyUrl picks a proxy of the list for every request.
+//
+// Deprecated: proxy.Pool leaves out proxies which are down or banned.
+func RandomProxyUrl(proxyUrlListGroup *ProxyUrlList) func(*http.Request) (*url.URL, error) {
 	return func(*http.Request) (*url.URL, error) {
+		proxyUrlList, _ := proxyUrlListGroup.GetList()
 		if len(proxyUrlList) == 0 {
 			logger.Get().Info("there is no proxy")
 			return nil, nil
@@ -24,8 +27,8 @@ func RandomProxyUrl(proxyUrlListGroup ProxyUrlList) func(*http.Request) (*url.UR
 	}
 }
 
-func NewProxyUrlList(baseUrl string) ProxyUrlList {
-	pul := ProxyUrlList{BASE_URL: baseUrl, listLastUpdated: time.Now(), ListCacheDuration: time.Minute * 5}
+func NewProxyUrlList(baseUrl string) *ProxyUrlList {
+	pul := &ProxyUrlList{BASE_URL: baseUrl, listLastUpdated: time.Now(), ListCacheDuration: time.Minute * 5}
 	pul.urlList = make([]*url.URL, 0)
 	pul.fetchList()
 	return pul
@@ -37,9 +40,12 @@ type ProxyUrlList struct {
 	listLastUpdated   time.Time
 	ListCacheDuration time.Duration
 	urlList           []*url.URL
+	m                 sync.Mutex
 }
 
 func (pul *ProxyUrlList) GetList() ([]*url.URL, error) {
+	pul.m.Lock()
+	defer pul.m.Unlock()
 	now := time.Now()
 	if now.Sub(pul.listLastUpdated) >= pul.ListCacheDuration {
 		logger.Get().Info("update proxies")
@@ -67,7 +73,7 @@ func (pul *ProxyUrlList) fetchList() error {
 		if v == "" {
 			continue
 		}
-		url, err := url.Parse("http://" + v)
+		url, err := proxy.ParseURL(v)
 		if err != nil {
 			continue
 		}
@@ -0,0 +1,105 @@
+package public
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+var ErrUnknownExchange = errors.New("unknown exchange")
+
+// Factory builds a PublicClient for one exchange.
+type Factory func(opts ...options.Option) (PublicClient, error)
+
+var (
+	factories  = make(map[string]Factory)
+	aliases    = make(map[string]string)
+	registryMu sync.RWMutex
+)
+
+// Register makes an exchange available to NewClient under name and its aliases.
+// Names are case insensitive.
+func Register(name string, factory Factory, alias ...string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	name = strings.ToLower(name)
+	if name == "" || factory == nil {
+		return errors.New("exchange name and factory are required")
+	}
+	if _, ok := factories[name]; ok {
+		return errors.Errorf("exchange %s is already registered", name)
+	}
+	if _, ok := aliases[name]; ok {
+		return errors.Errorf("exchange %s is already registered as an alias", name)
+	}
+	for _, a := range alias {
+		a = strings.ToLower(a)
+		if _, ok := factories[a]; ok {
+			return errors.Errorf("alias %s is already registered as an exchange", a)
+		}
+		if _, ok := aliases[a]; ok {
+			return errors.Errorf("alias %s is already registered", a)
+		}
+	}
+	factories[name] = factory
+	for _, a := range alias {
+		aliases[strings.ToLower(a)] = name
+	}
+	return nil
+}
+
+func mustRegister(name string, factory Factory, alias ...string) {
+	if err := Register(name, factory, alias...); err != nil {
+		panic(err)
+	}
+}
+
+// Resolve returns the registered name of an exchange name or alias.
+func Resolve(exchangeName string) (string, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	name := strings.ToLower(exchangeName)
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+	if _, ok := factories[name]; !ok {
+		return "", errors.Wrapf(ErrUnknownExchange, "%s", exchangeName)
+	}
+	return name, nil
+}
+
+// Exchanges returns the registered exchange names in alphabetical order.
+func Exchanges() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Aliases returns a map from alias to registered exchange name.
+func Aliases() map[string]string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	m := make(map[string]string, len(aliases))
+	for a, name := range aliases {
+		m[a] = name
+	}
+	return m
+}
+
+func lookup(exchangeName string) (string, Factory, error) {
+	name, err := Resolve(exchangeName)
+	if err != nil {
+		return "", nil, err
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return name, factories[name], nil
+}
@@ -0,0 +1,80 @@
+package public
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/cache"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
+)
+
+// symbolIndex is the symbol index of a client, built on first use.
+type symbolIndex struct {
+	mu sync.Mutex
+	ix *symbols.Index
+}
+
+func (s *symbolIndex) load(build func() (*symbols.Index, error)) (*symbols.Index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ix != nil {
+		return s.ix, nil
+	}
+	ix, err := build()
+	if err != nil {
+		return nil, err
+	}
+	s.ix = ix
+	return ix, nil
+}
+
+func (s *symbolIndex) loaded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ix != nil
+}
+
+// addSymbol lists pair under the native symbol of the exchange. A symbol
+// which conflicts with a listed one is left out and logged.
+func addSymbol(o *options.Options, ix *symbols.Index, symbol string, pair models.CurrencyPair) {
+	if err := ix.Add(symbol, pair); err != nil {
+		o.Log().Warnw("skipped symbol", "symbol", symbol, "error", err)
+	}
+}
+
+// indexTickers builds the index of the native symbols the tickers of fetch
+// are listed by, for exchanges without a symbol list.
+func indexTickers(o *options.Options, c *cache.Cache, fetch func() (*tickers, error)) func() (*symbols.Index, error) {
+	return func() (*symbols.Index, error) {
+		t, err := fetchTickers(c, fetch)
+		if err != nil {
+			return nil, err
+		}
+		if len(t.Symbols) == 0 {
+			return nil, errors.New("no symbols in the tickers")
+		}
+		// sorted so that conflicts are resolved the same way every time
+		names := make([]string, 0, len(t.Symbols))
+		for s := range t.Symbols {
+			names = append(names, s)
+		}
+		sort.Strings(names)
+		ix := symbols.New()
+		for _, s := range names {
+			addSymbol(o, ix, s, t.Symbols[s])
+		}
+		return ix, nil
+	}
+}
+
+// pairSymbol returns the native symbol of a pair from the index of load.
+func pairSymbol(load func() (*symbols.Index, error), trading, settlement string) (string, error) {
+	ix, err := load()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get symbols")
+	}
+	return ix.Symbol(trading, settlement)
+}
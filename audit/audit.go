@@ -0,0 +1,243 @@
+// Package audit records the requests of private clients and the responses
+// to them, so what happened to an order can be replayed later.
+//
+//	sink, err := audit.NewFileSink("audit.jsonl", 100<<20, 10)
+//	cli, err := private.NewClient(private.PROJECT, "binance", apikey, seckey, audit.New(sink).Option())
+//
+// API keys, signatures and passphrases are redacted from headers, parameters
+// and JSON bodies before a record reaches the sink.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+// Redacted replaces the values of sensitive fields.
+const Redacted = "[REDACTED]"
+
+// Record is one request and the response to it.
+type Record struct {
+	Time           time.Time     `json:"time"`
+	Exchange       string        `json:"exchange"`
+	Method         string        `json:"method"`
+	Path           string        `json:"path"`
+	Params         url.Values    `json:"params,omitempty"`
+	Header         http.Header   `json:"header,omitempty"`
+	RequestBody    string        `json:"request_body,omitempty"`
+	Status         int           `json:"status,omitempty"`
+	Body           string        `json:"body,omitempty"`
+	Truncated      bool          `json:"truncated,omitempty"`
+	Latency        time.Duration `json:"latency_ns"`
+	Error          string        `json:"error,omitempty"`
+	OrderAffecting bool          `json:"order_affecting"`
+}
+
+// Sink stores records. Write is called from the goroutine sending the request.
+type Sink interface {
+	Write(r *Record) error
+}
+
+// Recorder sends the records of private requests to a sink.
+type Recorder struct {
+	sink Sink
+
+	// MaxBody is the number of bytes of each body which are recorded.
+	MaxBody int
+	// OrderAffecting tells whether a request places, cancels or withdraws.
+	OrderAffecting func(req *http.Request, params url.Values) bool
+}
+
+// New returns a Recorder writing to sink.
+func New(sink Sink) *Recorder {
+	return &Recorder{sink: sink, MaxBody: 64 << 10, OrderAffecting: OrderAffecting}
+}
+
+// Option records the private requests of a client built with it. Requests of
+// public clients, including the one a private client is built on, are skipped.
+func (a *Recorder) Option() options.Option {
+	return options.WithMiddleware(a.Middleware)
+}
+
+// Middleware is an options.Middleware recording the requests sent through next.
+func (a *Recorder) Middleware(o *options.Options, next http.RoundTripper) http.RoundTripper {
+	if !o.Private {
+		return next
+	}
+	return &transport{a: a, o: o, next: next}
+}
+
+type transport struct {
+	a    *Recorder
+	o    *options.Options
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := &Record{
+		Time:     time.Now(),
+		Exchange: t.o.Exchange,
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Params:   req.URL.Query(),
+		Header:   redactHeader(req.Header),
+	}
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		form, _ := url.ParseQuery(string(body))
+		for k, vs := range form {
+			r.Params[k] = append(r.Params[k], vs...)
+		}
+	} else {
+		r.RequestBody, r.Truncated = t.truncate(redactBody(body))
+	}
+	r.OrderAffecting = t.a.OrderAffecting(req, r.Params)
+	r.Params = redactParams(r.Params)
+
+	res, err := t.next.RoundTrip(req)
+	if err == nil {
+		var resBody []byte
+		resBody, err = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+		var truncated bool
+		r.Status = res.StatusCode
+		r.Body, truncated = t.truncate(redactBody(resBody))
+		r.Truncated = r.Truncated || truncated
+	}
+	r.Latency = time.Since(r.Time)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if werr := t.a.sink.Write(r); werr != nil {
+		t.o.Log().Warnf("failed to write audit record: %v", werr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (t *transport) truncate(body []byte) (string, bool) {
+	if t.a.MaxBody > 0 && len(body) > t.a.MaxBody {
+		return string(body[:t.a.MaxBody]), true
+	}
+	return string(body), false
+}
+
+// requestBody returns the body of req, leaving req readable.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+var orderAffecting = regexp.MustCompile(`(?i)order|cancel|withdraw|transfer|buy|sell`)
+
+// OrderAffecting is the default of Recorder.OrderAffecting. It flags requests
+// other than GET whose path or command parameter looks like trading or a
+// withdrawal.
+func OrderAffecting(req *http.Request, params url.Values) bool {
+	if req.Method == http.MethodGet {
+		return false
+	}
+	return orderAffecting.MatchString(req.URL.Path) ||
+		orderAffecting.MatchString(params.Get("command")) ||
+		orderAffecting.MatchString(params.Get("method"))
+}
+
+var sensitive = regexp.MustCompile(`(?i)key|sign|secret|pass|auth|token`)
+
+// Sensitive tells whether a header, parameter or JSON field is redacted.
+func Sensitive(name string) bool {
+	return sensitive.MatchString(name)
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, vs := range h {
+		if Sensitive(k) {
+			vs = []string{Redacted}
+		}
+		out[k] = vs
+	}
+	return out
+}
+
+func redactParams(params url.Values) url.Values {
+	if len(params) == 0 {
+		return nil
+	}
+	for k := range params {
+		if Sensitive(k) {
+			params[k] = []string{Redacted}
+		}
+	}
+	return params
+}
+
+// redactBody redacts the sensitive fields of a JSON body. Other bodies are
+// returned as they are.
+func redactBody(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !redactValue(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue redacts v in place and tells whether anything was redacted.
+func redactValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if Sensitive(k) {
+				v[k] = Redacted
+				changed = true
+				continue
+			}
+			changed = redactValue(field) || changed
+		}
+	case []interface{}:
+		for _, e := range v {
+			changed = redactValue(e) || changed
+		}
+	}
+	return changed
+}
@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+type memorySink []*Record
+
+func (s *memorySink) Write(r *Record) error {
+	*s = append(*s, r)
+	return nil
+}
+
+func newFakeExchange() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orderId":42,"apiKey":"leaked","fills":[{"price":"0.03"}]}`))
+	}))
+}
+
+func newTestClient(t *testing.T, sink Sink, private bool) *http.Client {
+	o, err := options.New(options.Options{Exchange: "binance", Private: private}, New(sink).Option())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o.NewHttpClient()
+}
+
+func TestRecorder(t *testing.T) {
+	fake := newFakeExchange()
+	defer fake.Close()
+	var sink memorySink
+	cli := newTestClient(t, &sink, true)
+
+	req, _ := http.NewRequest(http.MethodPost, fake.URL+"/api/v3/order?timestamp=1&signature=abcdef",
+		strings.NewReader(url.Values{"symbol": {"ETHBTC"}, "side": {"BUY"}, "secret": {"s"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-MBX-APIKEY", "my-key")
+	res, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if !strings.Contains(string(body), "leaked") {
+		t.Error("the response body must reach the client untouched")
+	}
+
+	if len(sink) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(sink))
+	}
+	r := sink[0]
+	if r.Exchange != "binance" || r.Method != http.MethodPost || r.Path != "/api/v3/order" || r.Status != http.StatusOK || !r.OrderAffecting {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if r.Params.Get("signature") != Redacted || r.Params.Get("secret") != Redacted || r.Params.Get("symbol") != "ETHBTC" {
+		t.Errorf("unexpected params %v", r.Params)
+	}
+	if r.Header.Get("X-MBX-APIKEY") != Redacted {
+		t.Errorf("api key is not redacted: %v", r.Header)
+	}
+	if strings.Contains(r.Body, "leaked") || !strings.Contains(r.Body, `"orderId":42`) {
+		t.Errorf("unexpected body %s", r.Body)
+	}
+	line, _ := json.Marshal(r)
+	for _, secret := range []string{"my-key", "abcdef", "leaked"} {
+		if strings.Contains(string(line), secret) {
+			t.Errorf("%s is in the record %s", secret, line)
+		}
+	}
+}
+
+func TestRecorderSkipsPublic(t *testing.T) {
+	fake := newFakeExchange()
+	defer fake.Close()
+	var sink memorySink
+	cli := newTestClient(t, &sink, false)
+	res, err := cli.Get(fake.URL + "/api/v1/depth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if len(sink) != 0 {
+		t.Errorf("expected no records, got %d", len(sink))
+	}
+}
+
+func TestOrderAffecting(t *testing.T) {
+	for _, c := range []struct {
+		method, path string
+		params       url.Values
+		want         bool
+	}{
+		{http.MethodPost, "/api/v3/order", nil, true},
+		{http.MethodDelete, "/api/v1/orders/5bd6e9286d99522a52e458de", nil, true},
+		{http.MethodGet, "/api/v3/openOrders", nil, false},
+		{http.MethodPost, "/tradingApi", url.Values{"command": {"cancelOrder"}}, true},
+		{http.MethodPost, "/tradingApi", url.Values{"command": {"returnCompleteBalances"}}, false},
+		{http.MethodPost, "/wapi/v3/withdraw.html", nil, true},
+	} {
+		req, _ := http.NewRequest(c.method, "https://example.com"+c.path, nil)
+		if got := OrderAffecting(req, c.params); got != c.want {
+			t.Errorf("%s %s %v: expected %v", c.method, c.path, c.params, c.want)
+		}
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.jsonl")
+
+	s, err := NewFileSink(path, 300, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := s.Write(&Record{Exchange: "binance", Method: http.MethodPost, Path: "/api/v3/order", Body: strings.Repeat("x", 100)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&Record{}); err == nil {
+		t.Error("expected an error after Close")
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc := bufio.NewScanner(f)
+		lines := 0
+		for sc.Scan() {
+			var r Record
+			if err := json.Unmarshal(sc.Bytes(), &r); err != nil || r.Exchange != "binance" {
+				t.Errorf("%s: unexpected line %s", name, sc.Text())
+			}
+			lines++
+		}
+		f.Close()
+		if lines == 0 {
+			t.Errorf("%s is empty", name)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileSink writes records as JSON lines. When the file grows over MaxSize it
+// is renamed to path.1, the older ones shifted to path.2 and so on, keeping
+// MaxBackups of them.
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// NewFileSink opens path for appending. A maxSize of 0 never rotates.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	s := &FileSink{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", s.path)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to stat %s", s.path)
+	}
+	s.f, s.size = f, fi.Size()
+	return nil
+}
+
+func (s *FileSink) Write(r *Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode audit record")
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return errors.Errorf("%s is closed", s.path)
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.f.Write(line)
+	s.size += int64(n)
+	return errors.Wrapf(err, "failed to write %s", s.path)
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close %s", s.path)
+	}
+	s.f = nil
+	if s.maxBackups > 0 {
+		os.Remove(backup(s.path, s.maxBackups))
+		for i := s.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(backup(s.path, i), backup(s.path, i+1)); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "failed to rotate %s", s.path)
+			}
+		}
+		if err := os.Rename(s.path, backup(s.path, 1)); err != nil {
+			return errors.Wrapf(err, "failed to rotate %s", s.path)
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return errors.Wrapf(err, "failed to rotate %s", s.path)
+	}
+	return s.open()
+}
+
+func backup(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// Close closes the file. Later writes fail.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return errors.Wrapf(err, "failed to close %s", s.path)
+}
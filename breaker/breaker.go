@@ -0,0 +1,313 @@
+// Package breaker stops sending requests to an exchange which keeps failing
+// or banned the client, so callers fail fast instead of piling up.
+//
+//	b := breaker.New()
+//	cli, err := public.NewClient("binance", b.Option())
+//	if b.Healthy("binance") { ... }
+//
+// There is a circuit per exchange and endpoint class. It opens after
+// Threshold failures in a row, or at once on a ban, and requests fail with
+// an *OpenError until Cooldown passes. A single probe is then let through:
+// the circuit closes if it succeeds and opens again if it fails.
+package breaker
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/proxy"
+)
+
+// State is the state of a circuit.
+type State int
+
+const (
+	Closed State = iota
+	Open
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// ErrOpen matches every *OpenError with errors.Is.
+var ErrOpen = errors.New("circuit open")
+
+// OpenError is returned without sending the request while a circuit is open.
+type OpenError struct {
+	Exchange string
+	Class    string
+	// Until is when a probe is let through.
+	Until time.Time
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s %s until %s", e.Exchange, e.Class, e.Until.Format(time.RFC3339))
+}
+
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Permanent tells the retries of options.WithRetry to give up at once.
+func (e *OpenError) Permanent() bool {
+	return true
+}
+
+// Breaker holds the circuits of every client it is applied to.
+type Breaker struct {
+	// Threshold is the number of failures in a row which opens a circuit.
+	Threshold int
+	// Cooldown is how long a circuit stays open before a probe. A Retry-After
+	// header of a ban takes precedence when it is longer.
+	Cooldown time.Duration
+	// Class turns a request into the endpoint class of its circuit.
+	Class func(o *options.Options, req *http.Request) string
+	// Failed tells whether a response counts as a failure. Network errors
+	// always do.
+	Failed func(res *http.Response) bool
+	// Banned tells whether a response is a ban, which opens the circuit at
+	// once. body is only read for error statuses.
+	Banned func(res *http.Response, body []byte) bool
+
+	mu       sync.Mutex
+	circuits map[key]*circuit
+}
+
+type key struct {
+	exchange string
+	class    string
+}
+
+type circuit struct {
+	state    State
+	failures int
+	until    time.Time
+	probing  bool
+	lastErr  string
+}
+
+// Status is the state of one circuit.
+type Status struct {
+	Exchange string
+	Class    string
+	State    State
+	// Failures are the failures in a row.
+	Failures int
+	// Until is when an open circuit lets a probe through.
+	Until time.Time
+	// LastError describes the last failure.
+	LastError string
+}
+
+// New returns a breaker opening after 5 failures in a row for 30 seconds.
+func New() *Breaker {
+	return &Breaker{
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+		Class:     Scope,
+		Failed:    ServerError,
+		Banned:    proxy.Banned,
+		circuits:  make(map[key]*circuit),
+	}
+}
+
+// Scope is the default of Breaker.Class: "public" or "private", so a ban
+// of the signed endpoints doesn't stop market data.
+func Scope(o *options.Options, req *http.Request) string {
+	if o.Private {
+		return "private"
+	}
+	return "public"
+}
+
+// ServerError is the default of Breaker.Failed: 5xx responses.
+func ServerError(res *http.Response) bool {
+	return res.StatusCode >= http.StatusInternalServerError
+}
+
+// Option applies b to a client.
+func (b *Breaker) Option() options.Option {
+	return options.WithMiddleware(b.Middleware)
+}
+
+// Middleware is an options.Middleware failing fast while the circuit of a
+// request is open.
+func (b *Breaker) Middleware(o *options.Options, next http.RoundTripper) http.RoundTripper {
+	return &transport{b: b, o: o, next: next}
+}
+
+type transport struct {
+	b    *Breaker
+	o    *options.Options
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	k := key{exchange: t.o.Exchange, class: t.b.Class(t.o, req)}
+	if err := t.b.allow(k); err != nil {
+		return nil, err
+	}
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.b.report(k, nil, false, err.Error())
+		return nil, err
+	}
+	var body []byte
+	if res.StatusCode >= 400 {
+		body, err = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.b.report(k, nil, false, err.Error())
+			return nil, err
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	switch {
+	case t.b.Banned(res, body):
+		t.b.report(k, res, true, "banned: "+res.Status)
+	case t.b.Failed(res):
+		t.b.report(k, res, false, res.Status)
+	default:
+		t.b.report(k, res, false, "")
+	}
+	return res, nil
+}
+
+func (b *Breaker) circuit(k key) *circuit {
+	c, ok := b.circuits[k]
+	if !ok {
+		c = &circuit{}
+		b.circuits[k] = c
+	}
+	return c
+}
+
+func (b *Breaker) allow(k key) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(k)
+	switch c.state {
+	case Open:
+		if time.Now().Before(c.until) {
+			return &OpenError{Exchange: k.exchange, Class: k.class, Until: c.until}
+		}
+		c.state = HalfOpen
+	case Closed:
+		return nil
+	}
+	if c.probing {
+		return &OpenError{Exchange: k.exchange, Class: k.class, Until: c.until}
+	}
+	c.probing = true
+	return nil
+}
+
+// report records the outcome of a request; failure is "" for successes.
+func (b *Breaker) report(k key, res *http.Response, banned bool, failure string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(k)
+	c.probing = false
+	if failure == "" {
+		c.state = Closed
+		c.failures = 0
+		c.until = time.Time{}
+		return
+	}
+	c.failures++
+	c.lastErr = failure
+	if !banned && c.state == Closed && c.failures < b.Threshold {
+		return
+	}
+	d := b.Cooldown
+	if banned {
+		if ra := retryAfter(res); ra > d {
+			d = ra
+		}
+	}
+	c.state = Open
+	c.until = time.Now().Add(d)
+}
+
+func retryAfter(res *http.Response) time.Duration {
+	if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return 0
+}
+
+// State returns the state of the circuit of the exchange and class.
+// A circuit whose cooldown passed is half-open.
+func (b *Breaker) State(exchange, class string) State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key{exchange: exchange, class: class}]
+	if !ok {
+		return Closed
+	}
+	return c.current(time.Now())
+}
+
+func (c *circuit) current(now time.Time) State {
+	if c.state == Open && !now.Before(c.until) {
+		return HalfOpen
+	}
+	return c.state
+}
+
+// Healthy tells whether no circuit of the exchange is open, so routing code
+// can skip the exchange.
+func (b *Breaker) Healthy(exchange string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for k, c := range b.circuits {
+		if k.exchange == exchange && c.current(now) == Open {
+			return false
+		}
+	}
+	return true
+}
+
+// Statuses returns every circuit, sorted by exchange and class.
+func (b *Breaker) Statuses() []Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	statuses := make([]Status, 0, len(b.circuits))
+	for k, c := range b.circuits {
+		statuses = append(statuses, Status{
+			Exchange:  k.exchange,
+			Class:     k.class,
+			State:     c.current(now),
+			Failures:  c.failures,
+			Until:     c.until,
+			LastError: c.lastErr,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Exchange != statuses[j].Exchange {
+			return statuses[i].Exchange < statuses[j].Exchange
+		}
+		return statuses[i].Class < statuses[j].Class
+	})
+	return statuses
+}
@@ -0,0 +1,149 @@
+package breaker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+// fakeExchange answers with the given statuses in turn and counts requests.
+type fakeExchange struct {
+	statuses []int
+	body     string
+	calls    int
+}
+
+func (f *fakeExchange) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := f.statuses[f.calls%len(f.statuses)]
+	f.calls++
+	if status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+func newClient(t *testing.T, b *Breaker, rt http.RoundTripper, opts ...options.Option) *http.Client {
+	opts = append([]options.Option{options.WithHTTPClient(&http.Client{Transport: rt}), b.Option()}, opts...)
+	o, err := options.New(options.Options{Exchange: "binance"}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o.NewHttpClient()
+}
+
+func get(cli *http.Client) error {
+	res, err := cli.Get("http://exchange.invalid/api/v1/depth")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func TestThreshold(t *testing.T) {
+	rt := &fakeExchange{statuses: []int{http.StatusBadGateway, 0, http.StatusServiceUnavailable, http.StatusOK}}
+	b := New()
+	b.Threshold = 3
+	b.Cooldown = 50 * time.Millisecond
+	cli := newClient(t, b, rt)
+
+	for i := 0; i < 3; i++ {
+		get(cli)
+	}
+	if s := b.State("binance", "public"); s != Open {
+		t.Fatalf("expected the circuit to open after 3 failures, got %v", s)
+	}
+	if b.Healthy("binance") || !b.Healthy("huobi") {
+		t.Error("unexpected health")
+	}
+	err := get(cli)
+	var oerr *OpenError
+	if !errors.As(err, &oerr) || !errors.Is(err, ErrOpen) || oerr.Class != "public" {
+		t.Errorf("expected an OpenError, got %v", err)
+	}
+	if rt.calls != 3 {
+		t.Errorf("expected no request while open, got %d", rt.calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if s := b.State("binance", "public"); s != HalfOpen {
+		t.Errorf("expected half-open after the cooldown, got %v", s)
+	}
+	if err := get(cli); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.State("binance", "public"); s != Closed {
+		t.Errorf("expected the probe to close the circuit, got %v", s)
+	}
+}
+
+func TestProbeFailure(t *testing.T) {
+	rt := &fakeExchange{statuses: []int{http.StatusInternalServerError}}
+	b := New()
+	b.Threshold = 1
+	b.Cooldown = 20 * time.Millisecond
+	cli := newClient(t, b, rt)
+
+	get(cli)
+	time.Sleep(30 * time.Millisecond)
+	get(cli)
+	if s := b.State("binance", "public"); s != Open || rt.calls != 2 {
+		t.Errorf("expected a failed probe to open the circuit again, got %v after %d calls", s, rt.calls)
+	}
+}
+
+func TestBan(t *testing.T) {
+	rt := &fakeExchange{statuses: []int{http.StatusTeapot}, body: `{"code":-1003,"msg":"Way too many requests; IP banned"}`}
+	b := New()
+	cli := newClient(t, b, rt)
+
+	get(cli)
+	statuses := b.Statuses()
+	if len(statuses) != 1 || statuses[0].State != Open || !strings.Contains(statuses[0].LastError, "banned") {
+		t.Errorf("expected a ban to open the circuit at once, got %+v", statuses)
+	}
+	if time.Until(statuses[0].Until) < 29*time.Second {
+		t.Errorf("expected the cooldown, got %v", statuses[0].Until)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	rt := &fakeExchange{statuses: []int{http.StatusBadRequest}}
+	b := New()
+	b.Threshold = 1
+	cli := newClient(t, b, rt)
+	for i := 0; i < 3; i++ {
+		get(cli)
+	}
+	if s := b.State("binance", "public"); s != Closed || rt.calls != 3 {
+		t.Errorf("expected client errors to keep the circuit closed, got %v", s)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	rt := &fakeExchange{statuses: []int{http.StatusBadGateway}}
+	b := New()
+	b.Threshold = 2
+	cli := newClient(t, b, rt, options.WithRetry(5, time.Millisecond))
+
+	start := time.Now()
+	get(cli)
+	if rt.calls != 2 {
+		t.Errorf("expected the retries to stop once the circuit opened, got %d calls", rt.calls)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected the open circuit to fail fast")
+	}
+}
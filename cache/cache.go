@@ -0,0 +1,121 @@
+// Package cache holds the market data of public clients.
+//
+// Every endpoint has its own TTL. Callers asking for an expired entry at the
+// same time share a single fetch, and with StaleWhileRevalidate an expired
+// entry keeps being served while it is refreshed in the background.
+// Entries live in a Store: in memory by default, or in a shared store such
+// as Redis through NewRemoteStore.
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/logger"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// Entry is a cached value and when it was fetched.
+type Entry struct {
+	Value   interface{}
+	Fetched time.Time
+}
+
+// Store keeps entries until ttl passes. Get returns nil for missing entries.
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, error)
+	Set(ctx context.Context, key string, e *Entry, ttl time.Duration) error
+}
+
+// Cache is the cache of one client.
+type Cache struct {
+	// Namespace prefixes the keys so clients can share a store.
+	Namespace string
+	// TTL is how long the entries of each endpoint are fresh. Endpoints which
+	// are not listed are never cached.
+	TTL map[string]time.Duration
+	// StaleWhileRevalidate is how long an expired entry is still returned
+	// while a fetch refreshes it in the background.
+	StaleWhileRevalidate time.Duration
+	// Observe is told whether each lookup was served from the cache.
+	Observe func(endpoint string, hit bool)
+	// Logger is told about store failures, the package default logger if nil.
+	Logger *zap.SugaredLogger
+
+	store Store
+	group singleflight.Group
+}
+
+// New returns a cache of store, or of a new memory store if nil.
+func New(store Store) *Cache {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Cache{TTL: make(map[string]time.Duration), store: store}
+}
+
+// Fetch returns the value of key for the endpoint, calling fetch when the
+// cache has no fresh one.
+func (c *Cache) Fetch(endpoint, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	ttl := c.TTL[endpoint]
+	if ttl <= 0 {
+		c.observe(endpoint, false)
+		return c.fetch(endpoint, key, 0, fetch)
+	}
+	ctx := context.Background()
+	e, err := c.store.Get(ctx, c.key(endpoint, key))
+	if err != nil {
+		c.log().Warnw("failed to read cache", "endpoint", endpoint, "error", err)
+		e = nil
+	}
+	if e != nil {
+		age := time.Since(e.Fetched)
+		if age < ttl {
+			c.observe(endpoint, true)
+			return e.Value, nil
+		}
+		if age < ttl+c.StaleWhileRevalidate {
+			c.observe(endpoint, true)
+			go c.fetch(endpoint, key, ttl, fetch)
+			return e.Value, nil
+		}
+	}
+	c.observe(endpoint, false)
+	return c.fetch(endpoint, key, ttl, fetch)
+}
+
+// fetch calls fetch once for all concurrent callers of the same key and
+// stores the value unless ttl is 0.
+func (c *Cache) fetch(endpoint, key string, ttl time.Duration, fetch func() (interface{}, error)) (interface{}, error) {
+	k := c.key(endpoint, key)
+	v, err, _ := c.group.Do(k, func() (interface{}, error) {
+		v, err := fetch()
+		if err != nil || ttl <= 0 {
+			return v, err
+		}
+		e := &Entry{Value: v, Fetched: time.Now()}
+		if err := c.store.Set(context.Background(), k, e, ttl+c.StaleWhileRevalidate); err != nil {
+			c.log().Warnw("failed to write cache", "endpoint", endpoint, "error", err)
+		}
+		return v, nil
+	})
+	return v, err
+}
+
+func (c *Cache) key(endpoint, key string) string {
+	return c.Namespace + ":" + endpoint + ":" + key
+}
+
+func (c *Cache) log() *zap.SugaredLogger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return logger.Get()
+}
+
+func (c *Cache) observe(endpoint string, hit bool) {
+	if c.Observe != nil {
+		c.Observe(endpoint, hit)
+	}
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+func TestTTL(t *testing.T) {
+	c := New(nil)
+	c.TTL["rate"] = 50 * time.Millisecond
+	var calls int
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	for i := 0; i < 3; i++ {
+		v, err := c.Fetch("rate", "", fetch)
+		if err != nil || v.(int) != 1 {
+			t.Fatalf("got %v, %v; want the first fetch", v, err)
+		}
+	}
+	time.Sleep(60 * time.Millisecond)
+	if v, _ := c.Fetch("rate", "", fetch); v.(int) != 2 {
+		t.Errorf("got %v after the TTL; want a new fetch", v)
+	}
+	if v, _ := c.Fetch("rate", "BTC_JPY", fetch); v.(int) != 3 {
+		t.Errorf("got %v for another key; want a new fetch", v)
+	}
+
+	// endpoints without a TTL are never cached
+	c.Fetch("board", "", fetch)
+	if v, _ := c.Fetch("board", "", fetch); v.(int) != 5 {
+		t.Errorf("got %v; want board to be fetched every time", v)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	c := New(nil)
+	c.TTL["rate"] = time.Minute
+	_, err := c.Fetch("rate", "", func() (interface{}, error) {
+		return nil, errors.New("timeout")
+	})
+	if err == nil {
+		t.Fatal("want the error of fetch")
+	}
+	v, err := c.Fetch("rate", "", func() (interface{}, error) {
+		return 1, nil
+	})
+	if err != nil || v.(int) != 1 {
+		t.Errorf("got %v, %v; want a new fetch after an error", v, err)
+	}
+}
+
+func TestSingleflight(t *testing.T) {
+	c := New(nil)
+	c.TTL["rate"] = time.Minute
+	var calls int32
+	release := make(chan struct{})
+	fetch := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "tickers", nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.Fetch("rate", "", fetch); err != nil || v != "tickers" {
+				t.Errorf("got %v, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("fetched %d times; want concurrent callers to share one fetch", calls)
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	c := New(nil)
+	c.TTL["rate"] = 20 * time.Millisecond
+	c.StaleWhileRevalidate = time.Minute
+	var hits, misses int
+	c.Observe = func(endpoint string, hit bool) {
+		if hit {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	var calls int32
+	refreshed := make(chan struct{}, 1)
+	fetch := func() (interface{}, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n > 1 {
+			refreshed <- struct{}{}
+		}
+		return int(n), nil
+	}
+	c.Fetch("rate", "", fetch)
+	time.Sleep(30 * time.Millisecond)
+	if v, _ := c.Fetch("rate", "", fetch); v.(int) != 1 {
+		t.Errorf("got %v; want the stale value", v)
+	}
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("stale value was not refreshed")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if v, _ := c.Fetch("rate", "", fetch); v.(int) != 2 {
+		t.Errorf("got %v; want the refreshed value", v)
+	}
+	if hits != 2 || misses != 1 {
+		t.Errorf("got %d hits and %d misses; want 2 and 1", hits, misses)
+	}
+}
+
+// fakeRemote is a Remote in a map, like Redis with expiry.
+type fakeRemote struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+func (r *fakeRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.values[key], nil
+}
+
+func (r *fakeRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = value
+	r.ttls[key] = ttl
+	return nil
+}
+
+func TestRemoteStore(t *testing.T) {
+	remote := &fakeRemote{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+	newCache := func() *Cache {
+		c := New(NewRemoteStore(remote))
+		c.Namespace = "bitflyer:production"
+		c.TTL["board"] = time.Minute
+		c.StaleWhileRevalidate = time.Second
+		return c
+	}
+	board := &models.Board{Bids: []models.BoardBar{{Price: 100, Amount: 1, Type: models.Bid}}}
+	if _, err := newCache().Fetch("board", "BTC_JPY", func() (interface{}, error) {
+		return board, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := remote.ttls["bitflyer:production:board:BTC_JPY"]; ttl != time.Minute+time.Second {
+		t.Errorf("got ttl %s; want the TTL and the stale period", ttl)
+	}
+
+	// another process sharing the store
+	v, err := newCache().Fetch("board", "BTC_JPY", func() (interface{}, error) {
+		return nil, errors.New("must not fetch")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := v.(*models.Board)
+	if len(got.Bids) != 1 || got.Bids[0].Price != 100 {
+		t.Errorf("got %+v; want %+v", got, board)
+	}
+}
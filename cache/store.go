@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+// MemoryStore keeps the entries in a map of the process.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	*Entry
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty store. Expired entries are dropped as new
+// ones are written.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, nil
+	}
+	return e.Entry, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, e *Entry, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if len(s.entries) > 0 && len(s.entries)%64 == 0 {
+		for k, old := range s.entries {
+			if !now.Before(old.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	s.entries[key] = memoryEntry{Entry: e, expires: now.Add(ttl)}
+	return nil
+}
+
+// Remote is a key-value store with expiry, such as Redis. Get returns nil
+// and no error for missing keys. A Redis client is adapted with GET and SET
+// with PX, mapping redis.Nil to nil.
+type Remote interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// RemoteStore keeps the entries gob encoded in a Remote, so several
+// processes share them. Value types other than the ones of models must be
+// registered with gob.Register.
+type RemoteStore struct {
+	remote Remote
+}
+
+func NewRemoteStore(r Remote) *RemoteStore {
+	return &RemoteStore{remote: r}
+}
+
+func init() {
+	gob.Register(map[string]map[string]float64{})
+	gob.Register(map[string]map[string]models.OrderBookTick{})
+	gob.Register(map[string]*models.Board{})
+	gob.Register(&models.Board{})
+	gob.Register([]models.CurrencyPair{})
+}
+
+func (s *RemoteStore) Get(ctx context.Context, key string) (*Entry, error) {
+	b, err := s.remote.Get(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s", key)
+	}
+	if b == nil {
+		return nil, nil
+	}
+	var e Entry
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&e); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", key)
+	}
+	return &e, nil
+}
+
+func (s *RemoteStore) Set(ctx context.Context, key string, e *Entry, ttl time.Duration) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return errors.Wrapf(err, "failed to encode %s", key)
+	}
+	return errors.Wrapf(s.remote.Set(ctx, key, buf.Bytes(), ttl), "failed to set %s", key)
+}
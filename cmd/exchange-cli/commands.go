@@ -0,0 +1,380 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+type command func(c *cli, args []string) (*table, error)
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"pairs":    pairs,
+		"ticker":   ticker,
+		"book":     book,
+		"rules":    rules,
+		"frozen":   frozen,
+		"balances": balances,
+		"orders":   orders,
+		"order":    order,
+		"address":  address,
+		"withdraw": withdraw,
+		"fees":     fees,
+	}
+}
+
+func pairs(c *cli, args []string) (*table, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("usage: pairs")
+	}
+	api, err := c.public()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := api.CurrencyPairs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Settlement != ps[j].Settlement {
+			return ps[i].Settlement < ps[j].Settlement
+		}
+		return ps[i].Trading < ps[j].Trading
+	})
+	t := &table{header: []string{"TRADING", "SETTLEMENT"}, value: ps}
+	for _, p := range ps {
+		t.append(p.Trading, p.Settlement)
+	}
+	return t, nil
+}
+
+type tick struct {
+	Trading    string `json:"trading"`
+	Settlement string `json:"settlement"`
+	models.OrderBookTick
+}
+
+func ticker(c *cli, args []string) (*table, error) {
+	if len(args) != 0 && len(args) != 2 {
+		return nil, fmt.Errorf("usage: ticker [trading settlement]")
+	}
+	api, err := c.public()
+	if err != nil {
+		return nil, err
+	}
+	m, err := api.OrderBookTickMap()
+	if err != nil {
+		return nil, err
+	}
+	var ticks []tick
+	for trading, settlements := range m {
+		for settlement, v := range settlements {
+			if len(args) == 2 && (trading != args[0] || settlement != args[1]) {
+				continue
+			}
+			ticks = append(ticks, tick{Trading: trading, Settlement: settlement, OrderBookTick: v})
+		}
+	}
+	if len(args) == 2 && len(ticks) == 0 {
+		return nil, fmt.Errorf("no ticker for %s/%s", args[0], args[1])
+	}
+	sort.Slice(ticks, func(i, j int) bool {
+		if ticks[i].Settlement != ticks[j].Settlement {
+			return ticks[i].Settlement < ticks[j].Settlement
+		}
+		return ticks[i].Trading < ticks[j].Trading
+	})
+	t := &table{header: []string{"TRADING", "SETTLEMENT", "BID", "BID AMOUNT", "ASK", "ASK AMOUNT"}, value: ticks}
+	for _, v := range ticks {
+		t.append(v.Trading, v.Settlement,
+			formatFloat(v.BestBidPrice), formatFloat(v.BestBidAmount),
+			formatFloat(v.BestAskPrice), formatFloat(v.BestAskAmount))
+	}
+	return t, nil
+}
+
+func book(c *cli, args []string) (*table, error) {
+	fs := flag.NewFlagSet("book", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	depth := fs.Int("depth", 10, "number of levels on each side")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 || *depth <= 0 {
+		return nil, fmt.Errorf("usage: book [-depth n] <trading> <settlement>")
+	}
+	api, err := c.public()
+	if err != nil {
+		return nil, err
+	}
+	board, err := api.Board(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return nil, err
+	}
+	asks := append([]models.BoardBar(nil), board.Asks...)
+	bids := append([]models.BoardBar(nil), board.Bids...)
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	if len(asks) > *depth {
+		asks = asks[:*depth]
+	}
+	if len(bids) > *depth {
+		bids = bids[:*depth]
+	}
+	t := &table{header: []string{"SIDE", "PRICE", "AMOUNT"}, value: models.Board{Asks: asks, Bids: bids}}
+	// asks from the far side down to the spread, then bids
+	for i := len(asks) - 1; i >= 0; i-- {
+		t.append("ask", formatFloat(asks[i].Price), formatFloat(asks[i].Amount))
+	}
+	for _, b := range bids {
+		t.append("bid", formatFloat(b.Price), formatFloat(b.Amount))
+	}
+	return t, nil
+}
+
+func rules(c *cli, args []string) (*table, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("usage: rules <trading> <settlement>")
+	}
+	api, err := c.public()
+	if err != nil {
+		return nil, err
+	}
+	p, err := api.Precise(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	t := &table{header: []string{"TRADING", "SETTLEMENT", "PRICE PRECISION", "AMOUNT PRECISION"}, value: p}
+	t.append(args[0], args[1], strconv.Itoa(p.PricePrecision), strconv.Itoa(p.AmountPrecision))
+	return t, nil
+}
+
+func frozen(c *cli, args []string) (*table, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("usage: frozen")
+	}
+	api, err := c.public()
+	if err != nil {
+		return nil, err
+	}
+	currencies, err := api.FrozenCurrency()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(currencies)
+	t := &table{header: []string{"CURRENCY"}, value: currencies}
+	for _, currency := range currencies {
+		t.append(currency)
+	}
+	return t, nil
+}
+
+func balances(c *cli, args []string) (*table, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("usage: balances")
+	}
+	api, err := c.private()
+	if err != nil {
+		return nil, err
+	}
+	m, err := api.CompleteBalances()
+	if err != nil {
+		return nil, err
+	}
+	t := &table{header: []string{"CURRENCY", "AVAILABLE", "ON ORDERS"}, value: m}
+	for _, currency := range sortedKeys(m) {
+		b := m[currency]
+		if b.Available == 0 && b.OnOrders == 0 {
+			continue
+		}
+		t.append(currency, formatFloat(b.Available), formatFloat(b.OnOrders))
+	}
+	return t, nil
+}
+
+func orders(c *cli, args []string) (*table, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("usage: orders")
+	}
+	api, err := c.private()
+	if err != nil {
+		return nil, err
+	}
+	active, err := api.ActiveOrders()
+	if err != nil {
+		return nil, err
+	}
+	t := &table{header: []string{"ID", "TRADING", "SETTLEMENT", "SIDE", "PRICE", "AMOUNT"}, value: active}
+	for _, o := range active {
+		t.append(o.ExchangeOrderID, o.Trading, o.Settlement, side(o.Type),
+			formatFloat(o.Price), formatFloat(o.Amount))
+	}
+	return t, nil
+}
+
+func order(c *cli, args []string) (*table, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("usage: order place|cancel ...")
+	}
+	switch args[0] {
+	case "place":
+		if len(args) != 6 {
+			return nil, fmt.Errorf("usage: order place <trading> <settlement> <buy|sell> <price> <amount>")
+		}
+		typ, err := parseSide(args[3])
+		if err != nil {
+			return nil, err
+		}
+		price, err := strconv.ParseFloat(args[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %s", args[4])
+		}
+		amount, err := strconv.ParseFloat(args[5], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %s", args[5])
+		}
+		if err := c.confirm("%s %s %s/%s at %s on %s?", args[3], args[5], args[1], args[2], args[4], c.exchange); err != nil {
+			return nil, err
+		}
+		api, err := c.private()
+		if err != nil {
+			return nil, err
+		}
+		id, err := api.Order(args[1], args[2], typ, price, amount)
+		if err != nil {
+			return nil, err
+		}
+		t := &table{header: []string{"ID"}, value: map[string]string{"id": id}}
+		t.append(id)
+		return t, nil
+	case "cancel":
+		if len(args) != 5 {
+			return nil, fmt.Errorf("usage: order cancel <trading> <settlement> <buy|sell> <order id>")
+		}
+		typ, err := parseSide(args[3])
+		if err != nil {
+			return nil, err
+		}
+		api, err := c.private()
+		if err != nil {
+			return nil, err
+		}
+		return nil, api.CancelOrder(args[1], args[2], typ, args[4])
+	}
+	return nil, fmt.Errorf("unknown order command %s", args[0])
+}
+
+func address(c *cli, args []string) (*table, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("usage: address <currency>")
+	}
+	api, err := c.private()
+	if err != nil {
+		return nil, err
+	}
+	addr, err := api.Address(args[0])
+	if err != nil {
+		return nil, err
+	}
+	t := &table{header: []string{"CURRENCY", "ADDRESS"}, value: map[string]string{"currency": args[0], "address": addr}}
+	t.append(args[0], addr)
+	return t, nil
+}
+
+func withdraw(c *cli, args []string) (*table, error) {
+	fs := flag.NewFlagSet("withdraw", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fee := fs.Float64("fee", 0, "additional fee paid on top of the exchange fee")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 3 {
+		return nil, fmt.Errorf("usage: withdraw [-fee f] <currency> <address> <amount>")
+	}
+	amount, err := strconv.ParseFloat(fs.Arg(2), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %s", fs.Arg(2))
+	}
+	if err := c.confirm("withdraw %s %s from %s to %s?", fs.Arg(2), fs.Arg(0), c.exchange, fs.Arg(1)); err != nil {
+		return nil, err
+	}
+	api, err := c.private()
+	if err != nil {
+		return nil, err
+	}
+	return nil, api.Transfer(fs.Arg(0), fs.Arg(1), amount, *fee)
+}
+
+func fees(c *cli, args []string) (*table, error) {
+	kind := "trade"
+	if len(args) == 1 {
+		kind = args[0]
+	} else if len(args) > 1 {
+		return nil, fmt.Errorf("usage: fees [trade|transfer]")
+	}
+	api, err := c.private()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "trade":
+		m, err := api.TradeFeeRates()
+		if err != nil {
+			return nil, err
+		}
+		t := &table{header: []string{"TRADING", "SETTLEMENT", "MAKER", "TAKER"}, value: m}
+		for _, trading := range sortedKeys(m) {
+			for _, settlement := range sortedKeys(m[trading]) {
+				f := m[trading][settlement]
+				t.append(trading, settlement, formatFloat(f.MakerFee), formatFloat(f.TakerFee))
+			}
+		}
+		return t, nil
+	case "transfer":
+		m, err := api.TransferFee()
+		if err != nil {
+			return nil, err
+		}
+		t := &table{header: []string{"CURRENCY", "FEE"}, value: m}
+		for _, currency := range sortedKeys(m) {
+			t.append(currency, formatFloat(m[currency]))
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown fee kind %s", kind)
+}
+
+func parseSide(s string) (models.OrderType, error) {
+	switch strings.ToLower(s) {
+	case "buy", "bid":
+		return models.Bid, nil
+	case "sell", "ask":
+		return models.Ask, nil
+	}
+	return 0, fmt.Errorf("side must be buy or sell, got %s", s)
+}
+
+func side(t models.OrderType) string {
+	switch t {
+	case models.Bid:
+		return "buy"
+	case models.Ask:
+		return "sell"
+	case models.AskMarket:
+		return "sell market"
+	}
+	return strconv.Itoa(int(t))
+}
+
+// sortedKeys returns the keys of a map with string keys in order.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/keystore"
+)
+
+const usage = `usage: exchange-cli -exchange name [flags] <command> [args]
+
+market data:
+  pairs                                       list currency pairs
+  ticker [trading settlement]                 best bid and ask of every or one pair
+  book [-depth n] <trading> <settlement>      order book, 10 levels by default
+  rules <trading> <settlement>                price and amount precision
+  frozen                                      currencies with deposits or withdrawals suspended
+
+account:
+  balances                                    available and on-order balances
+  orders                                      active orders
+  order place <trading> <settlement> <buy|sell> <price> <amount>
+  order cancel <trading> <settlement> <buy|sell> <order id>
+  address <currency>                          deposit address
+  withdraw [-fee f] <currency> <address> <amount>
+  fees [trade|transfer]                       trade fee rates or transfer fees
+
+Account commands read the keys from -keystore and -account, or from
+$EXCHANGE_API_KEY, $EXCHANGE_SECRET_KEY and $EXCHANGE_API_PASSPHRASE.
+The keystore passphrase is read from $KEYSTORE_PASSPHRASE.
+Orders and withdrawals ask for confirmation unless -yes is given.
+
+flags:
+`
+
+type cli struct {
+	exchange string
+	format   string
+	yes      bool
+	opts     []options.Option
+	keystore string
+	account  string
+
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func main() {
+	c := &cli{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	flag.StringVar(&c.exchange, "exchange", "", "exchange name, such as binance")
+	flag.StringVar(&c.format, "format", "table", "output format: table, json or csv")
+	flag.BoolVar(&c.yes, "yes", false, "do not ask before placing orders and withdrawing")
+	flag.StringVar(&c.keystore, "keystore", "", "path to the encrypted keystore")
+	flag.StringVar(&c.account, "account", "", "keystore account")
+	testnet := flag.Bool("testnet", false, "use the testnet endpoints")
+	baseURL := flag.String("base-url", "", "override the REST endpoint")
+	timeout := flag.Duration("timeout", 0, "request timeout, such as 10s")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if c.exchange == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *testnet {
+		c.opts = append(c.opts, options.WithEnvironment(options.Testnet))
+	}
+	if *baseURL != "" {
+		c.opts = append(c.opts, options.WithBaseURL(*baseURL))
+	}
+	if *timeout != 0 {
+		c.opts = append(c.opts, options.WithTimeout(*timeout))
+	}
+	if err := c.run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %s", args[0])
+	}
+	t, err := cmd(c, args[1:])
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	return t.write(c.out, c.format)
+}
+
+func (c *cli) public() (public.PublicClient, error) {
+	return public.NewClient(c.exchange, c.opts...)
+}
+
+func (c *cli) private() (private.PrivateClient, error) {
+	opts := c.opts
+	var apikey, seckey func() (string, error)
+	if c.keystore != "" {
+		if c.account == "" {
+			return nil, fmt.Errorf("-account is required with -keystore")
+		}
+		passphrase := os.Getenv("KEYSTORE_PASSPHRASE")
+		if passphrase == "" {
+			return nil, fmt.Errorf("$KEYSTORE_PASSPHRASE is not set")
+		}
+		ks, err := keystore.Open(c.keystore, []byte(passphrase))
+		if err != nil {
+			return nil, err
+		}
+		cred, err := ks.Get(c.exchange, c.account)
+		if err != nil {
+			return nil, err
+		}
+		credEnv := options.Environment(cred.Environment)
+		if credEnv == "" {
+			credEnv = options.Production
+		}
+		// the client options check the keys against the resolved endpoints
+		opts = append(opts, options.WithCredentialEnvironment(credEnv))
+		apikey = ks.ApiKeyFunc(c.exchange, c.account, credEnv)
+		seckey = ks.SecretKeyFunc(c.exchange, c.account, credEnv)
+	} else {
+		apikey = func() (string, error) {
+			key, err := getenv("EXCHANGE_API_KEY")
+			if err != nil {
+				return "", err
+			}
+			if passphrase := os.Getenv("EXCHANGE_API_PASSPHRASE"); passphrase != "" {
+				return passphrase + "::" + key, nil
+			}
+			return key, nil
+		}
+		seckey = func() (string, error) {
+			return getenv("EXCHANGE_SECRET_KEY")
+		}
+	}
+	return private.NewClient(private.PROJECT, c.exchange, apikey, seckey, opts...)
+}
+
+// confirm asks the operator before an irreversible operation.
+func (c *cli) confirm(format string, a ...interface{}) error {
+	if c.yes {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, format+" [y/N] ", a...)
+	line, err := c.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+		return fmt.Errorf("aborted")
+	}
+	return nil
+}
+
+func getenv(name string) (string, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return "", fmt.Errorf("$%s is not set", name)
+	}
+	return v, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+func TestWrite(t *testing.T) {
+	tbl := &table{
+		header: []string{"TRADING", "SETTLEMENT"},
+		value:  []models.CurrencyPair{{Trading: "ETH", Settlement: "BTC"}},
+	}
+	tbl.append("ETH", "BTC")
+
+	expected := map[string]string{
+		"table": "TRADING  SETTLEMENT\nETH      BTC\n",
+		"csv":   "TRADING,SETTLEMENT\nETH,BTC\n",
+		"json":  "[\n  {\n    \"trading\": \"ETH\",\n    \"settlement\": \"BTC\"\n  }\n]\n",
+	}
+	for format, want := range expected {
+		var buf bytes.Buffer
+		if err := tbl.write(&buf, format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: expected %q, got %q", format, want, buf.String())
+		}
+	}
+	if err := tbl.write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestParseSide(t *testing.T) {
+	for s, want := range map[string]models.OrderType{"buy": models.Bid, "SELL": models.Ask} {
+		typ, err := parseSide(s)
+		if err != nil || typ != want {
+			t.Errorf("%s: expected %v, got %v %v", s, want, typ, err)
+		}
+	}
+	if _, err := parseSide("long"); err == nil {
+		t.Error("expected an error for an unknown side")
+	}
+}
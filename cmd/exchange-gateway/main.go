@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/xuyangcn/go-exchange-client/config"
+	"github.com/xuyangcn/go-exchange-client/gateway"
+	"github.com/xuyangcn/go-exchange-client/logger"
+	"github.com/xuyangcn/go-exchange-client/metrics"
+	"github.com/xuyangcn/go-exchange-client/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func main() {
+	configPath := flag.String("config", "exchanges.yaml", "exchange config (YAML or JSON)")
+	tokensPath := flag.String("tokens", "tokens.yaml", "token file")
+	listen := flag.String("listen", "127.0.0.1:8080", "listen address")
+	grpcListen := flag.String("grpc-listen", "", "gRPC listen address, gRPC is disabled when empty")
+	tlsCert := flag.String("tls-cert", "", "TLS certificate file, both APIs are served over TLS when set")
+	tlsKey := flag.String("tls-key", "", "TLS key file")
+	rps := flag.Float64("rate", 10, "requests per second allowed for each token, 0 for no limit")
+	burst := flag.Int("burst", 20, "burst allowed for each token")
+	cacheDuration := flag.Duration("cache", time.Second, "how long public responses are reused, 0 to disable")
+	metricsPath := flag.String("metrics", "/metrics", "path of the Prometheus metrics, metrics are disabled when empty")
+	flag.Parse()
+	logger.Set(logger.Development())
+	if (*tlsCert == "") != (*tlsKey == "") {
+		fmt.Fprintln(os.Stderr, "-tls-cert and -tls-key are required together")
+		os.Exit(1)
+	}
+
+	c, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	handler := http.NewServeMux()
+	if *metricsPath != "" {
+		m, err := metrics.New(prometheus.DefaultRegisterer)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		c.Options = append(c.Options, m.Option())
+		handler.Handle(*metricsPath, promhttp.Handler())
+	}
+	tokens, err := gateway.LoadTokens(*tokensPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	gw := gateway.New(c, gateway.Options{
+		Tokens:            tokens,
+		RequestsPerSecond: *rps,
+		Burst:             *burst,
+		CacheDuration:     *cacheDuration,
+	})
+	handler.Handle("/", gw)
+	if *grpcListen != "" {
+		lis, err := net.Listen("tcp", *grpcListen)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		var opts []grpc.ServerOption
+		if *tlsCert != "" {
+			creds, err := credentials.NewServerTLSFromFile(*tlsCert, *tlsKey)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+		s := grpc.NewServer(opts...)
+		rpc.Register(s, gw)
+		logger.Get().Infof("gRPC listening on %s", *grpcListen)
+		go func() {
+			if err := s.Serve(lis); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}()
+	}
+	srv := &http.Server{
+		Addr:         *listen,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: time.Minute,
+	}
+	logger.Get().Infof("listening on %s", *listen)
+	if *tlsCert != "" {
+		err = srv.ListenAndServeTLS(*tlsCert, *tlsKey)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/xuyangcn/go-exchange-client/keystore"
+	"golang.org/x/term"
+)
+
+const usage = `usage: keystore [-file path] <command> [args]
+
+commands:
+  add <exchange> <account> [environment]
+                               add or replace a key (read from the terminal or stdin),
+                               environment is production (default) or testnet
+  list                         list stored keys with secrets redacted
+  remove <exchange> <account>  remove a key
+
+The passphrase is read from $KEYSTORE_PASSPHRASE or prompted for.
+`
+
+func main() {
+	file := flag.String("file", "keystore.json", "path to the encrypted keystore")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if err := run(*file, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(file string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	in := bufio.NewReader(os.Stdin)
+	passphrase := os.Getenv("KEYSTORE_PASSPHRASE")
+	if passphrase == "" {
+		p, err := prompt(in, "passphrase: ")
+		if err != nil {
+			return err
+		}
+		passphrase = p
+	}
+	ks, err := keystore.Open(file, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) != 3 && len(args) != 4 {
+			return fmt.Errorf("usage: keystore add <exchange> <account> [environment]")
+		}
+		var c keystore.Credential
+		if len(args) == 4 {
+			switch args[3] {
+			case "production":
+			case "testnet":
+				c.Environment = args[3]
+			default:
+				return fmt.Errorf("unknown environment %s", args[3])
+			}
+		}
+		if c.ApiKey, err = prompt(in, "api key: "); err != nil {
+			return err
+		}
+		if c.SecretKey, err = prompt(in, "secret key: "); err != nil {
+			return err
+		}
+		if c.Passphrase, err = prompt(in, "api passphrase (optional): "); err != nil {
+			return err
+		}
+		if err := ks.Add(args[1], args[2], c); err != nil {
+			return err
+		}
+		return ks.Save()
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "EXCHANGE\tACCOUNT\tENVIRONMENT\tAPI KEY\tSECRET KEY\tPASSPHRASE")
+		for _, e := range ks.List() {
+			env := e.Credential.Environment
+			if env == "" {
+				env = "production"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Exchange, e.Account, env,
+				e.Credential.ApiKey, e.Credential.SecretKey, e.Credential.Passphrase)
+		}
+		return w.Flush()
+	case "remove":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: keystore remove <exchange> <account>")
+		}
+		if err := ks.Remove(args[1], args[2]); err != nil {
+			return err
+		}
+		return ks.Save()
+	}
+	return fmt.Errorf("unknown command %s", args[0])
+}
+
+// prompt reads a line without echo when stdin is a terminal.
+func prompt(in *bufio.Reader, label string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, label)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return strings.TrimSpace(string(b)), err
+	}
+	line, err := in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read %s%v", label, err)
+	}
+	return strings.TrimSpace(line), nil
+}
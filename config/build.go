@@ -0,0 +1,264 @@
+package config
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/keystore"
+	"github.com/xuyangcn/go-exchange-client/proxy"
+	"golang.org/x/time/rate"
+)
+
+// Options returns the client options described by the instance.
+// Settings which are left out keep the exchange defaults.
+func (e *Exchange) Options() []options.Option {
+	var opts []options.Option
+	if e.Environment != "" {
+		opts = append(opts, options.WithEnvironment(options.Environment(e.Environment)))
+	}
+	if e.Credentials.Environment != "" {
+		opts = append(opts, options.WithCredentialEnvironment(options.Environment(e.Credentials.Environment)))
+	}
+	if e.BaseURL != "" || e.WebSocketURL != "" {
+		opts = append(opts, options.WithEndpoints(options.Endpoints{REST: e.BaseURL, WebSocket: e.WebSocketURL}))
+	}
+	if pool := e.shared().proxies(e.Proxies, e.proxyHealthTarget(), time.Duration(e.ProxyHealth.Interval)); pool != nil {
+		opts = append(opts, pool.Option())
+	}
+	if e.Mirrors != nil {
+		opts = append(opts, options.WithMirrors(e.Mirrors...))
+	}
+	if e.Timeout != 0 {
+		opts = append(opts, options.WithTimeout(time.Duration(e.Timeout)))
+	}
+	if e.Cache.Rate != 0 {
+		opts = append(opts, options.WithRateCacheDuration(time.Duration(e.Cache.Rate)))
+	}
+	if e.Cache.Board != 0 {
+		opts = append(opts, options.WithBoardCacheDuration(time.Duration(e.Cache.Board)))
+	}
+	if l := e.shared().limiter(e.RateLimit); l != nil {
+		opts = append(opts, options.WithRateLimiter(l))
+	}
+	if e.Retry.MaxAttempts != 0 {
+		opts = append(opts, options.WithRetry(e.Retry.MaxAttempts, time.Duration(e.Retry.Backoff)))
+	}
+	return opts
+}
+
+// instanceState is what the clients of an instance have in common, however
+// many of them are built, so bans are counted and requests are limited once
+// per instance.
+type instanceState struct {
+	poolOnce sync.Once
+	pool     *proxy.Pool
+	// stop ends the health checks of pool, guarded by stateMu
+	stop context.CancelFunc
+
+	limiterOnce sync.Once
+	rateLimiter *rate.Limiter
+}
+
+var stateMu sync.Mutex
+
+func (e *Exchange) shared() *instanceState {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if e.state == nil {
+		e.state = &instanceState{}
+	}
+	return e.state
+}
+
+const defaultProxyHealthInterval = time.Minute
+
+func (e *Exchange) proxyHealthTarget() string {
+	if e.ProxyHealth.Target != "" {
+		return e.ProxyHealth.Target
+	}
+	return e.BaseURL
+}
+
+// proxies returns the proxy pool of the instance and starts checking the
+// health of its proxies, until Close.
+func (s *instanceState) proxies(urls []string, target string, interval time.Duration) *proxy.Pool {
+	if len(urls) == 0 {
+		return nil
+	}
+	s.poolOnce.Do(func() {
+		// validated by Validate
+		pool, err := proxy.New(urls)
+		if err != nil {
+			return
+		}
+		if interval == 0 {
+			interval = defaultProxyHealthInterval
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go pool.Run(ctx, target, interval)
+		s.pool = pool
+		stateMu.Lock()
+		s.stop = cancel
+		stateMu.Unlock()
+	})
+	return s.pool
+}
+
+func (s *instanceState) limiter(r RateLimit) *rate.Limiter {
+	if r.RequestsPerSecond == 0 {
+		return nil
+	}
+	s.limiterOnce.Do(func() {
+		burst := r.Burst
+		if burst == 0 {
+			burst = 1
+		}
+		s.rateLimiter = rate.NewLimiter(rate.Limit(r.RequestsPerSecond), burst)
+	})
+	return s.rateLimiter
+}
+
+// Keys returns the api key and secret key functions of the instance.
+// Keys are read when the client first needs them, not when the file is loaded.
+func (e *Exchange) Keys() (apikey func() (string, error), seckey func() (string, error), err error) {
+	c := e.Credentials
+	switch c.Source {
+	case CredentialsEnv:
+		apikey = func() (string, error) {
+			key, err := getenv(c.ApiKeyEnv)
+			if err != nil || c.PassphraseEnv == "" {
+				return key, err
+			}
+			passphrase, err := getenv(c.PassphraseEnv)
+			if err != nil {
+				return "", err
+			}
+			return passphrase + "::" + key, nil
+		}
+		seckey = func() (string, error) {
+			return getenv(c.SecretKeyEnv)
+		}
+		return apikey, seckey, nil
+	case CredentialsKeystore:
+		var (
+			once sync.Once
+			ks   *keystore.Keystore
+			kerr error
+		)
+		open := func() (*keystore.Keystore, error) {
+			once.Do(func() {
+				passphraseEnv := c.KeystorePassphraseEnv
+				if passphraseEnv == "" {
+					passphraseEnv = defaultPassphraseEnv
+				}
+				passphrase, err := getenv(passphraseEnv)
+				if err != nil {
+					kerr = err
+					return
+				}
+				ks, kerr = keystore.Open(c.Keystore, []byte(passphrase))
+			})
+			return ks, kerr
+		}
+		get := func() (keystore.Credential, error) {
+			ks, err := open()
+			if err != nil {
+				return keystore.Credential{}, err
+			}
+			cred, err := ks.Get(e.Exchange, c.Account)
+			if err != nil {
+				return keystore.Credential{}, err
+			}
+			if err := e.checkEnvironment(cred.Environment); err != nil {
+				return keystore.Credential{}, err
+			}
+			return cred, nil
+		}
+		apikey = func() (string, error) {
+			cred, err := get()
+			if err != nil {
+				return "", err
+			}
+			if cred.Passphrase != "" {
+				return cred.Passphrase + "::" + cred.ApiKey, nil
+			}
+			return cred.ApiKey, nil
+		}
+		seckey = func() (string, error) {
+			cred, err := get()
+			if err != nil {
+				return "", err
+			}
+			return cred.SecretKey, nil
+		}
+		return apikey, seckey, nil
+	}
+	return nil, nil, errors.Errorf("%s has no credentials", e.Name)
+}
+
+// checkEnvironment refuses keys issued for another environment than the endpoints.
+// Custom endpoints accept any keys.
+func (e *Exchange) checkEnvironment(credEnv string) error {
+	if e.BaseURL != "" || e.WebSocketURL != "" {
+		return nil
+	}
+	env := options.Environment(e.Environment)
+	if env == "" {
+		env = options.Production
+	}
+	if options.Environment(credEnv) == "" {
+		credEnv = string(options.Production)
+	}
+	if options.Environment(credEnv) != env {
+		return errors.Wrapf(options.ErrEnvironmentMismatch, "%s: %s credentials with %s endpoints", e.Name, credEnv, env)
+	}
+	return nil
+}
+
+func getenv(name string) (string, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return "", errors.Errorf("$%s is not set", name)
+	}
+	return v, nil
+}
+
+// Close stops the proxy health checks of the instances. Clients built before
+// keep using the proxies, which are no longer checked.
+func (c *Config) Close() {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	for i := range c.Exchanges {
+		if s := c.Exchanges[i].state; s != nil && s.stop != nil {
+			s.stop()
+		}
+	}
+}
+
+// PublicClient builds a new public client of the instance called name.
+func (c *Config) PublicClient(name string) (public.PublicClient, error) {
+	e, err := c.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return public.NewClient(e.Exchange, append(e.Options(), c.Options...)...)
+}
+
+// PrivateClient builds a new private client of the instance called name.
+func (c *Config) PrivateClient(name string) (private.PrivateClient, error) {
+	e, err := c.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	apikey, seckey, err := e.Keys()
+	if err != nil {
+		return nil, err
+	}
+	return private.NewClient(private.PROJECT, e.Exchange, apikey, seckey, append(e.Options(), c.Options...)...)
+}
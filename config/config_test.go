@@ -0,0 +1,240 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/keystore"
+)
+
+const yamlConfig = `
+exchanges:
+  - name: binance-main
+    exchange: binance
+    base_url: https://api1.binance.com
+    timeout: 10s
+    credentials:
+      source: env
+      api_key_env: TEST_BINANCE_API_KEY
+      secret_key_env: TEST_BINANCE_SECRET_KEY
+    proxies: ["http://localhost:3128"]
+    mirrors: []
+    rate_limit: {requests_per_second: 10, burst: 5}
+    retry: {max_attempts: 3, backoff: 200ms}
+    cache: {rate: 3s, board: 1s}
+  - name: huobi
+    exchange: huobipro
+`
+
+const jsonConfig = `{
+  "exchanges": [
+    {"name": "binance-main", "exchange": "binance", "timeout": "10s", "cache": {"rate": "3s"}}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(yamlConfig), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := c.Lookup("binance-main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(e.Timeout) != 10*time.Second || time.Duration(e.Retry.Backoff) != 200*time.Millisecond {
+		t.Errorf("durations are not parsed: %+v", e)
+	}
+	if e.RateLimit.RequestsPerSecond != 10 || e.RateLimit.Burst != 5 || len(e.Proxies) != 1 || e.Mirrors == nil {
+		t.Errorf("unexpected exchange: %+v", e)
+	}
+
+	c, err = Parse([]byte(jsonConfig), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(c.Exchanges[0].Cache.Rate) != 3*time.Second {
+		t.Errorf("durations are not parsed: %+v", c.Exchanges[0])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := map[string]string{
+		"unknown field":    "exchanges:\n  - name: a\n    exchange: binance\n    timeuot: 1s\n",
+		"unknown exchange": "exchanges:\n  - name: a\n    exchange: nosuchexchange\n",
+		"duplicate name":   "exchanges:\n  - name: a\n    exchange: binance\n  - name: a\n    exchange: huobi\n",
+		"missing name":     "exchanges:\n  - exchange: binance\n",
+		"bad duration":     "exchanges:\n  - name: a\n    exchange: binance\n    timeout: 10\n",
+		"bad proxy":        "exchanges:\n  - name: a\n    exchange: binance\n    proxies: [\"ftp://proxy:21\"]\n",
+		"no health target": "exchanges:\n  - name: a\n    exchange: binance\n    proxies: [\"http://proxy:3128\"]\n",
+		"bad credentials":  "exchanges:\n  - name: a\n    exchange: binance\n    credentials: {source: env}\n",
+		"unknown source":   "exchanges:\n  - name: a\n    exchange: binance\n    credentials: {source: vault}\n",
+		"unknown env":      "exchanges:\n  - name: a\n    exchange: binance\n    environment: staging\n",
+	}
+	for name, doc := range invalid {
+		if _, err := Parse([]byte(doc), "yaml"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for file, doc := range map[string]string{"a.yaml": yamlConfig, "b.json": jsonConfig} {
+		path := filepath.Join(dir, file)
+		if err := ioutil.WriteFile(path, []byte(doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "c.toml")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestClients(t *testing.T) {
+	c, err := Parse([]byte(yamlConfig), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	pub, err := c.PublicClient("huobi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.Capabilities().Exchange != "huobi" {
+		t.Errorf("expected a huobi client, got %s", pub.Capabilities().Exchange)
+	}
+	if _, err := c.PrivateClient("huobi"); err == nil {
+		t.Error("expected an error for an instance without credentials")
+	}
+
+	os.Setenv("TEST_BINANCE_API_KEY", "APIKEY")
+	os.Setenv("TEST_BINANCE_SECRET_KEY", "SECKEY")
+	defer os.Unsetenv("TEST_BINANCE_API_KEY")
+	defer os.Unsetenv("TEST_BINANCE_SECRET_KEY")
+	cli, err := c.PrivateClient("binance-main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cli.Capabilities().Exchange != "binance" {
+		t.Errorf("expected a binance client, got %s", cli.Capabilities().Exchange)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+	ks, err := keystore.Open(path, []byte("phrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks.Add("kucoin", "main", keystore.Credential{ApiKey: "APIKEY", SecretKey: "SECKEY", Passphrase: "PASS"})
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &Exchange{Name: "kucoin", Exchange: "kucoin", Credentials: Credentials{
+		Source: CredentialsKeystore, Keystore: path, Account: "main", KeystorePassphraseEnv: "TEST_KEYSTORE_PASSPHRASE",
+	}}
+	apikey, seckey, err := e.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apikey(); err == nil || !strings.Contains(err.Error(), "TEST_KEYSTORE_PASSPHRASE") {
+		t.Errorf("expected an error for the missing passphrase, got %v", err)
+	}
+
+	os.Setenv("TEST_KEYSTORE_PASSPHRASE", "phrase")
+	defer os.Unsetenv("TEST_KEYSTORE_PASSPHRASE")
+	apikey, seckey, err = e.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err := apikey(); err != nil || key != "PASS::APIKEY" {
+		t.Errorf("expected PASS::APIKEY, got %s %v", key, err)
+	}
+	if key, err := seckey(); err != nil || key != "SECKEY" {
+		t.Errorf("expected SECKEY, got %s %v", key, err)
+	}
+
+	e.Environment = "testnet"
+	apikey, _, err = e.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apikey(); errors.Cause(err) != options.ErrEnvironmentMismatch {
+		t.Errorf("expected %v, got %v", options.ErrEnvironmentMismatch, err)
+	}
+}
+
+func TestProxyHealth(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	doc := "exchanges:\n  - name: a\n    exchange: binance\n    proxies: [\"http://127.0.0.1:1\"]\n" +
+		"    proxy_health: {target: \"" + target.URL + "\", interval: 10ms}\n"
+	c, err := Parse([]byte(doc), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	e, err := c.Lookup("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(e.ProxyHealth.Interval) != 10*time.Millisecond || e.proxyHealthTarget() != target.URL {
+		t.Fatalf("proxy_health is not parsed: %+v", e.ProxyHealth)
+	}
+	e.Options()
+	pool := e.shared().pool
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Stats()[0].Healthy {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the dead proxy to fail its health check")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSharedState(t *testing.T) {
+	c, err := Parse([]byte(yamlConfig), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := c.Lookup("binance-main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	pool := e.shared().proxies(e.Proxies, e.proxyHealthTarget(), 0)
+	if pool == nil || pool != e.shared().proxies(e.Proxies, e.proxyHealthTarget(), 0) {
+		t.Fatal("expected one proxy pool per instance")
+	}
+	if pool.MaxFailures == 0 {
+		t.Error("expected failing proxies to be left out")
+	}
+	if e, _ := c.Lookup("huobi"); e.shared().proxies(e.Proxies, "", 0) != nil {
+		t.Error("expected no pool without proxies")
+	}
+	l := e.shared().limiter(e.RateLimit)
+	if l == nil || l != e.shared().limiter(e.RateLimit) || l.Burst() != 5 {
+		t.Error("expected one rate limiter per instance")
+	}
+}
@@ -0,0 +1,284 @@
+// Package gateway serves the public and private clients of a config over a
+// versioned REST/JSON API, for services which are not written in Go.
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/config"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"golang.org/x/time/rate"
+)
+
+const apiPrefix = "/v1/"
+
+// maxBodyBytes limits request bodies, which are small JSON objects.
+const maxBodyBytes = 1 << 20
+
+// Options are the server side settings of the gateway.
+type Options struct {
+	Tokens []Token
+	// RequestsPerSecond and Burst limit every token on its own. Zero disables the limit.
+	RequestsPerSecond float64
+	Burst             int
+	// CacheDuration is how long public responses are reused. Zero disables the cache.
+	CacheDuration time.Duration
+}
+
+// Server is an http.Handler for the instances of a config.
+// Clients are built on the first request for their instance.
+type Server struct {
+	config *config.Config
+	opts   Options
+	cache  *cache.Cache
+
+	m        sync.Mutex
+	limiters map[string]*rate.Limiter
+	publics  map[string]public.PublicClient
+	privates map[string]private.PrivateClient
+}
+
+func New(c *config.Config, opts Options) *Server {
+	s := &Server{
+		config:   c,
+		opts:     opts,
+		limiters: make(map[string]*rate.Limiter),
+		publics:  make(map[string]public.PublicClient),
+		privates: make(map[string]private.PrivateClient),
+	}
+	if opts.CacheDuration > 0 {
+		s.cache = cache.New(opts.CacheDuration, 10*opts.CacheDuration)
+	}
+	return s
+}
+
+// httpError is an error with the status code it is reported with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+func errorf(status int, format string, a ...interface{}) error {
+	return &httpError{status: status, err: fmt.Errorf(format, a...)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == apiPrefix+"openapi.yaml" {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte(OpenAPI))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	v, err := s.serve(r)
+	if err != nil {
+		writeJSON(w, StatusCode(err), map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) serve(r *http.Request) (interface{}, error) {
+	if !strings.HasPrefix(r.URL.Path, apiPrefix) {
+		return nil, errorf(http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+	token, err := s.authenticate(r.Header.Get("Authorization"))
+	if err != nil {
+		return nil, err
+	}
+	if !s.allow(token) {
+		return nil, errorf(http.StatusTooManyRequests, "rate limit exceeded")
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+	if len(segments) == 1 && segments[0] == "instances" && r.Method == http.MethodGet {
+		return s.instances(token), nil
+	}
+	instance, segments := segments[0], segments[1:]
+	rt, params, err := match(r.Method, segments)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.grant(token, rt.op, rt.public, instance); err != nil {
+		return nil, err
+	}
+	req := &request{Request: r, server: s, instance: instance, params: params}
+
+	if !rt.public || s.cache == nil {
+		return rt.handle(req)
+	}
+	key := instance + " " + r.URL.Path + "?" + r.URL.Query().Encode()
+	if v, found := s.cache.Get(key); found {
+		return v, nil
+	}
+	v, err := rt.handle(req)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.SetDefault(key, v)
+	return v, nil
+}
+
+// Authorize checks the "Bearer <token>" authorization, the rate limit of the
+// token and whether it may call op on instance. The gRPC server goes through it
+// so both APIs share the tokens and their limits.
+func (s *Server) Authorize(authorization string, op models.Operation, public bool, instance string) error {
+	token, err := s.authenticate(authorization)
+	if err != nil {
+		return err
+	}
+	if !s.allow(token) {
+		return errorf(http.StatusTooManyRequests, "rate limit exceeded")
+	}
+	return s.grant(token, op, public, instance)
+}
+
+// StatusCode returns the http status err is reported with.
+func StatusCode(err error) int {
+	if e, ok := err.(*httpError); ok {
+		return e.status
+	}
+	return http.StatusBadGateway
+}
+
+// authenticate finds the token of a "Bearer <token>" authorization.
+func (s *Server) authenticate(auth string) (*Token, error) {
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return nil, errorf(http.StatusUnauthorized, "missing bearer token")
+	}
+	secret := []byte(strings.TrimPrefix(auth, "Bearer "))
+	for i := range s.opts.Tokens {
+		t := &s.opts.Tokens[i]
+		if t.secret != "" && subtle.ConstantTimeCompare([]byte(t.secret), secret) == 1 {
+			return t, nil
+		}
+	}
+	return nil, errorf(http.StatusUnauthorized, "invalid bearer token")
+}
+
+func (s *Server) grant(t *Token, op models.Operation, public bool, instance string) error {
+	if _, err := s.config.Lookup(instance); err != nil {
+		return errorf(http.StatusNotFound, "%s", err)
+	}
+	if !t.allows(op, public, instance) {
+		return errorf(http.StatusForbidden, "%s may not call %s on %s", t.Name, op, instance)
+	}
+	return nil
+}
+
+func (s *Server) allow(t *Token) bool {
+	if s.opts.RequestsPerSecond <= 0 {
+		return true
+	}
+	s.m.Lock()
+	l, ok := s.limiters[t.Name]
+	if !ok {
+		burst := s.opts.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		l = rate.NewLimiter(rate.Limit(s.opts.RequestsPerSecond), burst)
+		s.limiters[t.Name] = l
+	}
+	s.m.Unlock()
+	return l.Allow()
+}
+
+type instance struct {
+	Name     string `json:"name"`
+	Exchange string `json:"exchange"`
+}
+
+func (s *Server) instances(t *Token) []instance {
+	list := make([]instance, 0)
+	for _, e := range s.config.Exchanges {
+		if len(t.Instances) == 0 || contains(t.Instances, e.Name) {
+			list = append(list, instance{Name: e.Name, Exchange: e.Exchange})
+		}
+	}
+	return list
+}
+
+// PublicClient returns the public client of the instance, built on first use.
+func (s *Server) PublicClient(name string) (public.PublicClient, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if cli, ok := s.publics[name]; ok {
+		return cli, nil
+	}
+	cli, err := s.config.PublicClient(name)
+	if err != nil {
+		return nil, errorf(http.StatusInternalServerError, "%s", err)
+	}
+	s.publics[name] = cli
+	return cli, nil
+}
+
+// PrivateClient returns the private client of the instance, built on first use.
+func (s *Server) PrivateClient(name string) (private.PrivateClient, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if cli, ok := s.privates[name]; ok {
+		return cli, nil
+	}
+	cli, err := s.config.PrivateClient(name)
+	if err != nil {
+		return nil, errorf(http.StatusInternalServerError, "%s", err)
+	}
+	s.privates[name] = cli
+	return cli, nil
+}
+
+// match finds the route of the path segments after the instance name.
+func match(method string, segments []string) (*route, map[string]string, error) {
+	found := false
+	for i := range routes {
+		rt := &routes[i]
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		found = true
+		if rt.method == method {
+			return rt, params, nil
+		}
+	}
+	if found {
+		return nil, nil, errorf(http.StatusMethodNotAllowed, "method %s is not allowed", method)
+	}
+	return nil, nil, errorf(http.StatusNotFound, "unknown path /%s", strings.Join(segments, "/"))
+}
+
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	parts := strings.Split(rt.path, "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range parts {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			params[p[1:len(p)-1]] = segments[i]
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,276 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/config"
+)
+
+// newFakeBinance serves the binance endpoints used by the tests.
+func newFakeBinance(requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch r.URL.Path {
+		case "/api/v1/exchangeInfo":
+			fmt.Fprint(w, `{"symbols":[{"symbol":"ETHBTC","baseAsset":"ETH","quoteAsset":"BTC","baseAssetPrecision":8,"quotePrecision":6}]}`)
+		case "/api/v1/ticker/24hr":
+			fmt.Fprint(w, `[{"symbol":"ETHBTC","lastPrice":"0.03","volume":"100","bidPrice":"0.029","askPrice":"0.031"}]`)
+		case "/api/v1/depth":
+			fmt.Fprint(w, `{"bids":[["0.029","1.5"]],"asks":[["0.031","2"]]}`)
+		case "/api/v1/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixNano()/int64(time.Millisecond))
+		case "/api/v3/account":
+			if r.Header.Get("X-MBX-APIKEY") != "APIKEY" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"code":-2015,"msg":"Invalid API-key"}`)
+				return
+			}
+			fmt.Fprint(w, `{"balances":[{"asset":"BTC","free":"1.5","locked":"0.5"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+}
+
+func newTestServer(t *testing.T, baseURL string, opts Options) *httptest.Server {
+	doc := fmt.Sprintf(`
+exchanges:
+  - name: binance-main
+    exchange: binance
+    base_url: %s
+    credentials: {source: env, api_key_env: TEST_GATEWAY_API_KEY, secret_key_env: TEST_GATEWAY_SECRET_KEY}
+  - name: binance-sub
+    exchange: binance
+    base_url: %s
+`, baseURL, baseURL)
+	c, err := config.Parse([]byte(doc), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(New(c, opts))
+}
+
+func get(t *testing.T, srv *httptest.Server, token string, path string, v interface{}) int {
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if v != nil && res.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res.StatusCode
+}
+
+func TestPublicRoutes(t *testing.T) {
+	var requests int32
+	fake := newFakeBinance(&requests)
+	defer fake.Close()
+	srv := newTestServer(t, fake.URL, Options{
+		Tokens:        []Token{NewToken("research", "secret", []string{GrantPublic}, nil)},
+		CacheDuration: time.Minute,
+	})
+	defer srv.Close()
+
+	var pairs []map[string]string
+	if status := get(t, srv, "secret", "/v1/binance-main/pairs", &pairs); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if len(pairs) != 1 || pairs[0]["trading"] != "ETH" || pairs[0]["settlement"] != "BTC" {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+
+	var rate map[string]float64
+	if status := get(t, srv, "secret", "/v1/binance-main/rate?trading=ETH&settlement=BTC", &rate); status != http.StatusOK || rate["rate"] != 0.03 {
+		t.Errorf("expected rate 0.03, got %d %v", status, rate)
+	}
+
+	var board struct {
+		Asks []struct{ Price, Amount float64 }
+		Bids []struct{ Price, Amount float64 }
+	}
+	if status := get(t, srv, "secret", "/v1/binance-main/board?trading=ETH&settlement=BTC", &board); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if len(board.Bids) != 1 || board.Bids[0].Price != 0.029 || board.Asks[0].Amount != 2 {
+		t.Errorf("unexpected board %+v", board)
+	}
+
+	before := atomic.LoadInt32(&requests)
+	get(t, srv, "secret", "/v1/binance-main/board?trading=ETH&settlement=BTC", nil)
+	if after := atomic.LoadInt32(&requests); after != before {
+		t.Errorf("cached board is fetched again, %d requests", after-before)
+	}
+
+	if status := get(t, srv, "secret", "/v1/binance-main/board", nil); status != http.StatusBadRequest {
+		t.Errorf("expected 400 without a pair, got %d", status)
+	}
+	if status := get(t, srv, "secret", "/v1/nosuchinstance/pairs", nil); status != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown instance, got %d", status)
+	}
+	if status := get(t, srv, "secret", "/v1/binance-main/nosuchroute", nil); status != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown route, got %d", status)
+	}
+}
+
+func TestPrivateRoutes(t *testing.T) {
+	var requests int32
+	fake := newFakeBinance(&requests)
+	defer fake.Close()
+	srv := newTestServer(t, fake.URL, Options{Tokens: []Token{
+		NewToken("bot", "bot-secret", []string{GrantAll}, []string{"binance-main"}),
+		NewToken("dashboard", "dash-secret", []string{GrantPublic, "CompleteBalances"}, nil),
+	}})
+	defer srv.Close()
+
+	os.Setenv("TEST_GATEWAY_API_KEY", "APIKEY")
+	os.Setenv("TEST_GATEWAY_SECRET_KEY", "SECKEY")
+	defer os.Unsetenv("TEST_GATEWAY_API_KEY")
+	defer os.Unsetenv("TEST_GATEWAY_SECRET_KEY")
+
+	var balances map[string]map[string]float64
+	if status := get(t, srv, "dash-secret", "/v1/binance-main/balances", &balances); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if balances["BTC"]["available"] != 1.5 || balances["BTC"]["on_orders"] != 0.5 {
+		t.Errorf("unexpected balances %v", balances)
+	}
+
+	if status := get(t, srv, "dash-secret", "/v1/binance-main/orders", nil); status != http.StatusForbidden {
+		t.Errorf("expected 403 for a route which is not granted, got %d", status)
+	}
+	if status := get(t, srv, "bot-secret", "/v1/binance-sub/pairs", nil); status != http.StatusForbidden {
+		t.Errorf("expected 403 for an instance which is not granted, got %d", status)
+	}
+	if status := get(t, srv, "dash-secret", "/v1/binance-sub/balances", nil); status != http.StatusInternalServerError {
+		t.Errorf("expected 500 for an instance without credentials, got %d", status)
+	}
+
+	var instances []map[string]string
+	if status := get(t, srv, "bot-secret", "/v1/instances", &instances); status != http.StatusOK || len(instances) != 1 {
+		t.Errorf("expected the granted instance only, got %d %v", status, instances)
+	}
+
+	res, err := http.Post(srv.URL+"/v1/binance-main/withdrawals", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", res.StatusCode)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	var requests int32
+	fake := newFakeBinance(&requests)
+	defer fake.Close()
+	srv := newTestServer(t, fake.URL, Options{Tokens: []Token{
+		NewToken("bot", "bot-secret", []string{GrantAll}, []string{"binance-main"}),
+	}})
+	defer srv.Close()
+
+	body := `{"side":"buy","trading":"` + strings.Repeat("A", maxBodyBytes) + `"}`
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/binance-main/orders", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer bot-secret")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for an oversized body, got %d", res.StatusCode)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var requests int32
+	fake := newFakeBinance(&requests)
+	defer fake.Close()
+	srv := newTestServer(t, fake.URL, Options{
+		Tokens:            []Token{NewToken("research", "secret", []string{GrantPublic}, nil)},
+		RequestsPerSecond: 0.001,
+		Burst:             2,
+	})
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		if status := get(t, srv, "secret", "/v1/binance-main/pairs", nil); status != http.StatusOK {
+			t.Fatalf("expected 200 within the burst, got %d", status)
+		}
+	}
+	if status := get(t, srv, "secret", "/v1/binance-main/pairs", nil); status != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", status)
+	}
+	if status := get(t, srv, "wrong", "/v1/binance-main/pairs", nil); status != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an unknown token, got %d", status)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	srv := newTestServer(t, "http://localhost:4243", Options{})
+	defer srv.Close()
+	res, err := http.Get(srv.URL + "/v1/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(string(body), "openapi: 3") {
+		t.Errorf("unexpected document %d %.40s", res.StatusCode, body)
+	}
+	// every route is documented
+	for _, rt := range routes {
+		if !strings.Contains(OpenAPI, "/{instance}/"+rt.path+":") {
+			t.Errorf("%s is not documented", rt.path)
+		}
+	}
+}
+
+func TestLoadTokens(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gateway")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tokens.yaml")
+	doc := "tokens:\n  - name: research\n    token_env: TEST_GATEWAY_TOKEN\n    routes: [public]\n"
+	if err := ioutil.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTokens(path); err == nil {
+		t.Error("expected an error for an unset token")
+	}
+	os.Setenv("TEST_GATEWAY_TOKEN", "secret")
+	defer os.Unsetenv("TEST_GATEWAY_TOKEN")
+	tokens, err := LoadTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 || tokens[0].secret != "secret" {
+		t.Errorf("unexpected tokens %+v", tokens)
+	}
+}
@@ -0,0 +1,302 @@
+package gateway
+
+// OpenAPI describes the v1 API. It is served at /v1/openapi.yaml without authentication.
+const OpenAPI = `openapi: 3.0.3
+info:
+  title: go-exchange-client gateway
+  version: "1"
+  description: |
+    Public and private exchange operations of the configured instances.
+    Every request except this document needs "Authorization: Bearer <token>".
+    Errors are returned as {"error": "..."} with 400 for invalid parameters,
+    401 for a missing or unknown token, 403 for a route the token may not call,
+    404 for an unknown instance or path, 429 when the token is rate limited and
+    502 when the exchange fails.
+servers:
+  - url: /v1
+security:
+  - bearer: []
+paths:
+  /instances:
+    get:
+      summary: Instances the token may use
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    name: {type: string}
+                    exchange: {type: string}
+  /{instance}/pairs:
+    get:
+      summary: Currency pairs (CurrencyPairs)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pair"}
+  /{instance}/rate:
+    get:
+      summary: Last price of a pair (Rate)
+      parameters:
+        - $ref: "#/components/parameters/instance"
+        - $ref: "#/components/parameters/trading"
+        - $ref: "#/components/parameters/settlement"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  rate: {type: number}
+  /{instance}/tickers:
+    get:
+      summary: Best bid and ask of every pair (OrderBookTickMap)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Ticker"}
+  /{instance}/frozen:
+    get:
+      summary: Currencies with deposits or withdrawals suspended (FrozenCurrency)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {type: string}
+  /{instance}/board:
+    get:
+      summary: Order book of a pair (Board)
+      parameters:
+        - $ref: "#/components/parameters/instance"
+        - $ref: "#/components/parameters/trading"
+        - $ref: "#/components/parameters/settlement"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Board"}
+  /{instance}/precision:
+    get:
+      summary: Price and amount precision of a pair (Precise)
+      parameters:
+        - $ref: "#/components/parameters/instance"
+        - $ref: "#/components/parameters/trading"
+        - $ref: "#/components/parameters/settlement"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  price: {type: integer}
+                  amount: {type: integer}
+  /{instance}/balances:
+    get:
+      summary: Balances by currency (CompleteBalances)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                additionalProperties:
+                  type: object
+                  properties:
+                    available: {type: number}
+                    on_orders: {type: number}
+  /{instance}/orders:
+    get:
+      summary: Active orders (ActiveOrders)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Order"}
+    post:
+      summary: Place a limit order (Order)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Order"}
+      responses:
+        "200":
+          description: The order with its id
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Order"}
+  /{instance}/orders/{id}:
+    delete:
+      summary: Cancel an order (CancelOrder)
+      parameters:
+        - $ref: "#/components/parameters/instance"
+        - $ref: "#/components/parameters/id"
+        - $ref: "#/components/parameters/trading"
+        - $ref: "#/components/parameters/settlement"
+        - {name: side, in: query, required: true, schema: {type: string, enum: [buy, sell]}}
+      responses:
+        "200":
+          description: OK
+  /{instance}/orders/{id}/filled:
+    get:
+      summary: Whether an order is filled (IsOrderFilled)
+      parameters:
+        - $ref: "#/components/parameters/instance"
+        - $ref: "#/components/parameters/id"
+        - $ref: "#/components/parameters/trading"
+        - $ref: "#/components/parameters/settlement"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  filled: {type: boolean}
+  /{instance}/fees/trade:
+    get:
+      summary: Maker and taker fee rates (TradeFeeRates)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    trading: {type: string}
+                    settlement: {type: string}
+                    maker: {type: number}
+                    taker: {type: number}
+  /{instance}/fees/transfer:
+    get:
+      summary: Withdrawal fees by currency (TransferFee)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                additionalProperties: {type: number}
+  /{instance}/addresses/{currency}:
+    get:
+      summary: Deposit address (Address)
+      parameters:
+        - $ref: "#/components/parameters/instance"
+        - {name: currency, in: path, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  currency: {type: string}
+                  address: {type: string}
+  /{instance}/withdrawals:
+    post:
+      summary: Withdraw to an address (Transfer)
+      parameters: [{$ref: "#/components/parameters/instance"}]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [currency, address, amount]
+              properties:
+                currency: {type: string}
+                address: {type: string}
+                amount: {type: number}
+                additional_fee: {type: number}
+      responses:
+        "200":
+          description: OK
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+  parameters:
+    instance: {name: instance, in: path, required: true, schema: {type: string}}
+    id: {name: id, in: path, required: true, schema: {type: string}}
+    trading: {name: trading, in: query, required: true, schema: {type: string}, example: ETH}
+    settlement: {name: settlement, in: query, required: true, schema: {type: string}, example: BTC}
+  schemas:
+    Pair:
+      type: object
+      properties:
+        trading: {type: string}
+        settlement: {type: string}
+    Ticker:
+      type: object
+      properties:
+        trading: {type: string}
+        settlement: {type: string}
+        best_bid_price: {type: number}
+        best_bid_amount: {type: number}
+        best_ask_price: {type: number}
+        best_ask_amount: {type: number}
+    Board:
+      type: object
+      properties:
+        asks:
+          type: array
+          items: {$ref: "#/components/schemas/BoardBar"}
+        bids:
+          type: array
+          items: {$ref: "#/components/schemas/BoardBar"}
+    BoardBar:
+      type: object
+      properties:
+        price: {type: number}
+        amount: {type: number}
+    Order:
+      type: object
+      required: [trading, settlement, side, price, amount]
+      properties:
+        id: {type: string, readOnly: true}
+        trading: {type: string}
+        settlement: {type: string}
+        side: {type: string, enum: [buy, sell]}
+        price: {type: number}
+        amount: {type: number}
+`
@@ -0,0 +1,375 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+type route struct {
+	method string
+	// path after /v1/{instance}/, with {name} for parameters
+	path   string
+	op     models.Operation
+	public bool
+	handle func(r *request) (interface{}, error)
+}
+
+var routes = []route{
+	{http.MethodGet, "pairs", models.OpCurrencyPairs, true, pairs},
+	{http.MethodGet, "rate", models.OpRate, true, rateHandler},
+	{http.MethodGet, "tickers", models.OpOrderBookTickMap, true, tickers},
+	{http.MethodGet, "frozen", models.OpFrozenCurrency, true, frozen},
+	{http.MethodGet, "board", models.OpBoard, true, board},
+	{http.MethodGet, "precision", models.OpPrecise, true, precision},
+
+	{http.MethodGet, "balances", models.OpCompleteBalances, false, balances},
+	{http.MethodGet, "orders", models.OpActiveOrders, false, orders},
+	{http.MethodPost, "orders", models.OpOrder, false, placeOrder},
+	{http.MethodDelete, "orders/{id}", models.OpCancelOrder, false, cancelOrder},
+	{http.MethodGet, "orders/{id}/filled", models.OpIsOrderFilled, false, filled},
+	{http.MethodGet, "fees/trade", models.OpTradeFeeRates, false, tradeFees},
+	{http.MethodGet, "fees/transfer", models.OpTransferFee, false, transferFees},
+	{http.MethodGet, "addresses/{currency}", models.OpAddress, false, address},
+	{http.MethodPost, "withdrawals", models.OpTransfer, false, withdraw},
+}
+
+type request struct {
+	*http.Request
+	server   *Server
+	instance string
+	params   map[string]string
+}
+
+// pair reads the trading and settlement query parameters.
+func (r *request) pair() (string, string, error) {
+	q := r.URL.Query()
+	trading, settlement := q.Get("trading"), q.Get("settlement")
+	if trading == "" || settlement == "" {
+		return "", "", errorf(http.StatusBadRequest, "trading and settlement are required")
+	}
+	return trading, settlement, nil
+}
+
+func (r *request) side(s string) (models.OrderType, error) {
+	switch strings.ToLower(s) {
+	case "buy":
+		return models.Bid, nil
+	case "sell":
+		return models.Ask, nil
+	}
+	return 0, errorf(http.StatusBadRequest, "side must be buy or sell, got %q", s)
+}
+
+func (r *request) decode(v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return errorf(http.StatusRequestEntityTooLarge, "body exceeds %d bytes", tooLarge.Limit)
+		}
+		return errorf(http.StatusBadRequest, "invalid body: %s", err)
+	}
+	return nil
+}
+
+type Ticker struct {
+	Trading       string  `json:"trading"`
+	Settlement    string  `json:"settlement"`
+	BestBidPrice  float64 `json:"best_bid_price"`
+	BestBidAmount float64 `json:"best_bid_amount"`
+	BestAskPrice  float64 `json:"best_ask_price"`
+	BestAskAmount float64 `json:"best_ask_amount"`
+}
+
+type Precision struct {
+	Price  int `json:"price"`
+	Amount int `json:"amount"`
+}
+
+type Balance struct {
+	Available float64 `json:"available"`
+	OnOrders  float64 `json:"on_orders"`
+}
+
+type Order struct {
+	ID         string  `json:"id"`
+	Trading    string  `json:"trading"`
+	Settlement string  `json:"settlement"`
+	Side       string  `json:"side"`
+	Price      float64 `json:"price"`
+	Amount     float64 `json:"amount"`
+}
+
+type TradeFee struct {
+	Trading    string  `json:"trading"`
+	Settlement string  `json:"settlement"`
+	Maker      float64 `json:"maker"`
+	Taker      float64 `json:"taker"`
+}
+
+type Withdrawal struct {
+	Currency      string  `json:"currency"`
+	Address       string  `json:"address"`
+	Amount        float64 `json:"amount"`
+	AdditionalFee float64 `json:"additional_fee"`
+}
+
+func pairs(r *request) (interface{}, error) {
+	cli, err := r.server.PublicClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	return cli.CurrencyPairs()
+}
+
+func rateHandler(r *request) (interface{}, error) {
+	trading, settlement, err := r.pair()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := r.server.PublicClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := cli.Rate(trading, settlement)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]float64{"rate": rate}, nil
+}
+
+func tickers(r *request) (interface{}, error) {
+	cli, err := r.server.PublicClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	m, err := cli.OrderBookTickMap()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Ticker, 0)
+	for trading, settlements := range m {
+		for settlement, v := range settlements {
+			list = append(list, Ticker{
+				Trading:       trading,
+				Settlement:    settlement,
+				BestBidPrice:  v.BestBidPrice,
+				BestBidAmount: v.BestBidAmount,
+				BestAskPrice:  v.BestAskPrice,
+				BestAskAmount: v.BestAskAmount,
+			})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Settlement != list[j].Settlement {
+			return list[i].Settlement < list[j].Settlement
+		}
+		return list[i].Trading < list[j].Trading
+	})
+	return list, nil
+}
+
+func frozen(r *request) (interface{}, error) {
+	cli, err := r.server.PublicClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	return cli.FrozenCurrency()
+}
+
+func board(r *request) (interface{}, error) {
+	trading, settlement, err := r.pair()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := r.server.PublicClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	return cli.Board(trading, settlement)
+}
+
+func precision(r *request) (interface{}, error) {
+	trading, settlement, err := r.pair()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := r.server.PublicClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	p, err := cli.Precise(trading, settlement)
+	if err != nil {
+		return nil, err
+	}
+	return Precision{Price: p.PricePrecision, Amount: p.AmountPrecision}, nil
+}
+
+func balances(r *request) (interface{}, error) {
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	m, err := cli.CompleteBalances()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]Balance)
+	for currency, b := range m {
+		res[currency] = Balance{Available: b.Available, OnOrders: b.OnOrders}
+	}
+	return res, nil
+}
+
+func orders(r *request) (interface{}, error) {
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	active, err := cli.ActiveOrders()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Order, 0, len(active))
+	for _, o := range active {
+		side := "sell"
+		if o.Type == models.Bid {
+			side = "buy"
+		}
+		list = append(list, Order{
+			ID:         o.ExchangeOrderID,
+			Trading:    o.Trading,
+			Settlement: o.Settlement,
+			Side:       side,
+			Price:      o.Price,
+			Amount:     o.Amount,
+		})
+	}
+	return list, nil
+}
+
+func placeOrder(r *request) (interface{}, error) {
+	var o Order
+	if err := r.decode(&o); err != nil {
+		return nil, err
+	}
+	typ, err := r.side(o.Side)
+	if err != nil {
+		return nil, err
+	}
+	if o.Trading == "" || o.Settlement == "" || o.Price <= 0 || o.Amount <= 0 {
+		return nil, errorf(http.StatusBadRequest, "trading, settlement, price and amount are required")
+	}
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	o.ID, err = cli.Order(o.Trading, o.Settlement, typ, o.Price, o.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func cancelOrder(r *request) (interface{}, error) {
+	trading, settlement, err := r.pair()
+	if err != nil {
+		return nil, err
+	}
+	typ, err := r.side(r.URL.Query().Get("side"))
+	if err != nil {
+		return nil, err
+	}
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	if err := cli.CancelOrder(trading, settlement, typ, r.params["id"]); err != nil {
+		return nil, err
+	}
+	return map[string]string{"id": r.params["id"]}, nil
+}
+
+func filled(r *request) (interface{}, error) {
+	trading, settlement, err := r.pair()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := cli.IsOrderFilled(trading, settlement, r.params["id"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]bool{"filled": ok}, nil
+}
+
+func tradeFees(r *request) (interface{}, error) {
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	m, err := cli.TradeFeeRates()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]TradeFee, 0)
+	for trading, settlements := range m {
+		for settlement, f := range settlements {
+			list = append(list, TradeFee{Trading: trading, Settlement: settlement, Maker: f.MakerFee, Taker: f.TakerFee})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Trading+"/"+list[i].Settlement < list[j].Trading+"/"+list[j].Settlement
+	})
+	return list, nil
+}
+
+func transferFees(r *request) (interface{}, error) {
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	return cli.TransferFee()
+}
+
+func address(r *request) (interface{}, error) {
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := cli.Address(r.params["currency"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"currency": r.params["currency"], "address": addr}, nil
+}
+
+func withdraw(r *request) (interface{}, error) {
+	var w Withdrawal
+	if err := r.decode(&w); err != nil {
+		return nil, err
+	}
+	if w.Currency == "" || w.Address == "" || w.Amount <= 0 {
+		return nil, errorf(http.StatusBadRequest, "currency, address and amount are required")
+	}
+	if w.AdditionalFee < 0 {
+		return nil, errorf(http.StatusBadRequest, "additional_fee must not be negative")
+	}
+	cli, err := r.server.PrivateClient(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	if err := cli.Transfer(w.Currency, w.Address, w.Amount, w.AdditionalFee); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Grants which stand for groups of operations.
+const (
+	GrantAll     = "*"
+	GrantPublic  = "public"
+	GrantPrivate = "private"
+)
+
+// Token is a client of the gateway. The secret itself is read from $TokenEnv
+// so the file can be checked in.
+//
+//	tokens:
+//	  - name: dashboard
+//	    token_env: DASHBOARD_TOKEN
+//	    routes: [public, Balances]
+//	    instances: [binance-main]
+type Token struct {
+	Name     string `yaml:"name"`
+	TokenEnv string `yaml:"token_env"`
+	// Routes are the operations the token may call, as named by models.Operation,
+	// or one of GrantAll, GrantPublic and GrantPrivate.
+	Routes []string `yaml:"routes"`
+	// Instances are the config instances the token may use, every instance when empty.
+	Instances []string `yaml:"instances"`
+
+	secret string
+}
+
+// NewToken returns a token with the given secret, for callers which do not read env.
+func NewToken(name string, secret string, routes []string, instances []string) Token {
+	return Token{Name: name, Routes: routes, Instances: instances, secret: secret}
+}
+
+// LoadTokens reads a YAML token file and the secrets it refers to.
+func LoadTokens(path string) ([]Token, error) {
+	byteArray, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	var file struct {
+		Tokens []Token `yaml:"tokens"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(byteArray))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	for i := range file.Tokens {
+		t := &file.Tokens[i]
+		if t.Name == "" || t.TokenEnv == "" {
+			return nil, errors.Errorf("tokens[%d]: name and token_env are required", i)
+		}
+		t.secret = os.Getenv(t.TokenEnv)
+		if t.secret == "" {
+			return nil, errors.Errorf("%s: $%s is not set", t.Name, t.TokenEnv)
+		}
+	}
+	return file.Tokens, nil
+}
+
+func (t *Token) allows(op models.Operation, public bool, instance string) bool {
+	if len(t.Instances) != 0 && !contains(t.Instances, instance) {
+		return false
+	}
+	for _, r := range t.Routes {
+		switch {
+		case r == GrantAll,
+			r == GrantPublic && public,
+			r == GrantPrivate && !public,
+			r == string(op):
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
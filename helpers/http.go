@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strings"
@@ -50,8 +49,7 @@ func HttpGet(client *http.Client, reqUrl string) (map[string]interface{}, error)
 	var bodyDataMap map[string]interface{}
 	err = json.Unmarshal(respData, &bodyDataMap)
 	if err != nil {
-		log.Println(string(respData))
-		return nil, err
+		return nil, fmt.Errorf("failed to decode response of %d bytes: %s", len(respData), err)
 	}
 	return bodyDataMap, nil
 }
@@ -69,8 +67,7 @@ func HttpGet2(client *http.Client, reqUrl string, headers map[string]string) (ma
 	var bodyDataMap map[string]interface{}
 	err = json.Unmarshal(respData, &bodyDataMap)
 	if err != nil {
-		log.Println("respData", string(respData))
-		return nil, err
+		return nil, fmt.Errorf("failed to decode response of %d bytes: %s", len(respData), err)
 	}
 	return bodyDataMap, nil
 }
@@ -88,8 +85,7 @@ func HttpGet3(client *http.Client, reqUrl string, headers map[string]string) ([]
 	var bodyDataMap []interface{}
 	err = json.Unmarshal(respData, &bodyDataMap)
 	if err != nil {
-		log.Println("respData", string(respData))
-		return nil, err
+		return nil, fmt.Errorf("failed to decode response of %d bytes: %s", len(respData), err)
 	}
 	return bodyDataMap, nil
 }
@@ -106,8 +102,7 @@ func HttpGet4(client *http.Client, reqUrl string, headers map[string]string, res
 
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		log.Printf("HttpGet4 - json.Unmarshal failed : %v, resp %s", err, string(respData))
-		return err
+		return fmt.Errorf("failed to decode response of %d bytes: %s", len(respData), err)
 	}
 
 	return nil
@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"context"
+)
+
+// Warmup runs the loaders in order and returns early when ctx is done.
+// A loader which is already running is left to finish in the background,
+// so its result is still cached for later calls.
+func Warmup(ctx context.Context, loaders ...func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		for _, load := range loaders {
+			if err := ctx.Err(); err != nil {
+				done <- err
+				return
+			}
+			if err := load(); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
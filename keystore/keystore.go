@@ -0,0 +1,347 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	fileVersion = 1
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	saltSize  = 32
+	nonceSize = 24
+	keySize   = 32
+)
+
+var (
+	ErrNotFound          = errors.New("key not found")
+	ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted keystore")
+)
+
+// Credential is the key bundle of one exchange account.
+// Passphrase is only used by exchanges which require it (e.g. kucoin).
+// Environment is the environment the keys were issued for, empty for production.
+type Credential struct {
+	ApiKey      string `json:"api_key"`
+	SecretKey   string `json:"secret_key"`
+	Passphrase  string `json:"passphrase,omitempty"`
+	Environment string `json:"environment,omitempty"`
+}
+
+// Entry is a credential with the exchange and account it belongs to.
+type Entry struct {
+	Exchange   string     `json:"exchange"`
+	Account    string     `json:"account"`
+	Credential Credential `json:"credential"`
+}
+
+type sealedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Keystore holds exchange credentials in a file encrypted with a key
+// derived from a passphrase (scrypt + nacl/secretbox).
+type Keystore struct {
+	path       string
+	passphrase []byte
+	entries    map[string]map[string]Credential
+
+	m *sync.Mutex
+}
+
+// Open decrypts the keystore at path. If the file does not exist an empty
+// keystore is returned and the file is created on the first Save.
+func Open(path string, passphrase []byte) (*Keystore, error) {
+	ks := &Keystore{
+		path:       path,
+		passphrase: append([]byte{}, passphrase...),
+		entries:    make(map[string]map[string]Credential),
+		m:          new(sync.Mutex),
+	}
+	byteArray, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ks, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read keystore %s", path)
+	}
+	entries, err := decrypt(byteArray, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		ks.set(e.Exchange, e.Account, e.Credential)
+	}
+	return ks, nil
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func (ks *Keystore) set(exchange string, account string, c Credential) {
+	m, ok := ks.entries[normalize(exchange)]
+	if !ok {
+		m = make(map[string]Credential)
+		ks.entries[normalize(exchange)] = m
+	}
+	m[normalize(account)] = c
+}
+
+// Add stores or replaces the credential of exchange/account.
+func (ks *Keystore) Add(exchange string, account string, c Credential) error {
+	if normalize(exchange) == "" || normalize(account) == "" {
+		return errors.New("exchange and account are required")
+	}
+	if c.ApiKey == "" || c.SecretKey == "" {
+		return errors.New("api key and secret key are required")
+	}
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	ks.set(exchange, account, c)
+	return nil
+}
+
+// Remove deletes the credential of exchange/account.
+func (ks *Keystore) Remove(exchange string, account string) error {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	m, ok := ks.entries[normalize(exchange)]
+	if !ok {
+		return errors.Wrapf(ErrNotFound, "%s/%s", exchange, account)
+	}
+	if _, ok := m[normalize(account)]; !ok {
+		return errors.Wrapf(ErrNotFound, "%s/%s", exchange, account)
+	}
+	delete(m, normalize(account))
+	if len(m) == 0 {
+		delete(ks.entries, normalize(exchange))
+	}
+	return nil
+}
+
+// Get returns the credential of exchange/account.
+func (ks *Keystore) Get(exchange string, account string) (Credential, error) {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	c, ok := ks.entries[normalize(exchange)][normalize(account)]
+	if !ok {
+		return Credential{}, errors.Wrapf(ErrNotFound, "%s/%s", exchange, account)
+	}
+	return c, nil
+}
+
+// List returns every entry sorted by exchange and account with its secrets redacted.
+func (ks *Keystore) List() []Entry {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	entries := ks.entryList()
+	for i := range entries {
+		entries[i].Credential = Credential{
+			ApiKey:      Redact(entries[i].Credential.ApiKey),
+			SecretKey:   Redact(entries[i].Credential.SecretKey),
+			Passphrase:  Redact(entries[i].Credential.Passphrase),
+			Environment: entries[i].Credential.Environment,
+		}
+	}
+	return entries
+}
+
+func (ks *Keystore) entryList() []Entry {
+	entries := make([]Entry, 0)
+	for exchange, m := range ks.entries {
+		for account, c := range m {
+			entries = append(entries, Entry{Exchange: exchange, Account: account, Credential: c})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Exchange != entries[j].Exchange {
+			return entries[i].Exchange < entries[j].Exchange
+		}
+		return entries[i].Account < entries[j].Account
+	})
+	return entries
+}
+
+// Save encrypts the keystore and atomically replaces the file on disk.
+func (ks *Keystore) Save() error {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	byteArray, err := encrypt(ks.entryList(), ks.passphrase)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(ks.path)
+	tmp, err := ioutil.TempFile(dir, ".keystore-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to write keystore %s", ks.path)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "failed to write keystore %s", ks.path)
+	}
+	if _, err := tmp.Write(byteArray); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "failed to write keystore %s", ks.path)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write keystore %s", ks.path)
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), ks.path), "failed to write keystore %s", ks.path)
+}
+
+// ApiKeyFunc returns a callback usable as the apikey argument of private.NewClient.
+// For credentials with a passphrase it returns "passphrase::apikey", the format
+// expected by the kucoin client. The callback fails with
+// options.ErrEnvironmentMismatch unless the credential was issued for env;
+// with an empty env or options.Custom the check is left to the client options.
+func (ks *Keystore) ApiKeyFunc(exchange string, account string, env options.Environment) func() (string, error) {
+	return func() (string, error) {
+		c, err := ks.get(exchange, account, env)
+		if err != nil {
+			return "", err
+		}
+		if c.Passphrase != "" {
+			return c.Passphrase + "::" + c.ApiKey, nil
+		}
+		return c.ApiKey, nil
+	}
+}
+
+// SecretKeyFunc returns a callback usable as the seckey argument of private.NewClient.
+// The environment is checked as in ApiKeyFunc.
+func (ks *Keystore) SecretKeyFunc(exchange string, account string, env options.Environment) func() (string, error) {
+	return func() (string, error) {
+		c, err := ks.get(exchange, account, env)
+		if err != nil {
+			return "", err
+		}
+		return c.SecretKey, nil
+	}
+}
+
+func (ks *Keystore) get(exchange string, account string, env options.Environment) (Credential, error) {
+	c, err := ks.Get(exchange, account)
+	if err != nil {
+		return Credential{}, err
+	}
+	if env == "" || env == options.Custom {
+		return c, nil
+	}
+	credEnv := options.Environment(c.Environment)
+	if credEnv == "" {
+		credEnv = options.Production
+	}
+	if credEnv != env {
+		return Credential{}, errors.Wrapf(options.ErrEnvironmentMismatch, "%s/%s: %s credentials with %s endpoints", exchange, account, credEnv, env)
+	}
+	return c, nil
+}
+
+// Redact keeps the first and last 2 characters of s.
+func Redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:2] + strings.Repeat("*", len(s)-4) + s[len(s)-2:]
+}
+
+// deriveKey rejects scrypt parameters above the ones encrypt writes, so a
+// tampered file cannot make Open exhaust memory or cpu.
+func deriveKey(passphrase []byte, salt []byte, n int, r int, p int) (*[keySize]byte, error) {
+	if n > scryptN || r > scryptR || p > scryptP {
+		return nil, errors.Errorf("unsupported scrypt parameters n=%d r=%d p=%d", n, r, p)
+	}
+	k, err := scrypt.Key(passphrase, salt, n, r, p, keySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key")
+	}
+	var key [keySize]byte
+	copy(key[:], k)
+	return &key, nil
+}
+
+func encrypt(entries []Entry, passphrase []byte) ([]byte, error) {
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode keystore")
+	}
+	f := sealedFile{
+		Version: fileVersion,
+		KDF:     "scrypt",
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, saltSize),
+		Nonce:   make([]byte, nonceSize),
+	}
+	if _, err := io.ReadFull(rand.Reader, f.Salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	if _, err := io.ReadFull(rand.Reader, f.Nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	key, err := deriveKey(passphrase, f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], f.Nonce)
+	f.Ciphertext = secretbox.Seal(nil, plain, &nonce, key)
+	return json.MarshalIndent(f, "", "  ")
+}
+
+func decrypt(byteArray []byte, passphrase []byte) ([]Entry, error) {
+	var f sealedFile
+	if err := json.Unmarshal(byteArray, &f); err != nil {
+		return nil, errors.Wrap(err, "failed to parse keystore")
+	}
+	if f.Version != fileVersion || f.KDF != "scrypt" {
+		return nil, errors.Errorf("unsupported keystore version %d (%s)", f.Version, f.KDF)
+	}
+	if len(f.Nonce) != nonceSize {
+		return nil, ErrInvalidPassphrase
+	}
+	key, err := deriveKey(passphrase, f.Salt, f.N, f.R, f.P)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], f.Nonce)
+	plain, ok := secretbox.Open(nil, f.Ciphertext, &nonce, key)
+	if !ok {
+		return nil, ErrInvalidPassphrase
+	}
+	var entries []Entry
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to decode keystore")
+	}
+	return entries, nil
+}
@@ -0,0 +1,146 @@
+package keystore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	ks, err := Open(path, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Add("Binance", "main", Credential{ApiKey: "binance-api-key", SecretKey: "binance-secret-key"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Add("kucoin", "main", Credential{ApiKey: "kucoin-api-key", SecretKey: "kucoin-secret", Passphrase: "phrase"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	byteArray, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(byteArray), "binance-secret-key") {
+		t.Errorf("Keystore: secret is stored in plaintext")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Keystore: Expected mode 0600. Got %v", info.Mode().Perm())
+	}
+
+	if _, err := Open(path, []byte("wrong")); errors.Cause(err) != ErrInvalidPassphrase {
+		t.Errorf("Keystore: Expected %v. Got %v", ErrInvalidPassphrase, err)
+	}
+
+	ks, err = Open(path, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiKey, err := ks.ApiKeyFunc("binance", "main", options.Production)()
+	if err != nil || apiKey != "binance-api-key" {
+		t.Errorf("Keystore: Expected %v. Got %v %v", "binance-api-key", apiKey, err)
+	}
+	secKey, err := ks.SecretKeyFunc("BINANCE", "Main", "")()
+	if err != nil || secKey != "binance-secret-key" {
+		t.Errorf("Keystore: Expected %v. Got %v %v", "binance-secret-key", secKey, err)
+	}
+	apiKey, err = ks.ApiKeyFunc("kucoin", "main", "")()
+	if err != nil || apiKey != "phrase::kucoin-api-key" {
+		t.Errorf("Keystore: Expected %v. Got %v %v", "phrase::kucoin-api-key", apiKey, err)
+	}
+	if _, err := ks.SecretKeyFunc("kucoin", "main", options.Testnet)(); errors.Cause(err) != options.ErrEnvironmentMismatch {
+		t.Errorf("Keystore: Expected %v. Got %v", options.ErrEnvironmentMismatch, err)
+	}
+
+	list := ks.List()
+	if len(list) != 2 || list[0].Exchange != "binance" || list[1].Exchange != "kucoin" {
+		t.Fatalf("Keystore: unexpected list %v", list)
+	}
+	if list[0].Credential.SecretKey == "binance-secret-key" {
+		t.Errorf("Keystore: List must redact secrets")
+	}
+
+	if err := ks.Remove("binance", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.ApiKeyFunc("binance", "main", options.Production)(); errors.Cause(err) != ErrNotFound {
+		t.Errorf("Keystore: Expected %v. Got %v", ErrNotFound, err)
+	}
+	if err := ks.Remove("binance", "main"); errors.Cause(err) != ErrNotFound {
+		t.Errorf("Keystore: Expected %v. Got %v", ErrNotFound, err)
+	}
+}
+
+func TestKeystoreScryptParams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	ks, err := Open(path, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+	byteArray, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, params := range []map[string]int{{"n": 1 << 30}, {"r": 1 << 20}, {"p": 1 << 20}} {
+		var f map[string]interface{}
+		if err := json.Unmarshal(byteArray, &f); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range params {
+			f[k] = v
+		}
+		tampered, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, tampered, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(path, []byte("correct horse")); err == nil || !strings.Contains(err.Error(), "unsupported scrypt parameters") {
+			t.Errorf("Keystore: Expected an error for %v. Got %v", params, err)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		"":             "",
+		"short":        "*****",
+		"0123456789ab": "01********ab",
+	}
+	for in, expected := range cases {
+		if got := Redact(in); got != expected {
+			t.Errorf("Redact(%q): Expected %q. Got %q", in, expected, got)
+		}
+	}
+}
@@ -1,27 +1,37 @@
+// Package logger holds the logger used when a client is not given one.
+// It discards everything until Set is called.
 package logger
 
 import (
+	"sync/atomic"
+
 	"go.uber.org/zap"
-	"sync"
 )
 
-var (
-	logger *zap.Logger
-	sugar  *zap.SugaredLogger
-	mtx    sync.Mutex
-)
+var sugar atomic.Value
+
+func init() {
+	sugar.Store(zap.NewNop().Sugar())
+}
 
+// Get returns the package default logger.
 func Get() *zap.SugaredLogger {
-	mtx.Lock()
-	defer mtx.Unlock()
+	return sugar.Load().(*zap.SugaredLogger)
+}
+
+// Set replaces the package default logger. nil restores the no-op logger.
+func Set(l *zap.SugaredLogger) {
+	if l == nil {
+		l = zap.NewNop().Sugar()
+	}
+	sugar.Store(l)
+}
 
-	if logger == nil {
-		lg, err := zap.NewDevelopment()
-		if err != nil {
-			panic(err)
-		}
-		logger = lg
-		sugar = lg.Sugar()
+// Development returns the zap development logger the package used to default to.
+func Development() *zap.SugaredLogger {
+	lg, err := zap.NewDevelopment()
+	if err != nil {
+		panic(err)
 	}
-	return sugar
+	return lg.Sugar()
 }
@@ -1,7 +1,55 @@
 package logger
 
-import "testing"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
 
 func TestLogger(t *testing.T) {
-	_ = Get()
+	if Get().Desugar().Core().Enabled(zap.ErrorLevel) {
+		t.Error("the default logger must discard everything")
+	}
+
+	l := Development()
+	Set(l)
+	if Get() != l {
+		t.Error("Set is not applied")
+	}
+	Set(nil)
+	if Get() == nil || Get().Desugar().Core().Enabled(zap.ErrorLevel) {
+		t.Error("Set(nil) must restore the no-op logger")
+	}
+}
+
+func TestFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromSlog(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	l.Debugw("dropped")
+	l.With("exchange", "binance").Warnw("request failed", "endpoint", "/api/v1/depth", "status", 429,
+		"latency", time.Second, "error", errors.New("too many requests"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected exactly one entry, got %q", buf.String())
+	}
+	for k, want := range map[string]interface{}{
+		"level":    "WARN",
+		"msg":      "request failed",
+		"exchange": "binance",
+		"endpoint": "/api/v1/depth",
+		"status":   float64(429),
+		"latency":  float64(time.Second),
+		"error":    "too many requests",
+	} {
+		if entry[k] != want {
+			t.Errorf("%s: expected %v, got %v", k, want, entry[k])
+		}
+	}
 }
@@ -1,21 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/xuyangcn/go-exchange-client/api/multi"
 	"github.com/xuyangcn/go-exchange-client/api/public"
 )
 
 func main() {
 	exchanges := []string{"huobi", "okex", "poloniex", "kucoin", "hitbtc", "binance"}
+	m := multi.New(10 * time.Second)
 	for _, e := range exchanges {
 		cli, err := public.NewClient(e)
 		if err != nil {
 			fmt.Println(err)
-			panic(err)
+			continue
 		}
-		fmt.Println(cli.OrderBookTickMap())
-		fmt.Println(cli.Precise("ZRX", "BTC"))
+		m.AddPublic(e, cli)
+	}
+	res := m.OrderBookTickMap(context.Background())
+	for name, ticks := range res.OrderBookTicks {
+		fmt.Println(name, ticks["ZRX"]["BTC"])
+	}
+	for name, err := range res.Errors {
+		fmt.Println(name, err)
 	}
 	/*cli, _ := unified.NewShrimpyApi()
 	fmt.Println(cli.GetBoards("bittrex"))
@@ -0,0 +1,188 @@
+// Package metrics records Prometheus metrics of the requests clients send.
+//
+//	m, err := metrics.New(prometheus.DefaultRegisterer)
+//	cli, err := public.NewClient("binance", m.Option())
+//
+// The cache hit ratio of an exchange is
+//
+//	sum(rate(exchange_cache_lookups_total{result="hit"}[5m])) by (exchange)
+//	  / sum(rate(exchange_cache_lookups_total[5m])) by (exchange)
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+// Error classes of the exchange_errors_total metric.
+const (
+	ClassTimeout     = "timeout"
+	ClassCanceled    = "canceled"
+	ClassNetwork     = "network"
+	ClassRateLimited = "rate_limited"
+	ClassClient      = "client"
+	ClassServer      = "server"
+)
+
+// Metrics are the collectors shared by every client it is applied to.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	headroom *prometheus.GaugeVec
+	cache    *prometheus.CounterVec
+
+	// Endpoint turns a request into the endpoint label. The default keeps the
+	// path and replaces symbols and ids in it so the label stays bounded.
+	Endpoint func(*http.Request) string
+}
+
+// New creates the collectors and registers them with reg.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	labels := []string{"exchange", "endpoint", "scope"}
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "exchange_requests_total",
+			Help: "Requests sent to exchanges by status code, \"error\" when no response was received.",
+		}, append(labels, "code")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "exchange_request_duration_seconds",
+			Help:    "Latency of requests sent to exchanges.",
+			Buckets: []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "exchange_errors_total",
+			Help: "Failed requests by error class.",
+		}, append(labels, "class")),
+		headroom: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "exchange_rate_limit_headroom",
+			Help: "Requests left before the rate limit, as seen by the local limiter (source=\"local\") or reported by the exchange (source=\"exchange\").",
+		}, []string{"exchange", "scope", "source"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "exchange_cache_lookups_total",
+			Help: "Lookups of the client caches by result.",
+		}, []string{"exchange", "scope", "cache", "result"}),
+		Endpoint: Endpoint,
+	}
+	for _, c := range []prometheus.Collector{m.requests, m.duration, m.errors, m.headroom, m.cache} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "failed to register metrics")
+		}
+	}
+	return m, nil
+}
+
+// Option instruments a client with m.
+func (m *Metrics) Option() options.Option {
+	return func(o *options.Options) {
+		options.WithMiddleware(m.Middleware)(o)
+		options.WithObserver(m)(o)
+	}
+}
+
+// Middleware is an options.Middleware recording every request sent through next.
+func (m *Metrics) Middleware(o *options.Options, next http.RoundTripper) http.RoundTripper {
+	return &transport{m: m, o: o, next: next}
+}
+
+// ObserveCache implements options.Observer.
+func (m *Metrics) ObserveCache(o *options.Options, cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cache.WithLabelValues(o.Exchange, scope(o), cache, result).Inc()
+}
+
+type transport struct {
+	m    *Metrics
+	o    *options.Options
+	next http.RoundTripper
+}
+
+// tokenLimiter is satisfied by *rate.Limiter.
+type tokenLimiter interface {
+	Tokens() float64
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange, sc, endpoint := t.o.Exchange, scope(t.o), t.m.Endpoint(req)
+	if l, ok := t.o.RateLimiter.(tokenLimiter); ok {
+		t.m.headroom.WithLabelValues(exchange, sc, "local").Set(l.Tokens())
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	t.m.duration.WithLabelValues(exchange, endpoint, sc).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if res != nil {
+		code = strconv.Itoa(res.StatusCode)
+		if v, perr := strconv.ParseFloat(res.Header.Get("X-RateLimit-Remaining"), 64); perr == nil {
+			t.m.headroom.WithLabelValues(exchange, sc, "exchange").Set(v)
+		}
+	}
+	t.m.requests.WithLabelValues(exchange, endpoint, sc, code).Inc()
+	if class := Classify(res, err); class != "" {
+		t.m.errors.WithLabelValues(exchange, endpoint, sc, class).Inc()
+	}
+	return res, err
+}
+
+func scope(o *options.Options) string {
+	if o.Private {
+		return "private"
+	}
+	return "public"
+}
+
+// Classify returns the error class of a request, or "" if it succeeded.
+func Classify(res *http.Response, err error) string {
+	if err != nil {
+		var nerr net.Error
+		switch {
+		case errors.Is(err, context.Canceled):
+			return ClassCanceled
+		case errors.Is(err, context.DeadlineExceeded), errors.As(err, &nerr) && nerr.Timeout():
+			return ClassTimeout
+		}
+		return ClassNetwork
+	}
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode == 418:
+		// binance answers 418 to clients which kept going after a 429
+		return ClassRateLimited
+	case res.StatusCode >= 500:
+		return ClassServer
+	case res.StatusCode >= 400:
+		return ClassClient
+	}
+	return ""
+}
+
+var (
+	symbolSegment = regexp.MustCompile(`^[A-Z0-9]+([-_/][A-Z0-9]+)?$`)
+	idSegment     = regexp.MustCompile(`[0-9].*[0-9].*[0-9].*[0-9]`)
+)
+
+// Endpoint is the default endpoint label: the request path with every segment
+// which looks like a symbol or an id replaced by ":param".
+// Query strings are dropped.
+func Endpoint(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i, s := range segments {
+		if strings.ContainsAny(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") && symbolSegment.MatchString(s) || idSegment.MatchString(s) {
+			segments[i] = ":param"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+)
+
+// value returns the value of the metric with the given labels, or -1.
+func value(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) float64 {
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	next:
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if v, ok := labels[l.GetName()]; ok && v != l.GetValue() {
+					continue next
+				}
+			}
+			return metricValue(m)
+		}
+	}
+	return -1
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	case m.Histogram != nil:
+		return float64(m.Histogram.GetSampleCount())
+	}
+	return -1
+}
+
+func TestMetrics(t *testing.T) {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/exchangeInfo":
+			w.Header().Set("X-RateLimit-Remaining", "42")
+			fmt.Fprint(w, `{"symbols":[{"symbol":"ETHBTC","baseAsset":"ETH","quoteAsset":"BTC"}]}`)
+		case "/api/v1/depth":
+			fmt.Fprint(w, `{"bids":[["0.029","1.5"]],"asks":[["0.031","2"]]}`)
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer fake.Close()
+
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := public.NewClient("binance", options.WithBaseURL(fake.URL), m.Option())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.CurrencyPairs(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cli.Board("ETH", "BTC"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cli.Rate("ETH", "BTC")
+
+	binance := map[string]string{"exchange": "binance", "scope": "public"}
+	with := func(kv ...string) map[string]string {
+		labels := map[string]string{}
+		for k, v := range binance {
+			labels[k] = v
+		}
+		for i := 0; i < len(kv); i += 2 {
+			labels[kv[i]] = kv[i+1]
+		}
+		return labels
+	}
+	if v := value(t, reg, "exchange_requests_total", with("endpoint", "/api/v1/exchangeInfo", "code", "200")); v != 1 {
+		t.Errorf("expected 1 exchangeInfo request, got %v", v)
+	}
+	if v := value(t, reg, "exchange_request_duration_seconds", with("endpoint", "/api/v1/depth")); v != 1 {
+		t.Errorf("expected 1 depth observation, got %v", v)
+	}
+	if v := value(t, reg, "exchange_errors_total", with("endpoint", "/api/v1/ticker/24hr", "class", ClassRateLimited)); v != 1 {
+		t.Errorf("expected 1 rate limited error, got %v", v)
+	}
+	if v := value(t, reg, "exchange_rate_limit_headroom", with("source", "exchange")); v != 42 {
+		t.Errorf("expected headroom 42, got %v", v)
+	}
+	if v := value(t, reg, "exchange_cache_lookups_total", with("cache", "board", "result", "hit")); v != 1 {
+		t.Errorf("expected 1 board cache hit, got %v", v)
+	}
+	if v := value(t, reg, "exchange_cache_lookups_total", with("cache", "board", "result", "miss")); v != 1 {
+		t.Errorf("expected 1 board cache miss, got %v", v)
+	}
+
+	if _, err := New(reg); err == nil {
+		t.Error("expected an error for registering twice")
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	for path, want := range map[string]string{
+		"/api/v1/depth":                     "/api/v1/depth",
+		"/api/2/public/orderbook/ETHBTC":    "/api/2/public/orderbook/:param",
+		"/api/v1/ticker/24hr":               "/api/v1/ticker/24hr",
+		"/api/v1/market/orderbook/ETH-BTC":  "/api/v1/market/orderbook/:param",
+		"/wapi/v3/assetDetail.html":         "/wapi/v3/assetDetail.html",
+		"/api/v3/order/596186ad07015679730": "/api/v3/order/:param",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com"+path+"?symbol=ETHBTC", nil)
+		if got := Endpoint(req); got != want {
+			t.Errorf("%s: expected %s, got %s", path, want, got)
+		}
+	}
+}
@@ -18,61 +18,80 @@ type BoardBar struct {
 	Amount float64   `json:"amount"`
 }
 
+// Board is the order book of a pair. Its methods never modify it, so a board
+// can be read by several goroutines at once.
 type Board struct {
 	Asks []BoardBar `json:"asks"`
 	Bids []BoardBar `json:"bids"`
 }
 
+// Copy returns a board with its own slices.
+func (b *Board) Copy() *Board {
+	return &Board{
+		Asks: append([]BoardBar(nil), b.Asks...),
+		Bids: append([]BoardBar(nil), b.Bids...),
+	}
+}
+
+func (b *Board) bestBid() BoardBar {
+	best := b.Bids[0]
+	for _, v := range b.Bids[1:] {
+		if v.Price > best.Price {
+			best = v
+		}
+	}
+	return best
+}
+
+func (b *Board) bestAsk() BoardBar {
+	best := b.Asks[0]
+	for _, v := range b.Asks[1:] {
+		if v.Price < best.Price {
+			best = v
+		}
+	}
+	return best
+}
+
 func (b *Board) BestBidAmount() float64 {
 	if len(b.Bids) == 0 {
 		return 0
 	}
-	sort.Slice(b.Bids, func(i, j int) bool {
-		return b.Bids[i].Price > b.Bids[j].Price
-	})
-	return b.Bids[0].Amount
+	return b.bestBid().Amount
 }
 
 func (b *Board) BestAskAmount() float64 {
 	if len(b.Asks) == 0 {
 		return 0
 	}
-	sort.Slice(b.Asks, func(i, j int) bool {
-		return b.Asks[i].Price < b.Asks[j].Price
-	})
-	return b.Asks[0].Amount
+	return b.bestAsk().Amount
 }
 
 func (b *Board) BestBidPrice() float64 {
 	if len(b.Bids) == 0 {
 		return 0
 	}
-	sort.Slice(b.Bids, func(i, j int) bool {
-		return b.Bids[i].Price > b.Bids[j].Price
-	})
-	return b.Bids[0].Price
+	return b.bestBid().Price
 }
 
 func (b *Board) BestAskPrice() float64 {
 	if len(b.Asks) == 0 {
 		return 0
 	}
-	sort.Slice(b.Asks, func(i, j int) bool {
-		return b.Asks[i].Price < b.Asks[j].Price
-	})
-	return b.Asks[0].Price
+	return b.bestAsk().Price
 }
 
 func (b *Board) AverageBidRate(amount float64) (float64, error) {
 	if len(b.Bids) == 0 {
 		return 0, errors.New("there is no bids")
 	}
-	sort.Slice(b.Bids, func(i, j int) bool {
-		return b.Bids[i].Price < b.Bids[j].Price
+	bars := append([]BoardBar(nil), b.Bids...)
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].Price < bars[j].Price
 	})
 	var sum float64
 	remainingAmount := amount
-	for _, v := range b.Bids {
+	for _, v := range bars {
 		if v.Amount > remainingAmount {
 			sum += remainingAmount * v.Price
 			return sum / amount, nil
@@ -88,12 +107,13 @@ func (b *Board) AverageAskRate(amount float64) (float64, error) {
 	if len(b.Asks) == 0 {
 		return 0, errors.New("there is no asks")
 	}
-	sort.Slice(b.Asks, func(i, j int) bool {
-		return b.Asks[i].Price < b.Asks[j].Price
+	bars := append([]BoardBar(nil), b.Asks...)
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].Price < bars[j].Price
 	})
 	var sum float64
 	remainingAmount := amount
-	for _, v := range b.Asks {
+	for _, v := range bars {
 		if v.Amount > remainingAmount {
 			sum += remainingAmount * v.Price
 			return sum / amount, nil
@@ -0,0 +1,158 @@
+package models
+
+// Operation is a method of PublicClient or PrivateClient.
+type Operation string
+
+const (
+	OpCurrencyPairs    Operation = "CurrencyPairs"
+	OpRate             Operation = "Rate"
+	OpOrderBookTickMap Operation = "OrderBookTickMap"
+	OpSnapshot         Operation = "Snapshot"
+	OpFrozenCurrency   Operation = "FrozenCurrency"
+	OpBoard            Operation = "Board"
+	OpPrecise          Operation = "Precise"
+
+	OpTransferFee      Operation = "TransferFee"
+	OpTradeFeeRates    Operation = "TradeFeeRates"
+	OpBalances         Operation = "Balances"
+	OpCompleteBalances Operation = "CompleteBalances"
+	OpActiveOrders     Operation = "ActiveOrders"
+	OpIsOrderFilled    Operation = "IsOrderFilled"
+	OpOrder            Operation = "Order"
+	OpCancelOrder      Operation = "CancelOrder"
+	OpTransfer         Operation = "Transfer"
+	OpAddress          Operation = "Address"
+)
+
+// PublicOperations are the operations of PublicClient.
+var PublicOperations = []Operation{
+	OpCurrencyPairs, OpRate, OpOrderBookTickMap, OpSnapshot, OpFrozenCurrency, OpBoard, OpPrecise,
+}
+
+// PrivateOperations are the operations of PrivateClient.
+var PrivateOperations = []Operation{
+	OpTransferFee, OpTradeFeeRates, OpBalances, OpCompleteBalances, OpActiveOrders,
+	OpIsOrderFilled, OpOrder, OpCancelOrder, OpTransfer, OpAddress,
+}
+
+type TimeInForce string
+
+const (
+	GTC TimeInForce = "GTC"
+	IOC TimeInForce = "IOC"
+	FOK TimeInForce = "FOK"
+)
+
+// Channel is a streaming data channel.
+type Channel string
+
+const (
+	ChannelTicker    Channel = "ticker"
+	ChannelOrderBook Channel = "orderbook"
+	ChannelTrades    Channel = "trades"
+	ChannelOrders    Channel = "orders"
+)
+
+// Capabilities describes what a client supports, so callers can choose
+// exchanges and code paths before calling them.
+type Capabilities struct {
+	Exchange    string
+	Operations  []Operation
+	OrderTypes  []OrderType
+	TimeInForce []TimeInForce
+	// WithdrawalNetworks maps a currency to the networks Transfer can use.
+	// Currencies which are not listed are sent on the exchange default network.
+	WithdrawalNetworks map[string][]string
+	Channels           []Channel
+}
+
+// Except returns a copy of operations without ops.
+func Except(operations []Operation, ops ...Operation) []Operation {
+	ret := make([]Operation, 0, len(operations))
+	for _, o := range operations {
+		excluded := false
+		for _, e := range ops {
+			if o == e {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			ret = append(ret, o)
+		}
+	}
+	return ret
+}
+
+func (c Capabilities) Supports(op Operation) bool {
+	for _, o := range c.Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Capabilities) SupportsOrderType(t OrderType) bool {
+	for _, o := range c.OrderTypes {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Capabilities) SupportsTimeInForce(t TimeInForce) bool {
+	for _, o := range c.TimeInForce {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Capabilities) SupportsChannel(ch Channel) bool {
+	for _, o := range c.Channels {
+		if o == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge combines the capabilities of the public and private client of an exchange.
+func (c Capabilities) Merge(o Capabilities) Capabilities {
+	ret := Capabilities{Exchange: c.Exchange}
+	if ret.Exchange == "" {
+		ret.Exchange = o.Exchange
+	}
+	for _, op := range append(append([]Operation{}, c.Operations...), o.Operations...) {
+		if !ret.Supports(op) {
+			ret.Operations = append(ret.Operations, op)
+		}
+	}
+	for _, t := range append(append([]OrderType{}, c.OrderTypes...), o.OrderTypes...) {
+		if !ret.SupportsOrderType(t) {
+			ret.OrderTypes = append(ret.OrderTypes, t)
+		}
+	}
+	for _, t := range append(append([]TimeInForce{}, c.TimeInForce...), o.TimeInForce...) {
+		if !ret.SupportsTimeInForce(t) {
+			ret.TimeInForce = append(ret.TimeInForce, t)
+		}
+	}
+	for _, ch := range append(append([]Channel{}, c.Channels...), o.Channels...) {
+		if !ret.SupportsChannel(ch) {
+			ret.Channels = append(ret.Channels, ch)
+		}
+	}
+	for _, m := range []map[string][]string{c.WithdrawalNetworks, o.WithdrawalNetworks} {
+		for currency, networks := range m {
+			if ret.WithdrawalNetworks == nil {
+				ret.WithdrawalNetworks = make(map[string][]string)
+			}
+			ret.WithdrawalNetworks[currency] = append(ret.WithdrawalNetworks[currency], networks...)
+		}
+	}
+	return ret
+}
@@ -1,10 +1,31 @@
 package models
 
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 type CurrencyPair struct {
 	Trading    string `json:"trading"`
 	Settlement string `json:"settlement"`
 }
 
+// String returns the canonical symbol of the pair, BASE/QUOTE.
+func (c CurrencyPair) String() string {
+	return c.Trading + "/" + c.Settlement
+}
+
+// ParseCurrencyPair parses a canonical symbol such as "BTC/JPY". Currencies
+// are upper cased.
+func ParseCurrencyPair(s string) (CurrencyPair, error) {
+	xs := strings.Split(s, "/")
+	if len(xs) != 2 || xs[0] == "" || xs[1] == "" {
+		return CurrencyPair{}, errors.Errorf("invalid symbol %q, want BASE/QUOTE", s)
+	}
+	return CurrencyPair{Trading: strings.ToUpper(xs[0]), Settlement: strings.ToUpper(xs[1])}, nil
+}
+
 type Asset struct {
 	Name   string // "Bitcoin"
 	Symbol string // "BTC"
@@ -5,3 +5,93 @@ import "testing"
 func TestNewBalance(t *testing.T) {
 	_ = NewBalance(0.1, 0.05)
 }
+
+func TestCapabilities(t *testing.T) {
+	pub := Capabilities{
+		Exchange:   "bitflyer",
+		Operations: Except(PublicOperations, OpFrozenCurrency),
+	}
+	if pub.Supports(OpFrozenCurrency) {
+		t.Errorf("Capabilities: FrozenCurrency must be excluded")
+	}
+	if !pub.Supports(OpBoard) {
+		t.Errorf("Capabilities: Board must be supported")
+	}
+	priv := Capabilities{
+		Exchange:           "bitflyer",
+		Operations:         Except(PrivateOperations, OpTransfer, OpAddress),
+		OrderTypes:         []OrderType{Ask, Bid},
+		TimeInForce:        []TimeInForce{GTC},
+		WithdrawalNetworks: map[string][]string{"USDT": {"ERC20"}},
+	}
+	all := pub.Merge(priv)
+	if all.Exchange != "bitflyer" {
+		t.Errorf("Capabilities: Expected bitflyer. Got %s", all.Exchange)
+	}
+	if !all.Supports(OpBoard) || !all.Supports(OpOrder) || all.Supports(OpTransfer) {
+		t.Errorf("Capabilities: unexpected operations %v", all.Operations)
+	}
+	if !all.SupportsOrderType(Bid) || all.SupportsOrderType(AskMarket) {
+		t.Errorf("Capabilities: unexpected order types %v", all.OrderTypes)
+	}
+	if !all.SupportsTimeInForce(GTC) || all.SupportsTimeInForce(IOC) {
+		t.Errorf("Capabilities: unexpected time in force %v", all.TimeInForce)
+	}
+	if len(all.WithdrawalNetworks["USDT"]) != 1 || all.SupportsChannel(ChannelTicker) {
+		t.Errorf("Capabilities: unexpected networks or channels %v %v", all.WithdrawalNetworks, all.Channels)
+	}
+	ops := Except(PublicOperations)
+	ops[0] = OpTransfer
+	if PublicOperations[0] == OpTransfer {
+		t.Errorf("Capabilities: Except must copy the operations")
+	}
+}
+
+func TestBoard(t *testing.T) {
+	b := &Board{
+		Asks: []BoardBar{{Price: 102, Amount: 1}, {Price: 101, Amount: 2}, {Price: 103, Amount: 3}},
+		Bids: []BoardBar{{Price: 98, Amount: 1}, {Price: 99, Amount: 2}, {Price: 97, Amount: 3}},
+	}
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 100; j++ {
+				if b.BestAskPrice() != 101 || b.BestAskAmount() != 2 || b.BestBidPrice() != 99 || b.BestBidAmount() != 2 {
+					t.Errorf("Board: unexpected best prices")
+					return
+				}
+				if rate, err := b.AverageAskRate(3); err != nil || rate != (2*101+102)/3.0 {
+					t.Errorf("Board: unexpected average ask rate %v, %v", rate, err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+	if b.Asks[0].Price != 102 || b.Bids[0].Price != 98 {
+		t.Errorf("Board: board was modified %v", b)
+	}
+	c := b.Copy()
+	c.Asks[0].Price = 0
+	if b.Asks[0].Price != 102 {
+		t.Errorf("Board: copy shares its bars")
+	}
+}
+
+func TestCurrencyPair(t *testing.T) {
+	p, err := ParseCurrencyPair("btc/jpy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != (CurrencyPair{Trading: "BTC", Settlement: "JPY"}) || p.String() != "BTC/JPY" {
+		t.Errorf("got %s; want BTC/JPY", p)
+	}
+	for _, s := range []string{"BTC_JPY", "BTC/", "BTC/JPY/ETH"} {
+		if _, err := ParseCurrencyPair(s); err == nil {
+			t.Errorf("ParseCurrencyPair(%q): want an error", s)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// MarketSnapshot is the market data of an exchange as of one fetch. The maps
+// are keyed by trading and then settlement currency and belong to the caller.
+type MarketSnapshot struct {
+	Rates   map[string]map[string]float64
+	Volumes map[string]map[string]float64
+	// OrderBookTicks are the best prices the tickers carry, empty for
+	// exchanges whose tickers have none.
+	OrderBookTicks map[string]map[string]OrderBookTick
+	Time           time.Time
+}
@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/logger"
+)
+
+// CheckHealth sends a GET to target through every proxy. Proxies which
+// answer with a status below 500 become healthy, the others are left out
+// until a later check passes.
+func (p *Pool) CheckHealth(ctx context.Context, target string) {
+	p.mu.Lock()
+	proxies := append([]*entry(nil), p.proxies...)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range proxies {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			healthy := p.check(ctx, e, target)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			e.healthy = healthy
+			if healthy {
+				e.failures = 0
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+func (p *Pool) check(ctx context.Context, e *entry, target string) bool {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	res, err := p.transport.RoundTrip(req.WithContext(context.WithValue(ctx, contextKey{}, e)))
+	if err != nil {
+		return false
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	return res.StatusCode < http.StatusInternalServerError
+}
+
+// Run loads Source and checks the health of the proxies every interval until
+// ctx is done. The current proxies are kept when Source fails.
+func (p *Pool) Run(ctx context.Context, target string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Load(ctx); err != nil {
+			logger.Get().Warnw("failed to load proxies", "error", err)
+		}
+		p.CheckHealth(ctx, target)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,375 @@
+// Package proxy sends the requests of clients through a pool of proxies,
+// leaving out the ones which are down or banned by an exchange.
+//
+//	pool, err := proxy.New([]string{"http://proxy1:3128", "http://proxy2:3128"})
+//	pool.Sticky = true
+//	go pool.Run(ctx, "https://api.binance.com/api/v1/ping", time.Minute)
+//	cli, err := public.NewClient("binance", pool.Option())
+//
+// A proxy banned by one exchange keeps serving the others. It is tried again
+// for that exchange after BanDuration, twice as long after every ban in a row.
+//
+// Proxies are URLs with the http, https, socks5 or socks5h scheme, with
+// credentials as user:pass@host when the proxy needs them.
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+// ErrNoProxy is returned when every proxy is unhealthy or banned by the exchange.
+var ErrNoProxy = errors.New("no proxy available")
+
+// Pool picks the proxy of every request.
+type Pool struct {
+	// BanDuration is how long a proxy is left out for an exchange after a ban.
+	// It doubles with every ban in a row up to MaxBanDuration. A Retry-After
+	// header takes precedence.
+	BanDuration    time.Duration
+	MaxBanDuration time.Duration
+	// MaxFailures is the number of network errors in a row after which a
+	// proxy is left out until a health check passes. 0 never leaves it out.
+	MaxFailures int
+	// Sticky sends the requests of an exchange through the same proxy as long
+	// as it is usable.
+	Sticky bool
+	// Banned tells whether a response means the exchange banned or rate
+	// limited the proxy. body is only read for error statuses.
+	Banned func(res *http.Response, body []byte) bool
+	// Source, if set, is loaded again by Run before every health check.
+	Source Source
+
+	mu        sync.Mutex
+	proxies   []*entry
+	sticky    map[string]*entry
+	rules     map[string]map[string]bool
+	next      int
+	transport *http.Transport
+}
+
+type entry struct {
+	url      *url.URL
+	healthy  bool
+	failures int
+	bans     map[string]*ban
+
+	successes    int
+	totalFailure int
+	totalBans    int
+}
+
+type ban struct {
+	until time.Time
+	count int
+}
+
+// Stats are the counters of one proxy.
+type Stats struct {
+	URL       string
+	Healthy   bool
+	Successes int
+	Failures  int
+	Bans      int
+	// BannedBy are the exchanges the proxy is left out for.
+	BannedBy []string
+}
+
+// New returns a pool of the proxies, all of them healthy until checked.
+func New(proxies []string) (*Pool, error) {
+	p := &Pool{
+		BanDuration:    time.Minute,
+		MaxBanDuration: time.Hour,
+		MaxFailures:    3,
+		Banned:         Banned,
+		sticky:         make(map[string]*entry),
+		rules:          make(map[string]map[string]bool),
+	}
+	if err := p.set(proxies); err != nil {
+		return nil, err
+	}
+	p.transport = http.DefaultTransport.(*http.Transport).Clone()
+	p.transport.Proxy = p.proxyOf
+	return p, nil
+}
+
+// NewFromSource returns a pool of the proxies listed by src, which Run keeps loading.
+func NewFromSource(ctx context.Context, src Source) (*Pool, error) {
+	proxies, err := src.Proxies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, err := New(proxies)
+	if err != nil {
+		return nil, err
+	}
+	p.Source = src
+	return p, nil
+}
+
+// Load replaces the proxies with the ones listed by Source. Proxies which
+// were already in the pool keep their state.
+func (p *Pool) Load(ctx context.Context) error {
+	if p.Source == nil {
+		return nil
+	}
+	proxies, err := p.Source.Proxies(ctx)
+	if err != nil {
+		return err
+	}
+	return p.set(proxies)
+}
+
+func (p *Pool) set(proxies []string) error {
+	if len(proxies) == 0 {
+		return errors.New("no proxies")
+	}
+	urls := make([]*url.URL, 0, len(proxies))
+	for i, s := range proxies {
+		u, err := ParseURL(s)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse proxy %d", i+1)
+		}
+		urls = append(urls, u)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := make(map[string]*entry, len(p.proxies))
+	for _, e := range p.proxies {
+		old[e.url.String()] = e
+	}
+	entries := make([]*entry, 0, len(urls))
+	kept := make(map[*entry]bool)
+	for _, u := range urls {
+		e, ok := old[u.String()]
+		if !ok {
+			e = &entry{url: u, healthy: true, bans: make(map[string]*ban)}
+		}
+		kept[e] = true
+		entries = append(entries, e)
+	}
+	for exchange, e := range p.sticky {
+		if !kept[e] {
+			delete(p.sticky, exchange)
+		}
+	}
+	p.proxies = entries
+	p.next = 0
+	return nil
+}
+
+// Assign restricts the requests of the exchange to the given proxies, which
+// are matched by host and port.
+func (p *Pool) Assign(exchange string, proxies ...string) error {
+	hosts := make(map[string]bool, len(proxies))
+	for i, s := range proxies {
+		u, err := ParseURL(s)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse proxy %d", i+1)
+		}
+		hosts[u.Host] = true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules[exchange] = hosts
+	delete(p.sticky, exchange)
+	return nil
+}
+
+// Option sends the requests of a client through the pool. It replaces the
+// http client, so it must come after WithHTTPClient if both are given.
+func (p *Pool) Option() options.Option {
+	return func(o *options.Options) {
+		options.WithHTTPClient(&http.Client{Transport: p.Transport()})(o)
+		options.WithMiddleware(p.Middleware)(o)
+	}
+}
+
+// Transport returns the transport connecting through the proxy Middleware
+// picked, or through any usable one for requests which didn't go through it.
+func (p *Pool) Transport() http.RoundTripper {
+	return p.transport
+}
+
+type contextKey struct{}
+
+func (p *Pool) proxyOf(req *http.Request) (*url.URL, error) {
+	if e, ok := req.Context().Value(contextKey{}).(*entry); ok {
+		return e.url, nil
+	}
+	e, err := p.pick("")
+	if err != nil {
+		return nil, err
+	}
+	return e.url, nil
+}
+
+// Middleware is an options.Middleware picking the proxy of each request and
+// recording how it went.
+func (p *Pool) Middleware(o *options.Options, next http.RoundTripper) http.RoundTripper {
+	return &transport{p: p, exchange: o.Exchange, next: next}
+}
+
+type transport struct {
+	p        *Pool
+	exchange string
+	next     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e, err := t.p.pick(t.exchange)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to send %s", req.URL.Path)
+	}
+	res, err := t.next.RoundTrip(req.WithContext(context.WithValue(req.Context(), contextKey{}, e)))
+	if err != nil {
+		t.p.report(e, t.exchange, nil, false)
+		return nil, err
+	}
+	var body []byte
+	if res.StatusCode >= 400 {
+		body, err = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.p.report(e, t.exchange, nil, false)
+			return nil, err
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	t.p.report(e, t.exchange, res, t.p.Banned(res, body))
+	return res, nil
+}
+
+var binanceBan = regexp.MustCompile(`"code"\s*:\s*-1003\b`)
+
+// Banned is the default of Pool.Banned. It treats 418, 429 and Binance's
+// -1003 (too many requests, IP banned) as bans.
+func Banned(res *http.Response, body []byte) bool {
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusTeapot:
+		return true
+	}
+	return binanceBan.Match(body)
+}
+
+func (p *Pool) pick(exchange string) (*entry, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	hosts, ruled := p.rules[exchange]
+	if p.Sticky {
+		if e, ok := p.sticky[exchange]; ok && e.usable(exchange, now) {
+			return e, nil
+		}
+	}
+	for i := range p.proxies {
+		e := p.proxies[(p.next+i)%len(p.proxies)]
+		if ruled && !hosts[e.url.Host] {
+			continue
+		}
+		if e.usable(exchange, now) {
+			p.next = (p.next + i + 1) % len(p.proxies)
+			if p.Sticky {
+				p.sticky[exchange] = e
+			}
+			return e, nil
+		}
+	}
+	return nil, ErrNoProxy
+}
+
+func (e *entry) usable(exchange string, now time.Time) bool {
+	if !e.healthy {
+		return false
+	}
+	b, ok := e.bans[exchange]
+	return !ok || !now.Before(b.until)
+}
+
+// report records the outcome of a request; res is nil for network errors.
+func (p *Pool) report(e *entry, exchange string, res *http.Response, banned bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case res == nil:
+		e.totalFailure++
+		e.failures++
+		if p.MaxFailures > 0 && e.failures >= p.MaxFailures {
+			e.healthy = false
+		}
+	case banned:
+		e.failures = 0
+		e.totalBans++
+		b, ok := e.bans[exchange]
+		if !ok {
+			b = &ban{}
+			e.bans[exchange] = b
+		}
+		b.count++
+		d := retryAfter(res)
+		if d == 0 {
+			d = p.banDuration(b.count)
+		}
+		b.until = time.Now().Add(d)
+	default:
+		e.failures = 0
+		e.successes++
+		delete(e.bans, exchange)
+	}
+}
+
+func (p *Pool) banDuration(count int) time.Duration {
+	d := p.BanDuration
+	for i := 1; i < count && (p.MaxBanDuration == 0 || d < p.MaxBanDuration); i++ {
+		d *= 2
+	}
+	if p.MaxBanDuration != 0 && d > p.MaxBanDuration {
+		d = p.MaxBanDuration
+	}
+	return d
+}
+
+func retryAfter(res *http.Response) time.Duration {
+	if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return 0
+}
+
+// Stats returns the counters of every proxy in the order they were given.
+// Credentials in the proxy URLs are masked.
+func (p *Pool) Stats() []Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	stats := make([]Stats, 0, len(p.proxies))
+	for _, e := range p.proxies {
+		s := Stats{
+			URL:       e.url.Redacted(),
+			Healthy:   e.healthy,
+			Successes: e.successes,
+			Failures:  e.totalFailure,
+			Bans:      e.totalBans,
+		}
+		for exchange, b := range e.bans {
+			if now.Before(b.until) {
+				s.BannedBy = append(s.BannedBy, exchange)
+			}
+		}
+		sort.Strings(s.BannedBy)
+		stats = append(stats, s)
+	}
+	return stats
+}
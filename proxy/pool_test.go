@@ -0,0 +1,175 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/api/options"
+)
+
+// fakeProxy answers every request itself and counts them.
+type fakeProxy struct {
+	*httptest.Server
+	mu    sync.Mutex
+	calls int
+}
+
+func newFakeProxy(status int, body string) *fakeProxy {
+	p := &fakeProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.calls++
+		p.mu.Unlock()
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	return p
+}
+
+func (p *fakeProxy) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func newClient(t *testing.T, pool *Pool, exchange string) *http.Client {
+	o, err := options.New(options.Options{Exchange: exchange}, pool.Option())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o.NewHttpClient()
+}
+
+func get(t *testing.T, cli *http.Client) (int, error) {
+	res, err := cli.Get("http://exchange.invalid/api/v1/depth")
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+	return res.StatusCode, nil
+}
+
+func TestBan(t *testing.T) {
+	banned := newFakeProxy(http.StatusForbidden, `{"code":-1003,"msg":"Way too many requests; IP banned"}`)
+	defer banned.Close()
+	ok := newFakeProxy(http.StatusOK, `{}`)
+	defer ok.Close()
+
+	pool, err := New([]string{banned.URL, ok.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := newClient(t, pool, "binance")
+	for i := 0; i < 4; i++ {
+		get(t, cli)
+	}
+	if banned.count() != 1 || ok.count() != 3 {
+		t.Errorf("expected the banned proxy to be left out, got %d and %d calls", banned.count(), ok.count())
+	}
+	stats := pool.Stats()
+	if stats[0].Bans != 1 || len(stats[0].BannedBy) != 1 || stats[0].BannedBy[0] != "binance" || stats[1].Successes != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// other exchanges keep using it
+	used := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		e, err := pool.pick("huobi")
+		if err != nil {
+			t.Fatal(err)
+		}
+		used[e.url.String()] = true
+	}
+	if !used[banned.URL] {
+		t.Error("a proxy banned by binance must still serve huobi")
+	}
+
+	pool.report(pool.proxies[1], "binance", &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"30"}}}, true)
+	if _, err := get(t, cli); !errors.Is(err, ErrNoProxy) {
+		t.Errorf("expected %v, got %v", ErrNoProxy, err)
+	}
+	if until := pool.proxies[1].bans["binance"].until; time.Until(until) < 29*time.Second {
+		t.Errorf("Retry-After is not applied: %v", until)
+	}
+}
+
+func TestSticky(t *testing.T) {
+	a := newFakeProxy(http.StatusOK, `{}`)
+	defer a.Close()
+	b := newFakeProxy(http.StatusOK, `{}`)
+	defer b.Close()
+
+	pool, err := New([]string{a.URL, b.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Sticky = true
+	binance := newClient(t, pool, "binance")
+	huobi := newClient(t, pool, "huobi")
+	for i := 0; i < 3; i++ {
+		get(t, binance)
+		get(t, huobi)
+	}
+	if a.count() != 3 || b.count() != 3 {
+		t.Errorf("expected each exchange to stick to one proxy, got %d and %d calls", a.count(), b.count())
+	}
+}
+
+func TestHealth(t *testing.T) {
+	dead := newFakeProxy(http.StatusOK, `{}`)
+	dead.Close()
+	failing := newFakeProxy(http.StatusBadGateway, ``)
+	defer failing.Close()
+	ok := newFakeProxy(http.StatusOK, `{}`)
+	defer ok.Close()
+
+	pool, err := New([]string{dead.URL, failing.URL, ok.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.CheckHealth(context.Background(), "http://exchange.invalid/api/v1/ping")
+	stats := pool.Stats()
+	if stats[0].Healthy || stats[1].Healthy || !stats[2].Healthy {
+		t.Errorf("unexpected health %+v", stats)
+	}
+	cli := newClient(t, pool, "binance")
+	for i := 0; i < 3; i++ {
+		if status, err := get(t, cli); err != nil || status != http.StatusOK {
+			t.Errorf("expected the healthy proxy, got %d %v", status, err)
+		}
+	}
+}
+
+func TestFailures(t *testing.T) {
+	dead := newFakeProxy(http.StatusOK, `{}`)
+	dead.Close()
+	pool, err := New([]string{dead.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.MaxFailures = 2
+	cli := newClient(t, pool, "binance")
+	for i := 0; i < 2; i++ {
+		if _, err := get(t, cli); err == nil {
+			t.Fatal("expected a network error")
+		}
+	}
+	if stats := pool.Stats(); stats[0].Healthy || stats[0].Failures != 2 {
+		t.Errorf("expected the proxy to be left out after 2 failures, got %+v", stats)
+	}
+}
+
+func TestBanDuration(t *testing.T) {
+	pool := &Pool{BanDuration: time.Minute, MaxBanDuration: 5 * time.Minute}
+	for count, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 4: 5 * time.Minute, 20: 5 * time.Minute} {
+		if got := pool.banDuration(count); got != want {
+			t.Errorf("ban %d: expected %v, got %v", count, want, got)
+		}
+	}
+}
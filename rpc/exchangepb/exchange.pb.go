@@ -0,0 +1,1912 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: exchange.proto
+
+// Package exchange mirrors api/public.PublicClient and api/private.PrivateClient.
+// Every request names the config instance it is sent to.
+
+package exchangepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OrderType is required, orders which leave it out are rejected.
+type OrderType int32
+
+const (
+	OrderType_ORDER_TYPE_UNSPECIFIED OrderType = 0
+	OrderType_ASK                    OrderType = 1
+	OrderType_BID                    OrderType = 2
+	OrderType_ASK_MARKET             OrderType = 3
+)
+
+// Enum value maps for OrderType.
+var (
+	OrderType_name = map[int32]string{
+		0: "ORDER_TYPE_UNSPECIFIED",
+		1: "ASK",
+		2: "BID",
+		3: "ASK_MARKET",
+	}
+	OrderType_value = map[string]int32{
+		"ORDER_TYPE_UNSPECIFIED": 0,
+		"ASK":                    1,
+		"BID":                    2,
+		"ASK_MARKET":             3,
+	}
+)
+
+func (x OrderType) Enum() *OrderType {
+	p := new(OrderType)
+	*p = x
+	return p
+}
+
+func (x OrderType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderType) Descriptor() protoreflect.EnumDescriptor {
+	return file_exchange_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderType) Type() protoreflect.EnumType {
+	return &file_exchange_proto_enumTypes[0]
+}
+
+func (x OrderType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderType.Descriptor instead.
+func (OrderType) EnumDescriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{0}
+}
+
+type CurrencyPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trading       string                 `protobuf:"bytes,1,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,2,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrencyPair) Reset() {
+	*x = CurrencyPair{}
+	mi := &file_exchange_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyPair) ProtoMessage() {}
+
+func (x *CurrencyPair) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyPair.ProtoReflect.Descriptor instead.
+func (*CurrencyPair) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CurrencyPair) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *CurrencyPair) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+type OrderBookTick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trading       string                 `protobuf:"bytes,1,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,2,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	BestAskPrice  float64                `protobuf:"fixed64,3,opt,name=best_ask_price,json=bestAskPrice,proto3" json:"best_ask_price,omitempty"`
+	BestAskAmount float64                `protobuf:"fixed64,4,opt,name=best_ask_amount,json=bestAskAmount,proto3" json:"best_ask_amount,omitempty"`
+	BestBidPrice  float64                `protobuf:"fixed64,5,opt,name=best_bid_price,json=bestBidPrice,proto3" json:"best_bid_price,omitempty"`
+	BestBidAmount float64                `protobuf:"fixed64,6,opt,name=best_bid_amount,json=bestBidAmount,proto3" json:"best_bid_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBookTick) Reset() {
+	*x = OrderBookTick{}
+	mi := &file_exchange_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBookTick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBookTick) ProtoMessage() {}
+
+func (x *OrderBookTick) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBookTick.ProtoReflect.Descriptor instead.
+func (*OrderBookTick) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderBookTick) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *OrderBookTick) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+func (x *OrderBookTick) GetBestAskPrice() float64 {
+	if x != nil {
+		return x.BestAskPrice
+	}
+	return 0
+}
+
+func (x *OrderBookTick) GetBestAskAmount() float64 {
+	if x != nil {
+		return x.BestAskAmount
+	}
+	return 0
+}
+
+func (x *OrderBookTick) GetBestBidPrice() float64 {
+	if x != nil {
+		return x.BestBidPrice
+	}
+	return 0
+}
+
+func (x *OrderBookTick) GetBestBidAmount() float64 {
+	if x != nil {
+		return x.BestBidAmount
+	}
+	return 0
+}
+
+type BoardBar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoardBar) Reset() {
+	*x = BoardBar{}
+	mi := &file_exchange_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoardBar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardBar) ProtoMessage() {}
+
+func (x *BoardBar) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardBar.ProtoReflect.Descriptor instead.
+func (*BoardBar) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BoardBar) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *BoardBar) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type Board struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trading       string                 `protobuf:"bytes,1,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,2,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	Asks          []*BoardBar            `protobuf:"bytes,3,rep,name=asks,proto3" json:"asks,omitempty"`
+	Bids          []*BoardBar            `protobuf:"bytes,4,rep,name=bids,proto3" json:"bids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Board) Reset() {
+	*x = Board{}
+	mi := &file_exchange_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Board) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Board) ProtoMessage() {}
+
+func (x *Board) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Board.ProtoReflect.Descriptor instead.
+func (*Board) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Board) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *Board) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+func (x *Board) GetAsks() []*BoardBar {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *Board) GetBids() []*BoardBar {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+type Precisions struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PricePrecision  int32                  `protobuf:"varint,1,opt,name=price_precision,json=pricePrecision,proto3" json:"price_precision,omitempty"`
+	AmountPrecision int32                  `protobuf:"varint,2,opt,name=amount_precision,json=amountPrecision,proto3" json:"amount_precision,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Precisions) Reset() {
+	*x = Precisions{}
+	mi := &file_exchange_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Precisions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Precisions) ProtoMessage() {}
+
+func (x *Precisions) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Precisions.ProtoReflect.Descriptor instead.
+func (*Precisions) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Precisions) GetPricePrecision() int32 {
+	if x != nil {
+		return x.PricePrecision
+	}
+	return 0
+}
+
+func (x *Precisions) GetAmountPrecision() int32 {
+	if x != nil {
+		return x.AmountPrecision
+	}
+	return 0
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Available     float64                `protobuf:"fixed64,2,opt,name=available,proto3" json:"available,omitempty"`
+	OnOrders      float64                `protobuf:"fixed64,3,opt,name=on_orders,json=onOrders,proto3" json:"on_orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_exchange_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Balance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Balance) GetAvailable() float64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *Balance) GetOnOrders() float64 {
+	if x != nil {
+		return x.OnOrders
+	}
+	return 0
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          OrderType              `protobuf:"varint,2,opt,name=type,proto3,enum=exchange.v1.OrderType" json:"type,omitempty"`
+	Trading       string                 `protobuf:"bytes,3,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,4,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Amount        float64                `protobuf:"fixed64,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_exchange_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetType() OrderType {
+	if x != nil {
+		return x.Type
+	}
+	return OrderType_ORDER_TYPE_UNSPECIFIED
+}
+
+func (x *Order) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *Order) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+func (x *Order) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Order) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TradeFee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trading       string                 `protobuf:"bytes,1,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,2,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	MakerFee      float64                `protobuf:"fixed64,3,opt,name=maker_fee,json=makerFee,proto3" json:"maker_fee,omitempty"`
+	TakerFee      float64                `protobuf:"fixed64,4,opt,name=taker_fee,json=takerFee,proto3" json:"taker_fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradeFee) Reset() {
+	*x = TradeFee{}
+	mi := &file_exchange_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeFee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeFee) ProtoMessage() {}
+
+func (x *TradeFee) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeFee.ProtoReflect.Descriptor instead.
+func (*TradeFee) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TradeFee) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *TradeFee) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+func (x *TradeFee) GetMakerFee() float64 {
+	if x != nil {
+		return x.MakerFee
+	}
+	return 0
+}
+
+func (x *TradeFee) GetTakerFee() float64 {
+	if x != nil {
+		return x.TakerFee
+	}
+	return 0
+}
+
+type InstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceRequest) Reset() {
+	*x = InstanceRequest{}
+	mi := &file_exchange_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceRequest) ProtoMessage() {}
+
+func (x *InstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceRequest.ProtoReflect.Descriptor instead.
+func (*InstanceRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *InstanceRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+type PairRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Trading       string                 `protobuf:"bytes,2,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,3,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PairRequest) Reset() {
+	*x = PairRequest{}
+	mi := &file_exchange_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PairRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PairRequest) ProtoMessage() {}
+
+func (x *PairRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PairRequest.ProtoReflect.Descriptor instead.
+func (*PairRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PairRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *PairRequest) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *PairRequest) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+type CurrencyPairsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []*CurrencyPair        `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrencyPairsResponse) Reset() {
+	*x = CurrencyPairsResponse{}
+	mi := &file_exchange_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyPairsResponse) ProtoMessage() {}
+
+func (x *CurrencyPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyPairsResponse.ProtoReflect.Descriptor instead.
+func (*CurrencyPairsResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CurrencyPairsResponse) GetPairs() []*CurrencyPair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type RateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          float64                `protobuf:"fixed64,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateResponse) Reset() {
+	*x = RateResponse{}
+	mi := &file_exchange_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateResponse) ProtoMessage() {}
+
+func (x *RateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateResponse.ProtoReflect.Descriptor instead.
+func (*RateResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RateResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+type OrderBookTicksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticks         []*OrderBookTick       `protobuf:"bytes,1,rep,name=ticks,proto3" json:"ticks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBookTicksResponse) Reset() {
+	*x = OrderBookTicksResponse{}
+	mi := &file_exchange_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBookTicksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBookTicksResponse) ProtoMessage() {}
+
+func (x *OrderBookTicksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBookTicksResponse.ProtoReflect.Descriptor instead.
+func (*OrderBookTicksResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OrderBookTicksResponse) GetTicks() []*OrderBookTick {
+	if x != nil {
+		return x.Ticks
+	}
+	return nil
+}
+
+type FrozenCurrencyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []string               `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FrozenCurrencyResponse) Reset() {
+	*x = FrozenCurrencyResponse{}
+	mi := &file_exchange_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FrozenCurrencyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrozenCurrencyResponse) ProtoMessage() {}
+
+func (x *FrozenCurrencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrozenCurrencyResponse.ProtoReflect.Descriptor instead.
+func (*FrozenCurrencyResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FrozenCurrencyResponse) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type WatchBoardRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Instance   string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Trading    string                 `protobuf:"bytes,2,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement string                 `protobuf:"bytes,3,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	// interval_ms is how often the board is polled, 1000 when zero
+	// and at least 100.
+	// Unchanged boards are not sent.
+	IntervalMs    int64 `protobuf:"varint,4,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchBoardRequest) Reset() {
+	*x = WatchBoardRequest{}
+	mi := &file_exchange_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchBoardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBoardRequest) ProtoMessage() {}
+
+func (x *WatchBoardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBoardRequest.ProtoReflect.Descriptor instead.
+func (*WatchBoardRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchBoardRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *WatchBoardRequest) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *WatchBoardRequest) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+func (x *WatchBoardRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type WatchTickersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	IntervalMs    int64                  `protobuf:"varint,2,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTickersRequest) Reset() {
+	*x = WatchTickersRequest{}
+	mi := &file_exchange_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTickersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTickersRequest) ProtoMessage() {}
+
+func (x *WatchTickersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTickersRequest.ProtoReflect.Descriptor instead.
+func (*WatchTickersRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchTickersRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *WatchTickersRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type TransferFeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fees          map[string]float64     `protobuf:"bytes,1,rep,name=fees,proto3" json:"fees,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferFeeResponse) Reset() {
+	*x = TransferFeeResponse{}
+	mi := &file_exchange_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferFeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferFeeResponse) ProtoMessage() {}
+
+func (x *TransferFeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferFeeResponse.ProtoReflect.Descriptor instead.
+func (*TransferFeeResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TransferFeeResponse) GetFees() map[string]float64 {
+	if x != nil {
+		return x.Fees
+	}
+	return nil
+}
+
+type TradeFeeRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fees          []*TradeFee            `protobuf:"bytes,1,rep,name=fees,proto3" json:"fees,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TradeFeeRatesResponse) Reset() {
+	*x = TradeFeeRatesResponse{}
+	mi := &file_exchange_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeFeeRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeFeeRatesResponse) ProtoMessage() {}
+
+func (x *TradeFeeRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeFeeRatesResponse.ProtoReflect.Descriptor instead.
+func (*TradeFeeRatesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TradeFeeRatesResponse) GetFees() []*TradeFee {
+	if x != nil {
+		return x.Fees
+	}
+	return nil
+}
+
+type BalancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      []*Balance             `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalancesResponse) Reset() {
+	*x = BalancesResponse{}
+	mi := &file_exchange_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalancesResponse) ProtoMessage() {}
+
+func (x *BalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalancesResponse.ProtoReflect.Descriptor instead.
+func (*BalancesResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BalancesResponse) GetBalances() []*Balance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type OrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrdersResponse) Reset() {
+	*x = OrdersResponse{}
+	mi := &file_exchange_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrdersResponse) ProtoMessage() {}
+
+func (x *OrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrdersResponse.ProtoReflect.Descriptor instead.
+func (*OrdersResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type OrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Trading       string                 `protobuf:"bytes,2,opt,name=trading,proto3" json:"trading,omitempty"`
+	Settlement    string                 `protobuf:"bytes,3,opt,name=settlement,proto3" json:"settlement,omitempty"`
+	Id            string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderRequest) Reset() {
+	*x = OrderRequest{}
+	mi := &file_exchange_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderRequest) ProtoMessage() {}
+
+func (x *OrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderRequest.ProtoReflect.Descriptor instead.
+func (*OrderRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OrderRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetTrading() string {
+	if x != nil {
+		return x.Trading
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetSettlement() string {
+	if x != nil {
+		return x.Settlement
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type IsOrderFilledResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filled        bool                   `protobuf:"varint,1,opt,name=filled,proto3" json:"filled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsOrderFilledResponse) Reset() {
+	*x = IsOrderFilledResponse{}
+	mi := &file_exchange_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsOrderFilledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsOrderFilledResponse) ProtoMessage() {}
+
+func (x *IsOrderFilledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsOrderFilledResponse.ProtoReflect.Descriptor instead.
+func (*IsOrderFilledResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *IsOrderFilledResponse) GetFilled() bool {
+	if x != nil {
+		return x.Filled
+	}
+	return false
+}
+
+type PlaceOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_exchange_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PlaceOrderRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_exchange_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CancelOrderRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_exchange_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{24}
+}
+
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	AdditionalFee float64                `protobuf:"fixed64,5,opt,name=additional_fee,json=additionalFee,proto3" json:"additional_fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_exchange_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TransferRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetAdditionalFee() float64 {
+	if x != nil {
+		return x.AdditionalFee
+	}
+	return 0
+}
+
+type TransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_exchange_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResponse) ProtoMessage() {}
+
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{26}
+}
+
+type AddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressRequest) Reset() {
+	*x = AddressRequest{}
+	mi := &file_exchange_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressRequest) ProtoMessage() {}
+
+func (x *AddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressRequest.ProtoReflect.Descriptor instead.
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AddressRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *AddressRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type AddressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressResponse) Reset() {
+	*x = AddressResponse{}
+	mi := &file_exchange_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressResponse) ProtoMessage() {}
+
+func (x *AddressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressResponse.ProtoReflect.Descriptor instead.
+func (*AddressResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AddressResponse) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+var File_exchange_proto protoreflect.FileDescriptor
+
+const file_exchange_proto_rawDesc = "" +
+	"\n" +
+	"\x0eexchange.proto\x12\vexchange.v1\"H\n" +
+	"\fCurrencyPair\x12\x18\n" +
+	"\atrading\x18\x01 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x02 \x01(\tR\n" +
+	"settlement\"\xe5\x01\n" +
+	"\rOrderBookTick\x12\x18\n" +
+	"\atrading\x18\x01 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x02 \x01(\tR\n" +
+	"settlement\x12$\n" +
+	"\x0ebest_ask_price\x18\x03 \x01(\x01R\fbestAskPrice\x12&\n" +
+	"\x0fbest_ask_amount\x18\x04 \x01(\x01R\rbestAskAmount\x12$\n" +
+	"\x0ebest_bid_price\x18\x05 \x01(\x01R\fbestBidPrice\x12&\n" +
+	"\x0fbest_bid_amount\x18\x06 \x01(\x01R\rbestBidAmount\"8\n" +
+	"\bBoardBar\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\"\x97\x01\n" +
+	"\x05Board\x12\x18\n" +
+	"\atrading\x18\x01 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x02 \x01(\tR\n" +
+	"settlement\x12)\n" +
+	"\x04asks\x18\x03 \x03(\v2\x15.exchange.v1.BoardBarR\x04asks\x12)\n" +
+	"\x04bids\x18\x04 \x03(\v2\x15.exchange.v1.BoardBarR\x04bids\"`\n" +
+	"\n" +
+	"Precisions\x12'\n" +
+	"\x0fprice_precision\x18\x01 \x01(\x05R\x0epricePrecision\x12)\n" +
+	"\x10amount_precision\x18\x02 \x01(\x05R\x0famountPrecision\"`\n" +
+	"\aBalance\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\tavailable\x18\x02 \x01(\x01R\tavailable\x12\x1b\n" +
+	"\ton_orders\x18\x03 \x01(\x01R\bonOrders\"\xab\x01\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x16.exchange.v1.OrderTypeR\x04type\x12\x18\n" +
+	"\atrading\x18\x03 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x04 \x01(\tR\n" +
+	"settlement\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x01R\x06amount\"~\n" +
+	"\bTradeFee\x12\x18\n" +
+	"\atrading\x18\x01 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x02 \x01(\tR\n" +
+	"settlement\x12\x1b\n" +
+	"\tmaker_fee\x18\x03 \x01(\x01R\bmakerFee\x12\x1b\n" +
+	"\ttaker_fee\x18\x04 \x01(\x01R\btakerFee\"-\n" +
+	"\x0fInstanceRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\"c\n" +
+	"\vPairRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x18\n" +
+	"\atrading\x18\x02 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x03 \x01(\tR\n" +
+	"settlement\"H\n" +
+	"\x15CurrencyPairsResponse\x12/\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x19.exchange.v1.CurrencyPairR\x05pairs\"\"\n" +
+	"\fRateResponse\x12\x12\n" +
+	"\x04rate\x18\x01 \x01(\x01R\x04rate\"J\n" +
+	"\x16OrderBookTicksResponse\x120\n" +
+	"\x05ticks\x18\x01 \x03(\v2\x1a.exchange.v1.OrderBookTickR\x05ticks\"8\n" +
+	"\x16FrozenCurrencyResponse\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\tR\n" +
+	"currencies\"\x8a\x01\n" +
+	"\x11WatchBoardRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x18\n" +
+	"\atrading\x18\x02 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x03 \x01(\tR\n" +
+	"settlement\x12\x1f\n" +
+	"\vinterval_ms\x18\x04 \x01(\x03R\n" +
+	"intervalMs\"R\n" +
+	"\x13WatchTickersRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x1f\n" +
+	"\vinterval_ms\x18\x02 \x01(\x03R\n" +
+	"intervalMs\"\x8e\x01\n" +
+	"\x13TransferFeeResponse\x12>\n" +
+	"\x04fees\x18\x01 \x03(\v2*.exchange.v1.TransferFeeResponse.FeesEntryR\x04fees\x1a7\n" +
+	"\tFeesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"B\n" +
+	"\x15TradeFeeRatesResponse\x12)\n" +
+	"\x04fees\x18\x01 \x03(\v2\x15.exchange.v1.TradeFeeR\x04fees\"D\n" +
+	"\x10BalancesResponse\x120\n" +
+	"\bbalances\x18\x01 \x03(\v2\x14.exchange.v1.BalanceR\bbalances\"<\n" +
+	"\x0eOrdersResponse\x12*\n" +
+	"\x06orders\x18\x01 \x03(\v2\x12.exchange.v1.OrderR\x06orders\"t\n" +
+	"\fOrderRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x18\n" +
+	"\atrading\x18\x02 \x01(\tR\atrading\x12\x1e\n" +
+	"\n" +
+	"settlement\x18\x03 \x01(\tR\n" +
+	"settlement\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\"/\n" +
+	"\x15IsOrderFilledResponse\x12\x16\n" +
+	"\x06filled\x18\x01 \x01(\bR\x06filled\"Y\n" +
+	"\x11PlaceOrderRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12(\n" +
+	"\x05order\x18\x02 \x01(\v2\x12.exchange.v1.OrderR\x05order\"Z\n" +
+	"\x12CancelOrderRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12(\n" +
+	"\x05order\x18\x02 \x01(\v2\x12.exchange.v1.OrderR\x05order\"\x15\n" +
+	"\x13CancelOrderResponse\"\xa2\x01\n" +
+	"\x0fTransferRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12%\n" +
+	"\x0eadditional_fee\x18\x05 \x01(\x01R\radditionalFee\"\x12\n" +
+	"\x10TransferResponse\"H\n" +
+	"\x0eAddressRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"+\n" +
+	"\x0fAddressResponse\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress*I\n" +
+	"\tOrderType\x12\x1a\n" +
+	"\x16ORDER_TYPE_UNSPECIFIED\x10\x00\x12\a\n" +
+	"\x03ASK\x10\x01\x12\a\n" +
+	"\x03BID\x10\x02\x12\x0e\n" +
+	"\n" +
+	"ASK_MARKET\x10\x032\xdb\x04\n" +
+	"\rPublicService\x12Q\n" +
+	"\rCurrencyPairs\x12\x1c.exchange.v1.InstanceRequest\x1a\".exchange.v1.CurrencyPairsResponse\x12;\n" +
+	"\x04Rate\x12\x18.exchange.v1.PairRequest\x1a\x19.exchange.v1.RateResponse\x12S\n" +
+	"\x0eOrderBookTicks\x12\x1c.exchange.v1.InstanceRequest\x1a#.exchange.v1.OrderBookTicksResponse\x12S\n" +
+	"\x0eFrozenCurrency\x12\x1c.exchange.v1.InstanceRequest\x1a#.exchange.v1.FrozenCurrencyResponse\x125\n" +
+	"\x05Board\x12\x18.exchange.v1.PairRequest\x1a\x12.exchange.v1.Board\x12<\n" +
+	"\aPrecise\x12\x18.exchange.v1.PairRequest\x1a\x17.exchange.v1.Precisions\x12B\n" +
+	"\n" +
+	"WatchBoard\x12\x1e.exchange.v1.WatchBoardRequest\x1a\x12.exchange.v1.Board0\x01\x12W\n" +
+	"\fWatchTickers\x12 .exchange.v1.WatchTickersRequest\x1a#.exchange.v1.OrderBookTicksResponse0\x012\xb9\x05\n" +
+	"\x0ePrivateService\x12M\n" +
+	"\vTransferFee\x12\x1c.exchange.v1.InstanceRequest\x1a .exchange.v1.TransferFeeResponse\x12Q\n" +
+	"\rTradeFeeRates\x12\x1c.exchange.v1.InstanceRequest\x1a\".exchange.v1.TradeFeeRatesResponse\x12G\n" +
+	"\bBalances\x12\x1c.exchange.v1.InstanceRequest\x1a\x1d.exchange.v1.BalancesResponse\x12I\n" +
+	"\fActiveOrders\x12\x1c.exchange.v1.InstanceRequest\x1a\x1b.exchange.v1.OrdersResponse\x12N\n" +
+	"\rIsOrderFilled\x12\x19.exchange.v1.OrderRequest\x1a\".exchange.v1.IsOrderFilledResponse\x12@\n" +
+	"\n" +
+	"PlaceOrder\x12\x1e.exchange.v1.PlaceOrderRequest\x1a\x12.exchange.v1.Order\x12P\n" +
+	"\vCancelOrder\x12\x1f.exchange.v1.CancelOrderRequest\x1a .exchange.v1.CancelOrderResponse\x12G\n" +
+	"\bTransfer\x12\x1c.exchange.v1.TransferRequest\x1a\x1d.exchange.v1.TransferResponse\x12D\n" +
+	"\aAddress\x12\x1b.exchange.v1.AddressRequest\x1a\x1c.exchange.v1.AddressResponseBBZ@github.com/xuyangcn/go-exchange-client/rpc/exchangepb;exchangepbb\x06proto3"
+
+var (
+	file_exchange_proto_rawDescOnce sync.Once
+	file_exchange_proto_rawDescData []byte
+)
+
+func file_exchange_proto_rawDescGZIP() []byte {
+	file_exchange_proto_rawDescOnce.Do(func() {
+		file_exchange_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_exchange_proto_rawDesc), len(file_exchange_proto_rawDesc)))
+	})
+	return file_exchange_proto_rawDescData
+}
+
+var file_exchange_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_exchange_proto_goTypes = []any{
+	(OrderType)(0),                 // 0: exchange.v1.OrderType
+	(*CurrencyPair)(nil),           // 1: exchange.v1.CurrencyPair
+	(*OrderBookTick)(nil),          // 2: exchange.v1.OrderBookTick
+	(*BoardBar)(nil),               // 3: exchange.v1.BoardBar
+	(*Board)(nil),                  // 4: exchange.v1.Board
+	(*Precisions)(nil),             // 5: exchange.v1.Precisions
+	(*Balance)(nil),                // 6: exchange.v1.Balance
+	(*Order)(nil),                  // 7: exchange.v1.Order
+	(*TradeFee)(nil),               // 8: exchange.v1.TradeFee
+	(*InstanceRequest)(nil),        // 9: exchange.v1.InstanceRequest
+	(*PairRequest)(nil),            // 10: exchange.v1.PairRequest
+	(*CurrencyPairsResponse)(nil),  // 11: exchange.v1.CurrencyPairsResponse
+	(*RateResponse)(nil),           // 12: exchange.v1.RateResponse
+	(*OrderBookTicksResponse)(nil), // 13: exchange.v1.OrderBookTicksResponse
+	(*FrozenCurrencyResponse)(nil), // 14: exchange.v1.FrozenCurrencyResponse
+	(*WatchBoardRequest)(nil),      // 15: exchange.v1.WatchBoardRequest
+	(*WatchTickersRequest)(nil),    // 16: exchange.v1.WatchTickersRequest
+	(*TransferFeeResponse)(nil),    // 17: exchange.v1.TransferFeeResponse
+	(*TradeFeeRatesResponse)(nil),  // 18: exchange.v1.TradeFeeRatesResponse
+	(*BalancesResponse)(nil),       // 19: exchange.v1.BalancesResponse
+	(*OrdersResponse)(nil),         // 20: exchange.v1.OrdersResponse
+	(*OrderRequest)(nil),           // 21: exchange.v1.OrderRequest
+	(*IsOrderFilledResponse)(nil),  // 22: exchange.v1.IsOrderFilledResponse
+	(*PlaceOrderRequest)(nil),      // 23: exchange.v1.PlaceOrderRequest
+	(*CancelOrderRequest)(nil),     // 24: exchange.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),    // 25: exchange.v1.CancelOrderResponse
+	(*TransferRequest)(nil),        // 26: exchange.v1.TransferRequest
+	(*TransferResponse)(nil),       // 27: exchange.v1.TransferResponse
+	(*AddressRequest)(nil),         // 28: exchange.v1.AddressRequest
+	(*AddressResponse)(nil),        // 29: exchange.v1.AddressResponse
+	nil,                            // 30: exchange.v1.TransferFeeResponse.FeesEntry
+}
+var file_exchange_proto_depIdxs = []int32{
+	3,  // 0: exchange.v1.Board.asks:type_name -> exchange.v1.BoardBar
+	3,  // 1: exchange.v1.Board.bids:type_name -> exchange.v1.BoardBar
+	0,  // 2: exchange.v1.Order.type:type_name -> exchange.v1.OrderType
+	1,  // 3: exchange.v1.CurrencyPairsResponse.pairs:type_name -> exchange.v1.CurrencyPair
+	2,  // 4: exchange.v1.OrderBookTicksResponse.ticks:type_name -> exchange.v1.OrderBookTick
+	30, // 5: exchange.v1.TransferFeeResponse.fees:type_name -> exchange.v1.TransferFeeResponse.FeesEntry
+	8,  // 6: exchange.v1.TradeFeeRatesResponse.fees:type_name -> exchange.v1.TradeFee
+	6,  // 7: exchange.v1.BalancesResponse.balances:type_name -> exchange.v1.Balance
+	7,  // 8: exchange.v1.OrdersResponse.orders:type_name -> exchange.v1.Order
+	7,  // 9: exchange.v1.PlaceOrderRequest.order:type_name -> exchange.v1.Order
+	7,  // 10: exchange.v1.CancelOrderRequest.order:type_name -> exchange.v1.Order
+	9,  // 11: exchange.v1.PublicService.CurrencyPairs:input_type -> exchange.v1.InstanceRequest
+	10, // 12: exchange.v1.PublicService.Rate:input_type -> exchange.v1.PairRequest
+	9,  // 13: exchange.v1.PublicService.OrderBookTicks:input_type -> exchange.v1.InstanceRequest
+	9,  // 14: exchange.v1.PublicService.FrozenCurrency:input_type -> exchange.v1.InstanceRequest
+	10, // 15: exchange.v1.PublicService.Board:input_type -> exchange.v1.PairRequest
+	10, // 16: exchange.v1.PublicService.Precise:input_type -> exchange.v1.PairRequest
+	15, // 17: exchange.v1.PublicService.WatchBoard:input_type -> exchange.v1.WatchBoardRequest
+	16, // 18: exchange.v1.PublicService.WatchTickers:input_type -> exchange.v1.WatchTickersRequest
+	9,  // 19: exchange.v1.PrivateService.TransferFee:input_type -> exchange.v1.InstanceRequest
+	9,  // 20: exchange.v1.PrivateService.TradeFeeRates:input_type -> exchange.v1.InstanceRequest
+	9,  // 21: exchange.v1.PrivateService.Balances:input_type -> exchange.v1.InstanceRequest
+	9,  // 22: exchange.v1.PrivateService.ActiveOrders:input_type -> exchange.v1.InstanceRequest
+	21, // 23: exchange.v1.PrivateService.IsOrderFilled:input_type -> exchange.v1.OrderRequest
+	23, // 24: exchange.v1.PrivateService.PlaceOrder:input_type -> exchange.v1.PlaceOrderRequest
+	24, // 25: exchange.v1.PrivateService.CancelOrder:input_type -> exchange.v1.CancelOrderRequest
+	26, // 26: exchange.v1.PrivateService.Transfer:input_type -> exchange.v1.TransferRequest
+	28, // 27: exchange.v1.PrivateService.Address:input_type -> exchange.v1.AddressRequest
+	11, // 28: exchange.v1.PublicService.CurrencyPairs:output_type -> exchange.v1.CurrencyPairsResponse
+	12, // 29: exchange.v1.PublicService.Rate:output_type -> exchange.v1.RateResponse
+	13, // 30: exchange.v1.PublicService.OrderBookTicks:output_type -> exchange.v1.OrderBookTicksResponse
+	14, // 31: exchange.v1.PublicService.FrozenCurrency:output_type -> exchange.v1.FrozenCurrencyResponse
+	4,  // 32: exchange.v1.PublicService.Board:output_type -> exchange.v1.Board
+	5,  // 33: exchange.v1.PublicService.Precise:output_type -> exchange.v1.Precisions
+	4,  // 34: exchange.v1.PublicService.WatchBoard:output_type -> exchange.v1.Board
+	13, // 35: exchange.v1.PublicService.WatchTickers:output_type -> exchange.v1.OrderBookTicksResponse
+	17, // 36: exchange.v1.PrivateService.TransferFee:output_type -> exchange.v1.TransferFeeResponse
+	18, // 37: exchange.v1.PrivateService.TradeFeeRates:output_type -> exchange.v1.TradeFeeRatesResponse
+	19, // 38: exchange.v1.PrivateService.Balances:output_type -> exchange.v1.BalancesResponse
+	20, // 39: exchange.v1.PrivateService.ActiveOrders:output_type -> exchange.v1.OrdersResponse
+	22, // 40: exchange.v1.PrivateService.IsOrderFilled:output_type -> exchange.v1.IsOrderFilledResponse
+	7,  // 41: exchange.v1.PrivateService.PlaceOrder:output_type -> exchange.v1.Order
+	25, // 42: exchange.v1.PrivateService.CancelOrder:output_type -> exchange.v1.CancelOrderResponse
+	27, // 43: exchange.v1.PrivateService.Transfer:output_type -> exchange.v1.TransferResponse
+	29, // 44: exchange.v1.PrivateService.Address:output_type -> exchange.v1.AddressResponse
+	28, // [28:45] is the sub-list for method output_type
+	11, // [11:28] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_exchange_proto_init() }
+func file_exchange_proto_init() {
+	if File_exchange_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_exchange_proto_rawDesc), len(file_exchange_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_exchange_proto_goTypes,
+		DependencyIndexes: file_exchange_proto_depIdxs,
+		EnumInfos:         file_exchange_proto_enumTypes,
+		MessageInfos:      file_exchange_proto_msgTypes,
+	}.Build()
+	File_exchange_proto = out.File
+	file_exchange_proto_goTypes = nil
+	file_exchange_proto_depIdxs = nil
+}
@@ -0,0 +1,803 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: exchange.proto
+
+// Package exchange mirrors api/public.PublicClient and api/private.PrivateClient.
+// Every request names the config instance it is sent to.
+
+package exchangepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PublicService_CurrencyPairs_FullMethodName  = "/exchange.v1.PublicService/CurrencyPairs"
+	PublicService_Rate_FullMethodName           = "/exchange.v1.PublicService/Rate"
+	PublicService_OrderBookTicks_FullMethodName = "/exchange.v1.PublicService/OrderBookTicks"
+	PublicService_FrozenCurrency_FullMethodName = "/exchange.v1.PublicService/FrozenCurrency"
+	PublicService_Board_FullMethodName          = "/exchange.v1.PublicService/Board"
+	PublicService_Precise_FullMethodName        = "/exchange.v1.PublicService/Precise"
+	PublicService_WatchBoard_FullMethodName     = "/exchange.v1.PublicService/WatchBoard"
+	PublicService_WatchTickers_FullMethodName   = "/exchange.v1.PublicService/WatchTickers"
+)
+
+// PublicServiceClient is the client API for PublicService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PublicServiceClient interface {
+	CurrencyPairs(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*CurrencyPairsResponse, error)
+	Rate(ctx context.Context, in *PairRequest, opts ...grpc.CallOption) (*RateResponse, error)
+	OrderBookTicks(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*OrderBookTicksResponse, error)
+	FrozenCurrency(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*FrozenCurrencyResponse, error)
+	Board(ctx context.Context, in *PairRequest, opts ...grpc.CallOption) (*Board, error)
+	Precise(ctx context.Context, in *PairRequest, opts ...grpc.CallOption) (*Precisions, error)
+	WatchBoard(ctx context.Context, in *WatchBoardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Board], error)
+	WatchTickers(ctx context.Context, in *WatchTickersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBookTicksResponse], error)
+}
+
+type publicServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPublicServiceClient(cc grpc.ClientConnInterface) PublicServiceClient {
+	return &publicServiceClient{cc}
+}
+
+func (c *publicServiceClient) CurrencyPairs(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*CurrencyPairsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CurrencyPairsResponse)
+	err := c.cc.Invoke(ctx, PublicService_CurrencyPairs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicServiceClient) Rate(ctx context.Context, in *PairRequest, opts ...grpc.CallOption) (*RateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateResponse)
+	err := c.cc.Invoke(ctx, PublicService_Rate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicServiceClient) OrderBookTicks(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*OrderBookTicksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderBookTicksResponse)
+	err := c.cc.Invoke(ctx, PublicService_OrderBookTicks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicServiceClient) FrozenCurrency(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*FrozenCurrencyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FrozenCurrencyResponse)
+	err := c.cc.Invoke(ctx, PublicService_FrozenCurrency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicServiceClient) Board(ctx context.Context, in *PairRequest, opts ...grpc.CallOption) (*Board, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Board)
+	err := c.cc.Invoke(ctx, PublicService_Board_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicServiceClient) Precise(ctx context.Context, in *PairRequest, opts ...grpc.CallOption) (*Precisions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Precisions)
+	err := c.cc.Invoke(ctx, PublicService_Precise_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicServiceClient) WatchBoard(ctx context.Context, in *WatchBoardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Board], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PublicService_ServiceDesc.Streams[0], PublicService_WatchBoard_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchBoardRequest, Board]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PublicService_WatchBoardClient = grpc.ServerStreamingClient[Board]
+
+func (c *publicServiceClient) WatchTickers(ctx context.Context, in *WatchTickersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBookTicksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PublicService_ServiceDesc.Streams[1], PublicService_WatchTickers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTickersRequest, OrderBookTicksResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PublicService_WatchTickersClient = grpc.ServerStreamingClient[OrderBookTicksResponse]
+
+// PublicServiceServer is the server API for PublicService service.
+// All implementations must embed UnimplementedPublicServiceServer
+// for forward compatibility.
+type PublicServiceServer interface {
+	CurrencyPairs(context.Context, *InstanceRequest) (*CurrencyPairsResponse, error)
+	Rate(context.Context, *PairRequest) (*RateResponse, error)
+	OrderBookTicks(context.Context, *InstanceRequest) (*OrderBookTicksResponse, error)
+	FrozenCurrency(context.Context, *InstanceRequest) (*FrozenCurrencyResponse, error)
+	Board(context.Context, *PairRequest) (*Board, error)
+	Precise(context.Context, *PairRequest) (*Precisions, error)
+	WatchBoard(*WatchBoardRequest, grpc.ServerStreamingServer[Board]) error
+	WatchTickers(*WatchTickersRequest, grpc.ServerStreamingServer[OrderBookTicksResponse]) error
+	mustEmbedUnimplementedPublicServiceServer()
+}
+
+// UnimplementedPublicServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPublicServiceServer struct{}
+
+func (UnimplementedPublicServiceServer) CurrencyPairs(context.Context, *InstanceRequest) (*CurrencyPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CurrencyPairs not implemented")
+}
+func (UnimplementedPublicServiceServer) Rate(context.Context, *PairRequest) (*RateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rate not implemented")
+}
+func (UnimplementedPublicServiceServer) OrderBookTicks(context.Context, *InstanceRequest) (*OrderBookTicksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OrderBookTicks not implemented")
+}
+func (UnimplementedPublicServiceServer) FrozenCurrency(context.Context, *InstanceRequest) (*FrozenCurrencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FrozenCurrency not implemented")
+}
+func (UnimplementedPublicServiceServer) Board(context.Context, *PairRequest) (*Board, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Board not implemented")
+}
+func (UnimplementedPublicServiceServer) Precise(context.Context, *PairRequest) (*Precisions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Precise not implemented")
+}
+func (UnimplementedPublicServiceServer) WatchBoard(*WatchBoardRequest, grpc.ServerStreamingServer[Board]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBoard not implemented")
+}
+func (UnimplementedPublicServiceServer) WatchTickers(*WatchTickersRequest, grpc.ServerStreamingServer[OrderBookTicksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTickers not implemented")
+}
+func (UnimplementedPublicServiceServer) mustEmbedUnimplementedPublicServiceServer() {}
+func (UnimplementedPublicServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafePublicServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PublicServiceServer will
+// result in compilation errors.
+type UnsafePublicServiceServer interface {
+	mustEmbedUnimplementedPublicServiceServer()
+}
+
+func RegisterPublicServiceServer(s grpc.ServiceRegistrar, srv PublicServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPublicServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PublicService_ServiceDesc, srv)
+}
+
+func _PublicService_CurrencyPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServiceServer).CurrencyPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublicService_CurrencyPairs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServiceServer).CurrencyPairs(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublicService_Rate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServiceServer).Rate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublicService_Rate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServiceServer).Rate(ctx, req.(*PairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublicService_OrderBookTicks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServiceServer).OrderBookTicks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublicService_OrderBookTicks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServiceServer).OrderBookTicks(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublicService_FrozenCurrency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServiceServer).FrozenCurrency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublicService_FrozenCurrency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServiceServer).FrozenCurrency(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublicService_Board_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServiceServer).Board(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublicService_Board_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServiceServer).Board(ctx, req.(*PairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublicService_Precise_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServiceServer).Precise(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublicService_Precise_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServiceServer).Precise(ctx, req.(*PairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublicService_WatchBoard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBoardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PublicServiceServer).WatchBoard(m, &grpc.GenericServerStream[WatchBoardRequest, Board]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PublicService_WatchBoardServer = grpc.ServerStreamingServer[Board]
+
+func _PublicService_WatchTickers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTickersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PublicServiceServer).WatchTickers(m, &grpc.GenericServerStream[WatchTickersRequest, OrderBookTicksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PublicService_WatchTickersServer = grpc.ServerStreamingServer[OrderBookTicksResponse]
+
+// PublicService_ServiceDesc is the grpc.ServiceDesc for PublicService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PublicService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchange.v1.PublicService",
+	HandlerType: (*PublicServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CurrencyPairs",
+			Handler:    _PublicService_CurrencyPairs_Handler,
+		},
+		{
+			MethodName: "Rate",
+			Handler:    _PublicService_Rate_Handler,
+		},
+		{
+			MethodName: "OrderBookTicks",
+			Handler:    _PublicService_OrderBookTicks_Handler,
+		},
+		{
+			MethodName: "FrozenCurrency",
+			Handler:    _PublicService_FrozenCurrency_Handler,
+		},
+		{
+			MethodName: "Board",
+			Handler:    _PublicService_Board_Handler,
+		},
+		{
+			MethodName: "Precise",
+			Handler:    _PublicService_Precise_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchBoard",
+			Handler:       _PublicService_WatchBoard_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchTickers",
+			Handler:       _PublicService_WatchTickers_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "exchange.proto",
+}
+
+const (
+	PrivateService_TransferFee_FullMethodName   = "/exchange.v1.PrivateService/TransferFee"
+	PrivateService_TradeFeeRates_FullMethodName = "/exchange.v1.PrivateService/TradeFeeRates"
+	PrivateService_Balances_FullMethodName      = "/exchange.v1.PrivateService/Balances"
+	PrivateService_ActiveOrders_FullMethodName  = "/exchange.v1.PrivateService/ActiveOrders"
+	PrivateService_IsOrderFilled_FullMethodName = "/exchange.v1.PrivateService/IsOrderFilled"
+	PrivateService_PlaceOrder_FullMethodName    = "/exchange.v1.PrivateService/PlaceOrder"
+	PrivateService_CancelOrder_FullMethodName   = "/exchange.v1.PrivateService/CancelOrder"
+	PrivateService_Transfer_FullMethodName      = "/exchange.v1.PrivateService/Transfer"
+	PrivateService_Address_FullMethodName       = "/exchange.v1.PrivateService/Address"
+)
+
+// PrivateServiceClient is the client API for PrivateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PrivateServiceClient interface {
+	TransferFee(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*TransferFeeResponse, error)
+	TradeFeeRates(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*TradeFeeRatesResponse, error)
+	Balances(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*BalancesResponse, error)
+	ActiveOrders(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*OrdersResponse, error)
+	IsOrderFilled(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*IsOrderFilledResponse, error)
+	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	Address(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AddressResponse, error)
+}
+
+type privateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrivateServiceClient(cc grpc.ClientConnInterface) PrivateServiceClient {
+	return &privateServiceClient{cc}
+}
+
+func (c *privateServiceClient) TransferFee(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*TransferFeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferFeeResponse)
+	err := c.cc.Invoke(ctx, PrivateService_TransferFee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) TradeFeeRates(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*TradeFeeRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TradeFeeRatesResponse)
+	err := c.cc.Invoke(ctx, PrivateService_TradeFeeRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) Balances(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*BalancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalancesResponse)
+	err := c.cc.Invoke(ctx, PrivateService_Balances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) ActiveOrders(ctx context.Context, in *InstanceRequest, opts ...grpc.CallOption) (*OrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrdersResponse)
+	err := c.cc.Invoke(ctx, PrivateService_ActiveOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) IsOrderFilled(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*IsOrderFilledResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IsOrderFilledResponse)
+	err := c.cc.Invoke(ctx, PrivateService_IsOrderFilled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, PrivateService_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, PrivateService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, PrivateService_Transfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateServiceClient) Address(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AddressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressResponse)
+	err := c.cc.Invoke(ctx, PrivateService_Address_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrivateServiceServer is the server API for PrivateService service.
+// All implementations must embed UnimplementedPrivateServiceServer
+// for forward compatibility.
+type PrivateServiceServer interface {
+	TransferFee(context.Context, *InstanceRequest) (*TransferFeeResponse, error)
+	TradeFeeRates(context.Context, *InstanceRequest) (*TradeFeeRatesResponse, error)
+	Balances(context.Context, *InstanceRequest) (*BalancesResponse, error)
+	ActiveOrders(context.Context, *InstanceRequest) (*OrdersResponse, error)
+	IsOrderFilled(context.Context, *OrderRequest) (*IsOrderFilledResponse, error)
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*Order, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	Address(context.Context, *AddressRequest) (*AddressResponse, error)
+	mustEmbedUnimplementedPrivateServiceServer()
+}
+
+// UnimplementedPrivateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPrivateServiceServer struct{}
+
+func (UnimplementedPrivateServiceServer) TransferFee(context.Context, *InstanceRequest) (*TransferFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferFee not implemented")
+}
+func (UnimplementedPrivateServiceServer) TradeFeeRates(context.Context, *InstanceRequest) (*TradeFeeRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TradeFeeRates not implemented")
+}
+func (UnimplementedPrivateServiceServer) Balances(context.Context, *InstanceRequest) (*BalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Balances not implemented")
+}
+func (UnimplementedPrivateServiceServer) ActiveOrders(context.Context, *InstanceRequest) (*OrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActiveOrders not implemented")
+}
+func (UnimplementedPrivateServiceServer) IsOrderFilled(context.Context, *OrderRequest) (*IsOrderFilledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsOrderFilled not implemented")
+}
+func (UnimplementedPrivateServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedPrivateServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedPrivateServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedPrivateServiceServer) Address(context.Context, *AddressRequest) (*AddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Address not implemented")
+}
+func (UnimplementedPrivateServiceServer) mustEmbedUnimplementedPrivateServiceServer() {}
+func (UnimplementedPrivateServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafePrivateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PrivateServiceServer will
+// result in compilation errors.
+type UnsafePrivateServiceServer interface {
+	mustEmbedUnimplementedPrivateServiceServer()
+}
+
+func RegisterPrivateServiceServer(s grpc.ServiceRegistrar, srv PrivateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPrivateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PrivateService_ServiceDesc, srv)
+}
+
+func _PrivateService_TransferFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).TransferFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_TransferFee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).TransferFee(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_TradeFeeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).TradeFeeRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_TradeFeeRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).TradeFeeRates(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_Balances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).Balances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_Balances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).Balances(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_ActiveOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).ActiveOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_ActiveOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).ActiveOrders(ctx, req.(*InstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_IsOrderFilled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).IsOrderFilled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_IsOrderFilled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).IsOrderFilled(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrivateService_Address_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServiceServer).Address(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrivateService_Address_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServiceServer).Address(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PrivateService_ServiceDesc is the grpc.ServiceDesc for PrivateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PrivateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchange.v1.PrivateService",
+	HandlerType: (*PrivateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TransferFee",
+			Handler:    _PrivateService_TransferFee_Handler,
+		},
+		{
+			MethodName: "TradeFeeRates",
+			Handler:    _PrivateService_TradeFeeRates_Handler,
+		},
+		{
+			MethodName: "Balances",
+			Handler:    _PrivateService_Balances_Handler,
+		},
+		{
+			MethodName: "ActiveOrders",
+			Handler:    _PrivateService_ActiveOrders_Handler,
+		},
+		{
+			MethodName: "IsOrderFilled",
+			Handler:    _PrivateService_IsOrderFilled_Handler,
+		},
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _PrivateService_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _PrivateService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _PrivateService_Transfer_Handler,
+		},
+		{
+			MethodName: "Address",
+			Handler:    _PrivateService_Address_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "exchange.proto",
+}
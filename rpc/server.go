@@ -0,0 +1,502 @@
+// Package rpc serves the gRPC services of proto/exchange.proto. It goes through
+// a gateway.Server so the REST and gRPC APIs share clients, tokens and limits.
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/gateway"
+	"github.com/xuyangcn/go-exchange-client/models"
+	pb "github.com/xuyangcn/go-exchange-client/rpc/exchangepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	// minWatchInterval keeps a stream from polling the exchange in a busy loop.
+	minWatchInterval = 100 * time.Millisecond
+)
+
+// Register adds the public and private services backed by gw to s.
+func Register(s *grpc.Server, gw *gateway.Server) {
+	pb.RegisterPublicServiceServer(s, &publicServer{gw: gw})
+	pb.RegisterPrivateServiceServer(s, &privateServer{gw: gw})
+}
+
+var codeOf = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// toStatus converts errors of the gateway and the clients to gRPC errors.
+// Failures of the exchange are reported as Unavailable.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code, ok := codeOf[gateway.StatusCode(err)]
+	if !ok {
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
+
+// authorize checks the "authorization: Bearer <token>" metadata of ctx.
+func authorize(ctx context.Context, gw *gateway.Server, op models.Operation, public bool, instance string) error {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) != 0 {
+			authorization = v[0]
+		}
+	}
+	return toStatus(gw.Authorize(authorization, op, public, instance))
+}
+
+func requirePair(trading string, settlement string) error {
+	if trading == "" || settlement == "" {
+		return status.Error(codes.InvalidArgument, "trading and settlement are required")
+	}
+	return nil
+}
+
+// orderType maps the order type of a request, which must be set.
+func orderType(t pb.OrderType) (models.OrderType, error) {
+	switch t {
+	case pb.OrderType_ASK:
+		return models.Ask, nil
+	case pb.OrderType_BID:
+		return models.Bid, nil
+	case pb.OrderType_ASK_MARKET:
+		return models.AskMarket, nil
+	}
+	return 0, status.Errorf(codes.InvalidArgument, "order type %s is not supported", t)
+}
+
+func protoOrderType(t models.OrderType) pb.OrderType {
+	switch t {
+	case models.Ask:
+		return pb.OrderType_ASK
+	case models.Bid:
+		return pb.OrderType_BID
+	case models.AskMarket:
+		return pb.OrderType_ASK_MARKET
+	}
+	return pb.OrderType_ORDER_TYPE_UNSPECIFIED
+}
+
+func interval(ms int64) time.Duration {
+	if ms <= 0 {
+		return defaultWatchInterval
+	}
+	if d := time.Duration(ms) * time.Millisecond; d > minWatchInterval {
+		return d
+	}
+	return minWatchInterval
+}
+
+type publicServer struct {
+	pb.UnimplementedPublicServiceServer
+	gw *gateway.Server
+}
+
+func (s *publicServer) CurrencyPairs(ctx context.Context, req *pb.InstanceRequest) (*pb.CurrencyPairsResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpCurrencyPairs, true, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PublicClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	pairs, err := cli.CurrencyPairs()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.CurrencyPairsResponse{}
+	for _, p := range pairs {
+		res.Pairs = append(res.Pairs, &pb.CurrencyPair{Trading: p.Trading, Settlement: p.Settlement})
+	}
+	return res, nil
+}
+
+func (s *publicServer) Rate(ctx context.Context, req *pb.PairRequest) (*pb.RateResponse, error) {
+	if err := requirePair(req.Trading, req.Settlement); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.gw, models.OpRate, true, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PublicClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	rate, err := cli.Rate(req.Trading, req.Settlement)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RateResponse{Rate: rate}, nil
+}
+
+func (s *publicServer) OrderBookTicks(ctx context.Context, req *pb.InstanceRequest) (*pb.OrderBookTicksResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpOrderBookTickMap, true, req.Instance); err != nil {
+		return nil, err
+	}
+	return s.ticks(req.Instance)
+}
+
+func (s *publicServer) ticks(instance string) (*pb.OrderBookTicksResponse, error) {
+	cli, err := s.gw.PublicClient(instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	m, err := cli.OrderBookTickMap()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.OrderBookTicksResponse{}
+	for trading, settlements := range m {
+		for settlement, v := range settlements {
+			res.Ticks = append(res.Ticks, &pb.OrderBookTick{
+				Trading:       trading,
+				Settlement:    settlement,
+				BestAskPrice:  v.BestAskPrice,
+				BestAskAmount: v.BestAskAmount,
+				BestBidPrice:  v.BestBidPrice,
+				BestBidAmount: v.BestBidAmount,
+			})
+		}
+	}
+	sort.Slice(res.Ticks, func(i, j int) bool {
+		if res.Ticks[i].Settlement != res.Ticks[j].Settlement {
+			return res.Ticks[i].Settlement < res.Ticks[j].Settlement
+		}
+		return res.Ticks[i].Trading < res.Ticks[j].Trading
+	})
+	return res, nil
+}
+
+func (s *publicServer) FrozenCurrency(ctx context.Context, req *pb.InstanceRequest) (*pb.FrozenCurrencyResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpFrozenCurrency, true, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PublicClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	currencies, err := cli.FrozenCurrency()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.FrozenCurrencyResponse{Currencies: currencies}, nil
+}
+
+func (s *publicServer) Board(ctx context.Context, req *pb.PairRequest) (*pb.Board, error) {
+	if err := requirePair(req.Trading, req.Settlement); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.gw, models.OpBoard, true, req.Instance); err != nil {
+		return nil, err
+	}
+	return s.board(req.Instance, req.Trading, req.Settlement)
+}
+
+func (s *publicServer) board(instance string, trading string, settlement string) (*pb.Board, error) {
+	cli, err := s.gw.PublicClient(instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	board, err := cli.Board(trading, settlement)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.Board{Trading: trading, Settlement: settlement}
+	for _, b := range board.Asks {
+		res.Asks = append(res.Asks, &pb.BoardBar{Price: b.Price, Amount: b.Amount})
+	}
+	for _, b := range board.Bids {
+		res.Bids = append(res.Bids, &pb.BoardBar{Price: b.Price, Amount: b.Amount})
+	}
+	return res, nil
+}
+
+func (s *publicServer) Precise(ctx context.Context, req *pb.PairRequest) (*pb.Precisions, error) {
+	if err := requirePair(req.Trading, req.Settlement); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.gw, models.OpPrecise, true, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PublicClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	p, err := cli.Precise(req.Trading, req.Settlement)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.Precisions{PricePrecision: int32(p.PricePrecision), AmountPrecision: int32(p.AmountPrecision)}, nil
+}
+
+// WatchBoard polls the board and sends it whenever it changes.
+// The token is authorized on every poll, so polls count against its limit.
+func (s *publicServer) WatchBoard(req *pb.WatchBoardRequest, stream grpc.ServerStreamingServer[pb.Board]) error {
+	if err := requirePair(req.Trading, req.Settlement); err != nil {
+		return err
+	}
+	return watch(stream.Context(), interval(req.IntervalMs), func() (proto.Message, error) {
+		if err := authorize(stream.Context(), s.gw, models.OpBoard, true, req.Instance); err != nil {
+			return nil, err
+		}
+		return s.board(req.Instance, req.Trading, req.Settlement)
+	}, func(m proto.Message) error {
+		return stream.Send(m.(*pb.Board))
+	})
+}
+
+// WatchTickers polls the best bids and asks and sends them whenever they change.
+func (s *publicServer) WatchTickers(req *pb.WatchTickersRequest, stream grpc.ServerStreamingServer[pb.OrderBookTicksResponse]) error {
+	return watch(stream.Context(), interval(req.IntervalMs), func() (proto.Message, error) {
+		if err := authorize(stream.Context(), s.gw, models.OpOrderBookTickMap, true, req.Instance); err != nil {
+			return nil, err
+		}
+		return s.ticks(req.Instance)
+	}, func(m proto.Message) error {
+		return stream.Send(m.(*pb.OrderBookTicksResponse))
+	})
+}
+
+// watch sends the result of fetch every d until ctx is done, skipping results
+// equal to the last one sent. A failed fetch ends the stream.
+func watch(ctx context.Context, d time.Duration, fetch func() (proto.Message, error), send func(proto.Message) error) error {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	var last proto.Message
+	for {
+		m, err := fetch()
+		if err != nil {
+			return err
+		}
+		if last == nil || !proto.Equal(last, m) {
+			if err := send(m); err != nil {
+				return err
+			}
+			last = m
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+type privateServer struct {
+	pb.UnimplementedPrivateServiceServer
+	gw *gateway.Server
+}
+
+func (s *privateServer) TransferFee(ctx context.Context, req *pb.InstanceRequest) (*pb.TransferFeeResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpTransferFee, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	fees, err := cli.TransferFee()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.TransferFeeResponse{Fees: fees}, nil
+}
+
+func (s *privateServer) TradeFeeRates(ctx context.Context, req *pb.InstanceRequest) (*pb.TradeFeeRatesResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpTradeFeeRates, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	m, err := cli.TradeFeeRates()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.TradeFeeRatesResponse{}
+	for trading, settlements := range m {
+		for settlement, f := range settlements {
+			res.Fees = append(res.Fees, &pb.TradeFee{Trading: trading, Settlement: settlement, MakerFee: f.MakerFee, TakerFee: f.TakerFee})
+		}
+	}
+	sort.Slice(res.Fees, func(i, j int) bool {
+		return res.Fees[i].Trading+"/"+res.Fees[i].Settlement < res.Fees[j].Trading+"/"+res.Fees[j].Settlement
+	})
+	return res, nil
+}
+
+func (s *privateServer) Balances(ctx context.Context, req *pb.InstanceRequest) (*pb.BalancesResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpCompleteBalances, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	m, err := cli.CompleteBalances()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.BalancesResponse{}
+	for currency, b := range m {
+		res.Balances = append(res.Balances, &pb.Balance{Currency: currency, Available: b.Available, OnOrders: b.OnOrders})
+	}
+	sort.Slice(res.Balances, func(i, j int) bool {
+		return res.Balances[i].Currency < res.Balances[j].Currency
+	})
+	return res, nil
+}
+
+func (s *privateServer) ActiveOrders(ctx context.Context, req *pb.InstanceRequest) (*pb.OrdersResponse, error) {
+	if err := authorize(ctx, s.gw, models.OpActiveOrders, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	orders, err := cli.ActiveOrders()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.OrdersResponse{}
+	for _, o := range orders {
+		res.Orders = append(res.Orders, &pb.Order{
+			Id:         o.ExchangeOrderID,
+			Type:       protoOrderType(o.Type),
+			Trading:    o.Trading,
+			Settlement: o.Settlement,
+			Price:      o.Price,
+			Amount:     o.Amount,
+		})
+	}
+	return res, nil
+}
+
+func (s *privateServer) IsOrderFilled(ctx context.Context, req *pb.OrderRequest) (*pb.IsOrderFilledResponse, error) {
+	if err := requirePair(req.Trading, req.Settlement); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.gw, models.OpIsOrderFilled, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	filled, err := cli.IsOrderFilled(req.Trading, req.Settlement, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.IsOrderFilledResponse{Filled: filled}, nil
+}
+
+func (s *privateServer) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.Order, error) {
+	o := req.Order
+	if o == nil || o.Price <= 0 || o.Amount <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "order with price and amount is required")
+	}
+	if err := requirePair(o.Trading, o.Settlement); err != nil {
+		return nil, err
+	}
+	t, err := orderType(o.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.gw, models.OpOrder, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	id, err := cli.Order(o.Trading, o.Settlement, t, o.Price, o.Amount)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := proto.Clone(o).(*pb.Order)
+	res.Id = id
+	return res, nil
+}
+
+func (s *privateServer) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
+	o := req.Order
+	if o == nil || o.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "order with id is required")
+	}
+	if err := requirePair(o.Trading, o.Settlement); err != nil {
+		return nil, err
+	}
+	t, err := orderType(o.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, s.gw, models.OpCancelOrder, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := cli.CancelOrder(o.Trading, o.Settlement, t, o.Id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CancelOrderResponse{}, nil
+}
+
+func (s *privateServer) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	if req.Currency == "" || req.Address == "" || req.Amount <= 0 || req.AdditionalFee < 0 {
+		return nil, status.Error(codes.InvalidArgument, "currency, address and amount are required")
+	}
+	if err := authorize(ctx, s.gw, models.OpTransfer, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := cli.Transfer(req.Currency, req.Address, req.Amount, req.AdditionalFee); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.TransferResponse{}, nil
+}
+
+func (s *privateServer) Address(ctx context.Context, req *pb.AddressRequest) (*pb.AddressResponse, error) {
+	if req.Currency == "" {
+		return nil, status.Error(codes.InvalidArgument, "currency is required")
+	}
+	if err := authorize(ctx, s.gw, models.OpAddress, false, req.Instance); err != nil {
+		return nil, err
+	}
+	cli, err := s.gw.PrivateClient(req.Instance)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	addr, err := cli.Address(req.Currency)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AddressResponse{Address: addr}, nil
+}
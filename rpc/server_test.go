@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xuyangcn/go-exchange-client/config"
+	"github.com/xuyangcn/go-exchange-client/gateway"
+	"github.com/xuyangcn/go-exchange-client/models"
+	pb "github.com/xuyangcn/go-exchange-client/rpc/exchangepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newFakeBinance() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/exchangeInfo":
+			fmt.Fprint(w, `{"symbols":[{"symbol":"ETHBTC","baseAsset":"ETH","quoteAsset":"BTC","baseAssetPrecision":8,"quotePrecision":6}]}`)
+		case "/api/v1/depth":
+			fmt.Fprint(w, `{"bids":[["0.029","1.5"]],"asks":[["0.031","2"]]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+}
+
+func newTestConn(t *testing.T, baseURL string) (*grpc.ClientConn, func()) {
+	return newLimitedTestConn(t, baseURL, 0, 0)
+}
+
+func newLimitedTestConn(t *testing.T, baseURL string, rps float64, burst int) (*grpc.ClientConn, func()) {
+	c, err := config.Parse([]byte(fmt.Sprintf("exchanges:\n  - name: binance\n    exchange: binance\n    base_url: %s\n", baseURL)), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gateway.New(c, gateway.Options{Tokens: []gateway.Token{
+		gateway.NewToken("research", "secret", []string{gateway.GrantPublic}, nil),
+	}, RequestsPerSecond: rps, Burst: burst})
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	Register(s, gw)
+	go s.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestPublicService(t *testing.T) {
+	fake := newFakeBinance()
+	defer fake.Close()
+	conn, stop := newTestConn(t, fake.URL)
+	defer stop()
+	cli := pb.NewPublicServiceClient(conn)
+
+	pairs, err := cli.CurrencyPairs(withToken("secret"), &pb.InstanceRequest{Instance: "binance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs.Pairs) != 1 || pairs.Pairs[0].Trading != "ETH" {
+		t.Errorf("unexpected pairs %v", pairs.Pairs)
+	}
+
+	board, err := cli.Board(withToken("secret"), &pb.PairRequest{Instance: "binance", Trading: "ETH", Settlement: "BTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Bids) != 1 || board.Bids[0].Price != 0.029 || board.Asks[0].Amount != 2 {
+		t.Errorf("unexpected board %v", board)
+	}
+
+	_, err = cli.Board(withToken("secret"), &pb.PairRequest{Instance: "binance"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	_, err = cli.CurrencyPairs(withToken("wrong"), &pb.InstanceRequest{Instance: "binance"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+	_, err = cli.CurrencyPairs(withToken("secret"), &pb.InstanceRequest{Instance: "nosuchinstance"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+	_, err = pb.NewPrivateServiceClient(conn).Balances(withToken("secret"), &pb.InstanceRequest{Instance: "binance"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestPlaceOrderType(t *testing.T) {
+	conn, stop := newTestConn(t, "http://localhost:4243")
+	defer stop()
+	cli := pb.NewPrivateServiceClient(conn)
+
+	order := &pb.Order{Trading: "ETH", Settlement: "BTC", Price: 0.03, Amount: 1}
+	_, err := cli.PlaceOrder(withToken("secret"), &pb.PlaceOrderRequest{Instance: "binance", Order: order})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without a type, got %v", err)
+	}
+	order.Type = pb.OrderType_BID
+	_, err = cli.PlaceOrder(withToken("secret"), &pb.PlaceOrderRequest{Instance: "binance", Order: order})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+	for _, typ := range []models.OrderType{models.Ask, models.Bid, models.AskMarket} {
+		if got, err := orderType(protoOrderType(typ)); err != nil || got != typ {
+			t.Errorf("expected %v, got %v %v", typ, got, err)
+		}
+	}
+}
+
+func TestWatchBoard(t *testing.T) {
+	fake := newFakeBinance()
+	defer fake.Close()
+	conn, stop := newTestConn(t, fake.URL)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(withToken("secret"))
+	defer cancel()
+	stream, err := pb.NewPublicServiceClient(conn).WatchBoard(ctx, &pb.WatchBoardRequest{
+		Instance: "binance", Trading: "ETH", Settlement: "BTC", IntervalMs: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.Trading != "ETH" || len(board.Asks) != 1 {
+		t.Errorf("unexpected board %v", board)
+	}
+}
+
+func TestWatchBoardRateLimit(t *testing.T) {
+	fake := newFakeBinance()
+	defer fake.Close()
+	conn, stop := newLimitedTestConn(t, fake.URL, 1, 2)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(withToken("secret"), 5*time.Second)
+	defer cancel()
+	stream, err := pb.NewPublicServiceClient(conn).WatchBoard(ctx, &pb.WatchBoardRequest{
+		Instance: "binance", Trading: "ETH", Settlement: "BTC", IntervalMs: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	// the board does not change, so the stream only ends once a poll is limited
+	if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	for ms, want := range map[int64]time.Duration{
+		0:    defaultWatchInterval,
+		-5:   defaultWatchInterval,
+		1:    minWatchInterval,
+		250:  250 * time.Millisecond,
+		5000: 5 * time.Second,
+	} {
+		if got := interval(ms); got != want {
+			t.Errorf("interval(%d) = %s; want %s", ms, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package symbols
+
+import (
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+// Aliases maps the asset names of an exchange to the canonical ones, e.g.
+// STR to XLM. Assets which are not listed have the same name on both sides,
+// and an asset mapped to itself drops a default alias.
+type Aliases map[string]string
+
+// Defaults are the aliases of the exchanges which rename assets.
+var Defaults = map[string]Aliases{
+	"hitbtc":   {"USD": "USDT"},
+	"poloniex": {"STR": "XLM"},
+}
+
+// Canonical returns the canonical name of an asset of the exchange.
+func (a Aliases) Canonical(asset string) string {
+	if c, ok := a[asset]; ok {
+		return c
+	}
+	return asset
+}
+
+// Native returns the name the exchange uses for a canonical asset. When
+// several names map to the asset, the first in alphabetical order is used.
+func (a Aliases) Native(asset string) string {
+	if c, ok := a[asset]; ok && c == asset {
+		return asset
+	}
+	name := ""
+	for native, c := range a {
+		if c == asset && (name == "" || native < name) {
+			name = native
+		}
+	}
+	if name == "" {
+		return asset
+	}
+	return name
+}
+
+// CanonicalPair returns the pair with canonical asset names.
+func (a Aliases) CanonicalPair(p models.CurrencyPair) models.CurrencyPair {
+	return models.CurrencyPair{Trading: a.Canonical(p.Trading), Settlement: a.Canonical(p.Settlement)}
+}
+
+// CanonicalAssets maps the canonical names of the assets of the exchange to
+// the names they are listed by. When several assets have the same canonical
+// name, an asset listed under that name wins over the aliased ones, e.g. BCH
+// over BCC, and aliased ones are ranked alphabetically.
+func (a Aliases) CanonicalAssets(assets []string) map[string]string {
+	m := make(map[string]string, len(assets))
+	for _, native := range assets {
+		c := a.Canonical(native)
+		if prev, ok := m[c]; ok && !preferAsset(c, native, prev) {
+			continue
+		}
+		m[c] = native
+	}
+	return m
+}
+
+// CanonicalPairs is CanonicalAssets for pairs: the pair with the most assets
+// listed under their canonical names wins, then the first in alphabetical
+// order.
+func (a Aliases) CanonicalPairs(pairs []models.CurrencyPair) map[models.CurrencyPair]models.CurrencyPair {
+	m := make(map[models.CurrencyPair]models.CurrencyPair, len(pairs))
+	for _, native := range pairs {
+		c := a.CanonicalPair(native)
+		if prev, ok := m[c]; ok && !preferPair(c, native, prev) {
+			continue
+		}
+		m[c] = native
+	}
+	return m
+}
+
+func preferAsset(canonical, native, prev string) bool {
+	if (native == canonical) != (prev == canonical) {
+		return native == canonical
+	}
+	return native < prev
+}
+
+func preferPair(canonical, native, prev models.CurrencyPair) bool {
+	if n, p := exactAssets(canonical, native), exactAssets(canonical, prev); n != p {
+		return n > p
+	}
+	return native.String() < prev.String()
+}
+
+func exactAssets(canonical, native models.CurrencyPair) int {
+	n := 0
+	if native.Trading == canonical.Trading {
+		n++
+	}
+	if native.Settlement == canonical.Settlement {
+		n++
+	}
+	return n
+}
+
+// Merge returns the aliases of a overridden by b.
+func (a Aliases) Merge(b Aliases) Aliases {
+	m := make(Aliases, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
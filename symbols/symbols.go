@@ -0,0 +1,117 @@
+// Package symbols maps the native symbols of an exchange, such as BTCUSDT,
+// btc_usdt or USDT_BTC, to currency pairs and back.
+package symbols
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+// Format returns the native symbol of a pair.
+type Format func(trading, settlement string) string
+
+// Join formats pairs as TRADING<sep>SETTLEMENT, e.g. Join("") for BTCUSDT.
+func Join(sep string) Format {
+	return func(trading, settlement string) string {
+		return strings.ToUpper(trading) + sep + strings.ToUpper(settlement)
+	}
+}
+
+// Lower formats symbols of f in lower case.
+func Lower(f Format) Format {
+	return func(trading, settlement string) string {
+		return strings.ToLower(f(trading, settlement))
+	}
+}
+
+// Swap formats symbols of f with the settlement first.
+func Swap(f Format) Format {
+	return func(trading, settlement string) string {
+		return f(settlement, trading)
+	}
+}
+
+// Index is the symbols of an exchange. It is built once from the exchange
+// metadata and only read afterwards, so it can be shared by goroutines.
+type Index struct {
+	pairs   map[string]models.CurrencyPair
+	symbols map[models.CurrencyPair]string
+	list    []models.CurrencyPair
+}
+
+func New() *Index {
+	return &Index{
+		pairs:   make(map[string]models.CurrencyPair),
+		symbols: make(map[models.CurrencyPair]string),
+	}
+}
+
+// ErrConflict is returned by Add for a symbol or pair which is already listed
+// with another pair or symbol.
+var ErrConflict = errors.New("conflicting symbol")
+
+// FromPairs returns the index of pairs whose symbols follow format. Symbols
+// built this way can be ambiguous, such as BCNBTC for BCN/BTC and BCNB/TC, so
+// indexes are better built from the native symbols of the exchange metadata.
+func FromPairs(pairs []models.CurrencyPair, format Format) (*Index, error) {
+	ix := New()
+	for _, p := range pairs {
+		if err := ix.Add(format(p.Trading, p.Settlement), p); err != nil {
+			return nil, err
+		}
+	}
+	return ix, nil
+}
+
+// Add lists the pair under its native symbol. Pairs are looked up ignoring
+// case. Adding an entry again is a no-op, while a symbol or pair which is
+// already listed with something else keeps its first entry and Add returns
+// ErrConflict.
+func (ix *Index) Add(symbol string, pair models.CurrencyPair) error {
+	k := key(pair.Trading, pair.Settlement)
+	p, listed := ix.pairs[symbol]
+	if listed && key(p.Trading, p.Settlement) != k {
+		return errors.Wrapf(ErrConflict, "%s is listed as %s, not %s", symbol, p, pair)
+	}
+	s, listed := ix.symbols[k]
+	if listed && s != symbol {
+		return errors.Wrapf(ErrConflict, "%s is listed as %s, not %s", pair, s, symbol)
+	}
+	if listed {
+		return nil
+	}
+	ix.pairs[symbol] = pair
+	ix.symbols[k] = symbol
+	ix.list = append(ix.list, pair)
+	return nil
+}
+
+// Pair returns the pair of a native symbol.
+func (ix *Index) Pair(symbol string) (models.CurrencyPair, bool) {
+	p, ok := ix.pairs[symbol]
+	return p, ok
+}
+
+// Symbol returns the native symbol of a pair.
+func (ix *Index) Symbol(trading, settlement string) (string, error) {
+	s, ok := ix.symbols[key(trading, settlement)]
+	if !ok {
+		return "", errors.Errorf("%s/%s is not listed", trading, settlement)
+	}
+	return s, nil
+}
+
+// Pairs returns the listed pairs in the order they were added.
+func (ix *Index) Pairs() []models.CurrencyPair {
+	return append([]models.CurrencyPair(nil), ix.list...)
+}
+
+func (ix *Index) Len() int {
+	return len(ix.list)
+}
+
+func key(trading, settlement string) models.CurrencyPair {
+	return models.CurrencyPair{Trading: strings.ToUpper(trading), Settlement: strings.ToUpper(settlement)}
+}
@@ -0,0 +1,126 @@
+package symbols
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/xuyangcn/go-exchange-client/models"
+)
+
+func TestFormat(t *testing.T) {
+	for _, c := range []struct {
+		format Format
+		want   string
+	}{
+		{Join(""), "BTCUSDT"},
+		{Join("-"), "BTC-USDT"},
+		{Lower(Join("_")), "btc_usdt"},
+		{Swap(Join("_")), "USDT_BTC"},
+	} {
+		if got := c.format("btc", "USDT"); got != c.want {
+			t.Errorf("got %s; want %s", got, c.want)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	if _, err := FromPairs([]models.CurrencyPair{
+		{Trading: "BCN", Settlement: "BTC"},
+		{Trading: "BCNB", Settlement: "TC"},
+	}, Join("")); errors.Cause(err) != ErrConflict {
+		t.Errorf("got %v; want the ambiguous BCNBTC reported", err)
+	}
+
+	ix := New()
+	for symbol, p := range map[string]models.CurrencyPair{
+		"ETHBTC": {Trading: "ETH", Settlement: "BTC"},
+		"BCNBTC": {Trading: "BCN", Settlement: "BTC"},
+	} {
+		if err := ix.Add(symbol, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ix.Len() != 2 {
+		t.Fatalf("got %d pairs; want 2", ix.Len())
+	}
+	if p, ok := ix.Pair("BCNBTC"); !ok || p.Trading != "BCN" {
+		t.Errorf("got %+v; want BCN/BTC", p)
+	}
+	if _, ok := ix.Pair("ethbtc"); ok {
+		t.Error("want symbols to be matched exactly")
+	}
+	if s, err := ix.Symbol("eth", "btc"); err != nil || s != "ETHBTC" {
+		t.Errorf("got %s, %v; want ETHBTC", s, err)
+	}
+	if _, err := ix.Symbol("XRP", "BTC"); err == nil {
+		t.Error("want an error for a pair which is not listed")
+	}
+
+	if err := ix.Add("ETHBTC", models.CurrencyPair{Trading: "eth", Settlement: "btc"}); err != nil {
+		t.Errorf("got %v; want adding an entry again to be a no-op", err)
+	}
+	if err := ix.Add("ethbtc", models.CurrencyPair{Trading: "eth", Settlement: "btc"}); errors.Cause(err) != ErrConflict {
+		t.Errorf("got %v; want a second symbol of ETH/BTC reported", err)
+	}
+	if err := ix.Add("BCNBTC", models.CurrencyPair{Trading: "BCNB", Settlement: "TC"}); errors.Cause(err) != ErrConflict {
+		t.Errorf("got %v; want a second pair of BCNBTC reported", err)
+	}
+	if p, _ := ix.Pair("BCNBTC"); p.Trading != "BCN" || ix.Len() != 2 {
+		t.Errorf("got %+v; want conflicts to keep the first entry", p)
+	}
+	for _, p := range ix.Pairs() {
+		s, err := ix.Symbol(p.Trading, p.Settlement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back, _ := ix.Pair(s); back != p {
+			t.Errorf("got %+v from %s; want %+v", back, s, p)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	a := Defaults["poloniex"].Merge(Aliases{"XBT": "BTC"})
+	if a.Canonical("STR") != "XLM" || a.Canonical("XBT") != "BTC" || a.Canonical("ETH") != "ETH" {
+		t.Errorf("got %v; want STR and XBT renamed", a)
+	}
+	if a.Native("XLM") != "STR" || a.Native("ETH") != "ETH" {
+		t.Errorf("got %s; want the name of the exchange", a.Native("XLM"))
+	}
+	if len(Defaults["poloniex"]) != 1 {
+		t.Error("want Merge to leave the defaults alone")
+	}
+	a = a.Merge(Aliases{"STR": "STR"})
+	if a.Canonical("STR") != "STR" || a.Native("STR") != "STR" {
+		t.Error("want an asset mapped to itself to drop its alias")
+	}
+	if p := a.CanonicalPair(models.CurrencyPair{Trading: "XBT", Settlement: "USDT"}); p.String() != "BTC/USDT" {
+		t.Errorf("got %s; want BTC/USDT", p)
+	}
+}
+
+func TestAliasCollisions(t *testing.T) {
+	a := Aliases{"BCC": "BCH", "BCHABC": "BCH", "USD": "USDT"}
+	for i := 0; i < 10; i++ {
+		if n := a.Native("BCH"); n != "BCC" {
+			t.Fatalf("got %s; want the first name in alphabetical order", n)
+		}
+	}
+	assets := a.CanonicalAssets([]string{"BCHABC", "BCH", "BCC", "USD"})
+	if assets["BCH"] != "BCH" || assets["USDT"] != "USD" || len(assets) != 2 {
+		t.Errorf("got %v; want BCH listed as BCH and USDT as USD", assets)
+	}
+	if assets = a.CanonicalAssets([]string{"BCHABC", "BCC"}); assets["BCH"] != "BCC" {
+		t.Errorf("got %v; want BCC", assets)
+	}
+	pairs := a.CanonicalPairs([]models.CurrencyPair{
+		{Trading: "BCC", Settlement: "USD"},
+		{Trading: "BCC", Settlement: "USDT"},
+		{Trading: "BCH", Settlement: "USD"},
+		{Trading: "BCHABC", Settlement: "USDT"},
+	})
+	want := models.CurrencyPair{Trading: "BCC", Settlement: "USDT"}
+	if p := pairs[models.CurrencyPair{Trading: "BCH", Settlement: "USDT"}]; p != want || len(pairs) != 1 {
+		t.Errorf("got %v; want BCH/USDT listed as %s", pairs, want)
+	}
+}
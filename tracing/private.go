@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PrivateClient is a private.PrivateClient recording a span for every call.
+type PrivateClient struct {
+	ctx      context.Context
+	client   private.PrivateClient
+	t        *Tracer
+	calls    *calls
+	exchange string
+}
+
+// NewPrivateClient builds a client of the exchange like private.NewClient.
+func (t *Tracer) NewPrivateClient(mode private.ClientMode, exchangeName string, apikey func() (string, error), seckey func() (string, error), opts ...options.Option) (*PrivateClient, error) {
+	name, err := private.Resolve(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+	c := &calls{}
+	cli, err := private.NewClient(mode, name, apikey, seckey, append(opts, options.WithMiddleware(t.middleware(c)))...)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateClient{ctx: context.Background(), client: cli, t: t, calls: c, exchange: name}, nil
+}
+
+// WithContext returns a client whose spans are children of the span in ctx.
+func (c *PrivateClient) WithContext(ctx context.Context) *PrivateClient {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
+
+func (c *PrivateClient) start(method string, attrs ...attribute.KeyValue) func(*error) {
+	return c.t.start(c.ctx, c.calls, c.exchange, true, method, attrs...)
+}
+
+func (c *PrivateClient) TransferFee() (fees map[string]float64, err error) {
+	defer c.start("TransferFee")(&err)
+	return c.client.TransferFee()
+}
+
+func (c *PrivateClient) TradeFeeRates() (fees map[string]map[string]private.TradeFee, err error) {
+	defer c.start("TradeFeeRates")(&err)
+	return c.client.TradeFeeRates()
+}
+
+func (c *PrivateClient) TradeFeeRate(trading string, settlement string) (fee private.TradeFee, err error) {
+	defer c.start("TradeFeeRate", pair(trading, settlement))(&err)
+	return c.client.TradeFeeRate(trading, settlement)
+}
+
+func (c *PrivateClient) Balances() (balances map[string]float64, err error) {
+	defer c.start("Balances")(&err)
+	return c.client.Balances()
+}
+
+func (c *PrivateClient) CompleteBalances() (balances map[string]*models.Balance, err error) {
+	defer c.start("CompleteBalances")(&err)
+	return c.client.CompleteBalances()
+}
+
+func (c *PrivateClient) CompleteBalance(coin string) (balance *models.Balance, err error) {
+	defer c.start("CompleteBalance", CurrencyKey.String(coin))(&err)
+	return c.client.CompleteBalance(coin)
+}
+
+func (c *PrivateClient) ActiveOrders() (orders []*models.Order, err error) {
+	defer c.start("ActiveOrders")(&err)
+	return c.client.ActiveOrders()
+}
+
+func (c *PrivateClient) IsOrderFilled(trading string, settlement string, orderNumber string) (filled bool, err error) {
+	defer c.start("IsOrderFilled", pair(trading, settlement), OrderKey.String(orderNumber))(&err)
+	return c.client.IsOrderFilled(trading, settlement, orderNumber)
+}
+
+func (c *PrivateClient) Order(trading string, settlement string,
+	ordertype models.OrderType, price float64, amount float64) (orderNumber string, err error) {
+	defer c.start("Order", pair(trading, settlement))(&err)
+	return c.client.Order(trading, settlement, ordertype, price, amount)
+}
+
+func (c *PrivateClient) CancelOrder(trading string, settlement string,
+	ordertype models.OrderType, orderNumber string) (err error) {
+	defer c.start("CancelOrder", pair(trading, settlement), OrderKey.String(orderNumber))(&err)
+	return c.client.CancelOrder(trading, settlement, ordertype, orderNumber)
+}
+
+func (c *PrivateClient) Transfer(typ string, addr string,
+	amount float64, additionalFee float64) (err error) {
+	defer c.start("Transfer", CurrencyKey.String(typ))(&err)
+	return c.client.Transfer(typ, addr, amount, additionalFee)
+}
+
+func (c *PrivateClient) Address(currency string) (addr string, err error) {
+	defer c.start("Address", CurrencyKey.String(currency))(&err)
+	return c.client.Address(currency)
+}
+
+func (c *PrivateClient) Capabilities() models.Capabilities {
+	return c.client.Capabilities()
+}
+
+func (c *PrivateClient) Warmup(ctx context.Context) (err error) {
+	defer c.t.start(ctx, c.calls, c.exchange, true, "Warmup")(&err)
+	return c.client.Warmup(ctx)
+}
+
+func (c *PrivateClient) SetTransport(transport http.RoundTripper) error {
+	return c.client.SetTransport(transport)
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/public"
+	"github.com/xuyangcn/go-exchange-client/models"
+	"github.com/xuyangcn/go-exchange-client/symbols"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PublicClient is a public.PublicClient recording a span for every call.
+type PublicClient struct {
+	ctx      context.Context
+	client   public.PublicClient
+	t        *Tracer
+	calls    *calls
+	exchange string
+}
+
+// NewPublicClient builds a client of the exchange like public.NewClient.
+func (t *Tracer) NewPublicClient(exchangeName string, opts ...options.Option) (*PublicClient, error) {
+	name, err := public.Resolve(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+	c := &calls{}
+	cli, err := public.NewClient(name, append(opts, options.WithMiddleware(t.middleware(c)))...)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicClient{ctx: context.Background(), client: cli, t: t, calls: c, exchange: name}, nil
+}
+
+// WithContext returns a client whose spans are children of the span in ctx.
+func (c *PublicClient) WithContext(ctx context.Context) *PublicClient {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
+
+func (c *PublicClient) start(method string, attrs ...attribute.KeyValue) func(*error) {
+	return c.t.start(c.ctx, c.calls, c.exchange, false, method, attrs...)
+}
+
+func (c *PublicClient) CurrencyPairs() (pairs []models.CurrencyPair, err error) {
+	defer c.start("CurrencyPairs")(&err)
+	return c.client.CurrencyPairs()
+}
+
+func (c *PublicClient) Rate(trading string, settlement string) (rate float64, err error) {
+	defer c.start("Rate", pair(trading, settlement))(&err)
+	return c.client.Rate(trading, settlement)
+}
+
+func (c *PublicClient) OrderBookTickMap() (m map[string]map[string]models.OrderBookTick, err error) {
+	defer c.start("OrderBookTickMap")(&err)
+	return c.client.OrderBookTickMap()
+}
+
+func (c *PublicClient) Snapshot() (snapshot *models.MarketSnapshot, err error) {
+	defer c.start("Snapshot")(&err)
+	return c.client.Snapshot()
+}
+
+func (c *PublicClient) Symbols() (ix *symbols.Index, err error) {
+	defer c.start("Symbols")(&err)
+	return c.client.Symbols()
+}
+
+func (c *PublicClient) FrozenCurrency() (currencies []string, err error) {
+	defer c.start("FrozenCurrency")(&err)
+	return c.client.FrozenCurrency()
+}
+
+func (c *PublicClient) Board(trading string, settlement string) (board *models.Board, err error) {
+	defer c.start("Board", pair(trading, settlement))(&err)
+	return c.client.Board(trading, settlement)
+}
+
+func (c *PublicClient) Precise(trading string, settlement string) (precisions *models.Precisions, err error) {
+	defer c.start("Precise", pair(trading, settlement))(&err)
+	return c.client.Precise(trading, settlement)
+}
+
+func (c *PublicClient) Capabilities() models.Capabilities {
+	return c.client.Capabilities()
+}
+
+func (c *PublicClient) Warmup(ctx context.Context) (err error) {
+	defer c.t.start(ctx, c.calls, c.exchange, false, "Warmup")(&err)
+	return c.client.Warmup(ctx)
+}
+
+func (c *PublicClient) SetTransport(transport http.RoundTripper) error {
+	return c.client.SetTransport(transport)
+}
@@ -0,0 +1,180 @@
+// Package tracing records OpenTelemetry spans of client calls and of the
+// requests they send.
+//
+//	t := tracing.New(otel.GetTracerProvider())
+//	cli, err := t.NewPrivateClient(private.PROJECT, "huobi", apikey, seckey)
+//	balances, err := cli.WithContext(ctx).CompleteBalances()
+//
+// The methods of the clients don't take a context, so the wrappers keep the
+// one given to WithContext. Spans of the requests sent during a call are its
+// children. When several calls of one client are in flight, a request can't
+// be told apart and its span is linked to all of them instead.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/xuyangcn/go-exchange-client/tracing"
+
+// Attribute keys of the spans.
+const (
+	ExchangeKey = attribute.Key("exchange.name")
+	ScopeKey    = attribute.Key("exchange.scope")
+	PairKey     = attribute.Key("exchange.pair")
+	CurrencyKey = attribute.Key("exchange.currency")
+	OrderKey    = attribute.Key("exchange.order_id")
+	EndpointKey = attribute.Key("exchange.endpoint")
+)
+
+// Tracer creates the spans of clients.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New returns a Tracer using tp, or the global provider if tp is nil.
+func New(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+// Option records a span for every request of a client built with it.
+// Use NewPublicClient or NewPrivateClient to also record the calls.
+func (t *Tracer) Option() options.Option {
+	return options.WithMiddleware(t.middleware(nil))
+}
+
+func (t *Tracer) middleware(c *calls) options.Middleware {
+	return func(o *options.Options, next http.RoundTripper) http.RoundTripper {
+		return &transport{t: t, o: o, calls: c, next: next}
+	}
+}
+
+// start starts the span of a call, which ends when the returned function is
+// called with the error of the call.
+func (t *Tracer) start(ctx context.Context, c *calls, exchange string, private bool, method string, attrs ...attribute.KeyValue) func(*error) {
+	attrs = append(attrs, ExchangeKey.String(exchange), ScopeKey.String(scope(private)))
+	ctx, span := t.tracer.Start(ctx, scope(private)+"."+method, trace.WithAttributes(attrs...))
+	c.push(ctx)
+	return func(err *error) {
+		c.remove(ctx)
+		if err != nil && *err != nil {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
+
+func pair(trading, settlement string) attribute.KeyValue {
+	return PairKey.String(trading + "/" + settlement)
+}
+
+func scope(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
+
+// calls are the contexts of the calls in flight on one client.
+type calls struct {
+	mu     sync.Mutex
+	active []context.Context
+}
+
+func (c *calls) push(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active = append(c.active, ctx)
+}
+
+func (c *calls) remove(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, a := range c.active {
+		if a == ctx {
+			c.active = append(c.active[:i], c.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// parent returns the parent of a request span, or links to the calls when
+// it is ambiguous.
+func (c *calls) parent(ctx context.Context) (context.Context, []trace.Link) {
+	if c == nil {
+		return ctx, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch len(c.active) {
+	case 0:
+		return ctx, nil
+	case 1:
+		return c.active[0], nil
+	}
+	links := make([]trace.Link, 0, len(c.active))
+	for _, a := range c.active {
+		links = append(links, trace.LinkFromContext(a))
+	}
+	return ctx, links
+}
+
+type transport struct {
+	t     *Tracer
+	o     *options.Options
+	calls *calls
+	next  http.RoundTripper
+}
+
+// RoundTrip records a span until the response headers are received. The
+// trace context is not sent to the exchange.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, links := req.Context(), []trace.Link(nil)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx, links = t.calls.parent(ctx)
+	}
+	endpoint := metrics.Endpoint(req)
+	_, span := t.t.tracer.Start(ctx, "HTTP "+req.Method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			ExchangeKey.String(t.o.Exchange),
+			ScopeKey.String(scope(t.o.Private)),
+			EndpointKey.String(endpoint),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+		))
+	defer span.End()
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	if res.StatusCode >= 400 {
+		span.SetStatus(codes.Error, strconv.Itoa(res.StatusCode)+" "+http.StatusText(res.StatusCode))
+	}
+	return res, nil
+}
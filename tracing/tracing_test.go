@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xuyangcn/go-exchange-client/api/options"
+	"github.com/xuyangcn/go-exchange-client/api/private"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	sr := tracetest.NewSpanRecorder()
+	return sr, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+}
+
+func attr(s sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestPublicClient(t *testing.T) {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/exchangeInfo":
+			fmt.Fprint(w, `{"symbols":[{"symbol":"ETHBTC","baseAsset":"ETH","quoteAsset":"BTC"}]}`)
+		case "/api/v1/depth":
+			fmt.Fprint(w, `{"bids":[["0.029","1.5"]],"asks":[["0.031","2"]]}`)
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer fake.Close()
+
+	sr, tp := newRecorder()
+	tr := New(tp)
+	cli, err := tr.NewPublicClient("binance", options.WithBaseURL(fake.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cycle := tp.Tracer("test").Start(context.Background(), "cycle")
+	if _, err := cli.WithContext(ctx).Board("ETH", "BTC"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.WithContext(ctx).Rate("ETH", "BTC"); err == nil {
+		t.Fatal("expected an error")
+	}
+	cycle.End()
+
+	spans := sr.Ended()
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+	board, ok := byName["public.Board"]
+	if !ok {
+		t.Fatalf("no Board span in %v", spans)
+	}
+	if board.Parent().SpanID() != cycle.SpanContext().SpanID() {
+		t.Error("Board span is not a child of the caller span")
+	}
+	if attr(board, PairKey) != "ETH/BTC" || attr(board, ExchangeKey) != "binance" {
+		t.Errorf("unexpected attributes %v", board.Attributes())
+	}
+	depth, ok := byName["HTTP GET /api/v1/depth"]
+	if !ok {
+		t.Fatalf("no depth request span in %v", spans)
+	}
+	if depth.Parent().SpanID() != board.SpanContext().SpanID() {
+		t.Error("request span is not a child of the Board span")
+	}
+	if attr(depth, EndpointKey) != "/api/v1/depth" || attr(depth, "http.response.status_code") != "200" {
+		t.Errorf("unexpected attributes %v", depth.Attributes())
+	}
+
+	rate := byName["public.Rate"]
+	if rate == nil || rate.Status().Code != codes.Error {
+		t.Errorf("expected a failed Rate span, got %v", rate)
+	}
+	ticker := byName["HTTP GET /api/v1/ticker/24hr"]
+	if ticker == nil || ticker.Status().Code != codes.Error || ticker.Parent().SpanID() != rate.SpanContext().SpanID() {
+		t.Errorf("expected a failed request span under Rate, got %v", ticker)
+	}
+}
+
+func TestPrivateClient(t *testing.T) {
+	sr, tp := newRecorder()
+	key := func() (string, error) { return "", nil }
+	cli, err := New(tp).NewPrivateClient(private.TEST, "binance", key, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.CompleteBalances(); err != nil {
+		t.Fatal(err)
+	}
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "private.CompleteBalances" || attr(spans[0], ScopeKey) != "private" {
+		t.Errorf("unexpected spans %v", spans)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	_, tp := newRecorder()
+	tracer := tp.Tracer("test")
+	c := &calls{}
+	first, a := tracer.Start(context.Background(), "a")
+	second, b := tracer.Start(context.Background(), "b")
+
+	if ctx, links := c.parent(context.Background()); ctx != context.Background() || links != nil {
+		t.Error("expected no parent without calls")
+	}
+	c.push(first)
+	if ctx, _ := c.parent(context.Background()); ctx != first {
+		t.Error("expected the only call as parent")
+	}
+	c.push(second)
+	if _, links := c.parent(context.Background()); len(links) != 2 ||
+		links[0].SpanContext.SpanID() != a.SpanContext().SpanID() || links[1].SpanContext.SpanID() != b.SpanContext().SpanID() {
+		t.Errorf("expected links to both calls, got %v", links)
+	}
+	c.remove(first)
+	if ctx, _ := c.parent(context.Background()); ctx != second {
+		t.Error("expected the remaining call as parent")
+	}
+}